@@ -10,6 +10,7 @@ import (
 	"gaetanjaminon/GoTuto/internal/billing/config"
 	"gaetanjaminon/GoTuto/internal/billing/database"
 	"gaetanjaminon/GoTuto/internal/billing/api"
+	"gaetanjaminon/GoTuto/internal/billing/services"
 	
 	"github.com/gin-gonic/gin"
 	"gorm.io/gorm"
@@ -124,6 +125,9 @@ func setupRouter(cfg *config.BillingConfig, db *gorm.DB) *gin.Engine {
 		c.JSON(200, health)
 	})
 	
+	// Tax IDs are checked offline; swap in a registry-backed verifier (e.g. VIES) here
+	taxIDVerifier := services.NewOfflineTaxIDVerifier()
+	
 	// API routes
 	apiGroup := router.Group("/api/v1")
 	{
@@ -131,11 +135,34 @@ func setupRouter(cfg *config.BillingConfig, db *gorm.DB) *gin.Engine {
 		clients := apiGroup.Group("/clients")
 		{
 			clients.GET("", api.GetClients(db))
+			clients.GET("/duplicates", api.GetClientDuplicates(db))
+			clients.GET("/tags", api.GetClientTags(db))
+			clients.POST("/import", api.ImportClients(db, taxIDVerifier))
 			clients.GET("/:id", api.GetClient(db))
-			clients.POST("", api.CreateClient(db))
-			clients.PUT("/:id", api.UpdateClient(db))
+			clients.POST("", api.CreateClient(db, taxIDVerifier))
+			clients.PUT("/:id", api.UpdateClient(db, taxIDVerifier))
 			clients.DELETE("/:id", api.DeleteClient(db))
-			clients.GET("/:client_id/invoices", api.GetInvoicesByClient(db))
+			clients.GET("/:id/invoices", api.GetInvoicesByClient(db))
+			clients.POST("/:id/tax-id/verify", api.VerifyClientTaxID(db, taxIDVerifier))
+			
+			// Structured addresses (one billing and one shipping address per client)
+			clients.GET("/:id/addresses", api.GetClientAddresses(db))
+			clients.PUT("/:id/addresses/:type", api.SetClientAddress(db))
+			clients.DELETE("/:id/addresses/:type", api.DeleteClientAddress(db))
+			
+			// Contacts with roles
+			clients.GET("/:id/contacts", api.GetClientContacts(db))
+			clients.POST("/:id/contacts", api.CreateClientContact(db))
+			clients.PUT("/:id/contacts/:contact_id", api.UpdateClientContact(db))
+			clients.DELETE("/:id/contacts/:contact_id", api.DeleteClientContact(db))
+			
+			// Duplicate merging (:id is the surviving client)
+			clients.POST("/:id/merge", api.MergeClients(db))
+			clients.GET("/:id/merges", api.GetClientMerges(db))
+			
+			// GDPR subject access and erasure
+			clients.GET("/:id/export", api.ExportClientData(db))
+			clients.POST("/:id/erase", api.EraseClient(db))
 		}
 		
 		// Invoice routes
@@ -143,9 +170,73 @@ func setupRouter(cfg *config.BillingConfig, db *gorm.DB) *gin.Engine {
 		{
 			invoices.GET("", api.GetInvoices(db))
 			invoices.GET("/:id", api.GetInvoice(db))
-			invoices.POST("", api.CreateInvoice(db))
+			invoices.POST("", api.CreateInvoice(db, cfg.Invoice))
 			invoices.PUT("/:id", api.UpdateInvoice(db))
 			invoices.DELETE("/:id", api.DeleteInvoice(db))
+			invoices.GET("/:id/pdf", api.DownloadInvoicePDF(db, cfg.Invoice))
+			invoices.GET("/:id/ubl", api.DownloadInvoiceUBL(db, cfg.Invoice))
+		}
+		
+		// Client custom field definitions
+		customFields := apiGroup.Group("/custom-fields")
+		{
+			customFields.GET("", api.GetCustomFields(db))
+			customFields.GET("/:id", api.GetCustomField(db))
+			customFields.POST("", api.CreateCustomField(db))
+			customFields.PUT("/:id", api.UpdateCustomField(db))
+			customFields.DELETE("/:id", api.DeleteCustomField(db))
+		}
+		
+		// Saved client segments
+		segments := apiGroup.Group("/segments")
+		{
+			segments.GET("", api.GetSegments(db))
+			segments.GET("/:id", api.GetSegment(db))
+			segments.POST("", api.CreateSegment(db))
+			segments.PUT("/:id", api.UpdateSegment(db))
+			segments.DELETE("/:id", api.DeleteSegment(db))
+		}
+		
+		// Payment term routes
+		paymentTerms := apiGroup.Group("/payment-terms")
+		{
+			paymentTerms.GET("", api.GetPaymentTerms(db))
+			paymentTerms.GET("/:id", api.GetPaymentTerm(db))
+			paymentTerms.POST("", api.CreatePaymentTerm(db))
+			paymentTerms.PUT("/:id", api.UpdatePaymentTerm(db))
+			paymentTerms.DELETE("/:id", api.DeletePaymentTerm(db))
+		}
+		
+		// Client self-service portal, authenticated by signed access links
+		if cfg.Portal.Enabled() {
+			portalSigner := services.NewPortalTokenSigner(cfg.Portal.SigningSecret)
+			
+			clients.GET("/:id/portal-tokens", api.GetPortalTokens(db))
+			clients.POST("/:id/portal-tokens", api.CreatePortalToken(db, cfg.Portal, portalSigner))
+			clients.DELETE("/:id/portal-tokens/:token_id", api.RevokePortalToken(db))
+			
+			portal := apiGroup.Group("/portal", api.PortalAuth(db, portalSigner))
+			{
+				portal.GET("/client", api.GetPortalClient(db))
+				portal.GET("/invoices", api.GetPortalInvoices(db))
+				portal.GET("/invoices/:id", api.GetPortalInvoice(db))
+				portal.GET("/invoices/:id/pdf", api.DownloadPortalInvoicePDF(db, cfg.Invoice))
+				portal.GET("/invoices/:id/ubl", api.DownloadPortalInvoiceUBL(db, cfg.Invoice))
+				portal.GET("/statement", api.GetPortalStatement(db, cfg.Invoice))
+				portal.GET("/balance", api.GetPortalBalance(db, cfg.Invoice))
+			}
+		} else {
+			log.Println("Client portal disabled: portal.signing_secret is not set")
+		}
+		
+		// Discount code routes
+		discountCodes := apiGroup.Group("/discount-codes")
+		{
+			discountCodes.GET("", api.GetDiscountCodes(db))
+			discountCodes.GET("/:id", api.GetDiscountCode(db))
+			discountCodes.POST("", api.CreateDiscountCode(db))
+			discountCodes.PUT("/:id", api.UpdateDiscountCode(db))
+			discountCodes.DELETE("/:id", api.DeleteDiscountCode(db))
 		}
 	}
 	
@@ -0,0 +1,160 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"mime/multipart"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"gaetanjaminon/GoTuto/internal/billing/services"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var (
+	rootCmd = &cobra.Command{
+		Use:   "billing-importer",
+		Short: "Bulk client import tool for billing service",
+		Long:  `A CLI tool to import billing clients from CSV or vCard files through the billing API.`,
+	}
+)
+
+func init() {
+	cobra.OnInitialize(initConfig)
+
+	rootCmd.PersistentFlags().String("api-url", "http://localhost:8080", "Billing API base URL")
+	viper.BindPFlag("importer.api_url", rootCmd.PersistentFlags().Lookup("api-url"))
+
+	clientsCmd.Flags().String("format", "", "File format: csv or vcard (default: from file extension)")
+	clientsCmd.Flags().StringToString("map", nil, "CSV column mapping as field=Column, e.g. name=Company,email=E-mail")
+	clientsCmd.Flags().Bool("dry-run", false, "Validate and report without saving anything")
+	clientsCmd.Flags().Bool("json", false, "Print the full report as JSON")
+
+	rootCmd.AddCommand(clientsCmd)
+}
+
+var clientsCmd = &cobra.Command{
+	Use:   "clients FILE",
+	Short: "Import clients from a CSV or vCard file",
+	Long: `Import clients from a CSV or vCard file. Each record is validated like POST /api/v1/clients;
+records whose email matches an existing client update it, the others create new clients.
+
+CSV columns are matched by field name unless mapped with --map. Supported fields:
+  ` + strings.Join(services.ClientImportFields, ", "),
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		format, _ := cmd.Flags().GetString("format")
+		mapping, _ := cmd.Flags().GetStringToString("map")
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+		asJSON, _ := cmd.Flags().GetBool("json")
+
+		report, err := uploadClients(args[0], format, mapping, dryRun)
+		if err != nil {
+			log.Fatal("Import failed: ", err)
+		}
+
+		if asJSON {
+			encoder := json.NewEncoder(os.Stdout)
+			encoder.SetIndent("", "  ")
+			encoder.Encode(report)
+		} else {
+			printReport(report)
+		}
+
+		if report.Rejected > 0 {
+			os.Exit(2)
+		}
+	},
+}
+
+func initConfig() {
+	// Environment variables with BILLING prefix, e.g. BILLING_IMPORTER_API_URL
+	viper.SetEnvPrefix("BILLING")
+	viper.SetEnvKeyReplacer(strings.NewReplacer(".", "_"))
+	viper.AutomaticEnv()
+}
+
+// uploadClients posts the file to the import endpoint and decodes the report
+func uploadClients(path, format string, mapping map[string]string, dryRun bool) (*services.ClientImportReport, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	part, err := writer.CreateFormFile("file", filepath.Base(path))
+	if err != nil {
+		return nil, err
+	}
+	if _, err := io.Copy(part, file); err != nil {
+		return nil, err
+	}
+	if format != "" {
+		writer.WriteField("format", format)
+	}
+	if len(mapping) > 0 {
+		encoded, err := json.Marshal(mapping)
+		if err != nil {
+			return nil, err
+		}
+		writer.WriteField("mapping", string(encoded))
+	}
+	writer.WriteField("dry_run", fmt.Sprint(dryRun))
+	if err := writer.Close(); err != nil {
+		return nil, err
+	}
+
+	url := strings.TrimRight(viper.GetString("importer.api_url"), "/") + "/api/v1/clients/import"
+	client := &http.Client{Timeout: 5 * time.Minute}
+	resp, err := client.Post(url, writer.FormDataContentType(), &body)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		var apiErr struct {
+			Error string `json:"error"`
+		}
+		json.NewDecoder(resp.Body).Decode(&apiErr)
+		return nil, fmt.Errorf("%s: %s", resp.Status, apiErr.Error)
+	}
+
+	var report services.ClientImportReport
+	if err := json.NewDecoder(resp.Body).Decode(&report); err != nil {
+		return nil, fmt.Errorf("invalid response: %w", err)
+	}
+	return &report, nil
+}
+
+func printReport(report *services.ClientImportReport) {
+	for _, result := range report.Results {
+		switch result.Status {
+		case services.ClientImportRejected:
+			fmt.Printf("row %d\trejected\t%s <%s>: %s\n", result.Row, result.Name, result.Email, strings.Join(result.Errors, "; "))
+		default:
+			fmt.Printf("row %d\t%s\t%s <%s> (client %d)\n", result.Row, result.Status, result.Name, result.Email, result.ClientID)
+		}
+	}
+
+	fmt.Printf("\nCreated: %d, updated: %d, rejected: %d\n", report.Created, report.Updated, report.Rejected)
+	if report.DryRun {
+		fmt.Println("Dry run: nothing was saved")
+	}
+}
+
+func main() {
+	if err := rootCmd.Execute(); err != nil {
+		log.Fatal(err)
+	}
+}
@@ -2,14 +2,21 @@ package main
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
+	"net/http"
+	"os"
+	"os/signal"
 	"strings"
+	"syscall"
 	"time"
 	
 	"gaetanjaminon/GoTuto/internal/catalog/config"
 	"gaetanjaminon/GoTuto/internal/catalog/database"
 	"gaetanjaminon/GoTuto/internal/catalog/api"
+	"gaetanjaminon/GoTuto/internal/catalog/services"
+	"gaetanjaminon/GoTuto/internal/catalog/storage"
 	
 	"github.com/gin-gonic/gin"
 	"gorm.io/gorm"
@@ -25,6 +32,12 @@ func main() {
 		cfg.Database.Host, cfg.Database.Port, cfg.Database.Name, cfg.Database.Schema, cfg.Database.Username)
 	log.Printf("Logging: Level=%s, Format=%s", cfg.Logging.Level, cfg.Logging.Format)
 	
+	// Handlers read their settings from the configuration; unsupported category rules and
+	// SKU settings that cannot produce SKUs are rejected before any request is served
+	if err := api.Configure(cfg); err != nil {
+		log.Fatal("Invalid catalog configuration:", err)
+	}
+	
 	// Connect to database
 	db, err := database.Connect(cfg)
 	if err != nil {
@@ -36,18 +49,58 @@ func main() {
 		log.Fatal("Failed to migrate database:", err)
 	}
 	
+	// Background jobs stop when the server shuts down
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	
+	// Apply scheduled price changes in the background
+	if cfg.Product.PriceSchedulerInterval > 0 {
+		go services.RunPriceScheduler(ctx, db, cfg.Product.PriceSchedulerInterval)
+		log.Printf("Price scheduler: running every %s", cfg.Product.PriceSchedulerInterval)
+	} else {
+		log.Println("Price scheduler: disabled (product.price_scheduler_interval not set)")
+	}
+	
+	// Release expired stock reservations in the background
+	if cfg.Inventory.ExpiryInterval > 0 {
+		go services.RunReservationExpiry(ctx, db, cfg.Inventory.ExpiryInterval)
+		log.Printf("Reservation expiry: running every %s", cfg.Inventory.ExpiryInterval)
+	} else {
+		log.Println("Reservation expiry: disabled (inventory.expiry_interval not set)")
+	}
+	
+	// Product media is stored on the local filesystem
+	store, err := storage.NewLocalStorage(cfg.Media.StoragePath, cfg.Media.BaseURL)
+	if err != nil {
+		log.Fatal("Failed to set up media storage:", err)
+	}
+	log.Printf("Media storage: %s, served under %s", store.Root(), cfg.Media.BaseURL)
+	
 	// Set up router
-	router := setupRouter(cfg, db)
+	router := setupRouter(cfg, db, store)
 	
 	// Start server
 	addr := fmt.Sprintf(":%d", cfg.Server.Port)
-	log.Printf("Server starting on %s in %s mode", addr, cfg.Server.Mode)
-	if err := router.Run(addr); err != nil {
-		log.Fatal("Failed to start server:", err)
+	server := &http.Server{Addr: addr, Handler: router}
+	go func() {
+		log.Printf("Server starting on %s in %s mode", addr, cfg.Server.Mode)
+		if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Fatal("Failed to start server:", err)
+		}
+	}()
+	
+	// Wait for an interrupt, then let in-flight requests finish
+	<-ctx.Done()
+	log.Println("Shutting down server...")
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		log.Fatal("Server forced to shut down:", err)
 	}
+	log.Println("Server stopped")
 }
 
-func setupRouter(cfg *config.CatalogConfig, db *gorm.DB) *gin.Engine {
+func setupRouter(cfg *config.CatalogConfig, db *gorm.DB, store storage.Storage) *gin.Engine {
 	// Set Gin mode based on config
 	gin.SetMode(cfg.Server.Mode)
 	
@@ -124,6 +177,11 @@ func setupRouter(cfg *config.CatalogConfig, db *gorm.DB) *gin.Engine {
 		c.JSON(200, health)
 	})
 	
+	// Media files of the local storage
+	if local, ok := store.(*storage.LocalStorage); ok && cfg.Media.BaseURL != "" {
+		router.Static(cfg.Media.BaseURL, local.Root())
+	}
+	
 	// API routes
 	apiGroup := router.Group("/api/v1")
 	{
@@ -131,22 +189,95 @@ func setupRouter(cfg *config.CatalogConfig, db *gorm.DB) *gin.Engine {
 		products := apiGroup.Group("/products")
 		{
 			products.GET("", api.GetProducts)
+			products.GET("/search", api.SearchProducts)
 			products.GET("/:id", api.GetProduct)
 			products.POST("", api.CreateProduct)
 			products.PUT("/:id", api.UpdateProduct)
-			products.DELETE("/:id", api.DeleteProduct)
+			products.DELETE("/:id", api.DeleteProduct(store))
+			products.PUT("/:id/categories", api.SetProductCategories)
+			products.GET("/:id/price", api.ResolveProductPrice)
+			products.GET("/:id/prices", api.GetProductPrices)
+			products.POST("/:id/prices", api.ScheduleProductPrice)
+			products.DELETE("/:id/prices/:price_id", api.CancelScheduledProductPrice)
+			
+			// Images (multipart upload, stored in the media storage)
+			products.GET("/:id/images", api.GetProductImages(store))
+			products.POST("/:id/images", api.UploadProductImage(cfg.Media, store))
+			products.PUT("/:id/images/order", api.ReorderProductImages(store))
+			products.PUT("/:id/images/:image_id", api.UpdateProductImage(store))
+			products.DELETE("/:id/images/:image_id", api.DeleteProductImage(store))
+			
+			// Variants (SKUs are unique across products and variants)
+			products.GET("/:id/variants", api.GetProductVariants)
+			products.GET("/:id/variants/lookup", api.LookupProductVariant)
+			products.GET("/:id/variants/:variant_id", api.GetProductVariant)
+			products.POST("/:id/variants", api.CreateProductVariant)
+			products.PUT("/:id/variants/:variant_id", api.UpdateProductVariant)
+			products.DELETE("/:id/variants/:variant_id", api.DeleteProductVariant)
+		}
+		
+		// Brands routes
+		brands := apiGroup.Group("/brands")
+		{
+			brands.GET("", api.GetBrands)
+			brands.GET("/:id", api.GetBrand)
+			brands.POST("", api.CreateBrand)
+			brands.PUT("/:id", api.UpdateBrand)
+			brands.DELETE("/:id", api.DeleteBrand)
+		}
+		
+		// Price lists routes
+		priceLists := apiGroup.Group("/price-lists")
+		{
+			priceLists.GET("", api.GetPriceLists)
+			priceLists.GET("/:id", api.GetPriceList)
+			priceLists.POST("", api.CreatePriceList)
+			priceLists.PUT("/:id", api.UpdatePriceList)
+			priceLists.DELETE("/:id", api.DeletePriceList)
+			priceLists.PUT("/:id/items", api.SetPriceListItem)
+			priceLists.DELETE("/:id/items/:item_id", api.DeletePriceListItem)
+		}
+		
+		// Warehouses routes
+		warehouses := apiGroup.Group("/warehouses")
+		{
+			warehouses.GET("", api.GetWarehouses)
+			warehouses.GET("/:id", api.GetWarehouse)
+			warehouses.POST("", api.CreateWarehouse)
+			warehouses.PUT("/:id", api.UpdateWarehouse)
+			warehouses.DELETE("/:id", api.DeleteWarehouse)
+		}
+		
+		// Inventory routes (stock only changes through movements and reservations)
+		inventory := apiGroup.Group("/inventory")
+		{
+			inventory.GET("/stock", api.GetStockLevels)
+			inventory.PUT("/stock/:id", api.UpdateStockLevel)
+			inventory.GET("/movements", api.GetStockMovements)
+			inventory.POST("/movements", api.CreateStockMovement)
+			inventory.GET("/reservations/:id", api.GetReservation)
+			inventory.POST("/reservations", api.CreateReservation(cfg.Inventory))
+			inventory.POST("/reservations/:id/release", api.ReleaseReservation)
+			inventory.POST("/reservations/:id/fulfill", api.FulfillReservation)
 		}
 		
 		// Categories routes
 		categories := apiGroup.Group("/categories")
 		{
 			categories.GET("", api.GetCategories)
+			categories.GET("/tree", api.GetCategoryTree)
 			categories.GET("/:id", api.GetCategory)
 			categories.POST("", api.CreateCategory)
 			categories.PUT("/:id", api.UpdateCategory)
 			categories.DELETE("/:id", api.DeleteCategory)
 			categories.PUT("/:id/move", api.MoveCategory)
-			categories.GET("/:category_id/products", api.GetCategoryProducts)
+			categories.PUT("/:id/children/order", api.ReorderCategoryChildren)
+			categories.GET("/:id/products", api.GetCategoryProducts)
+			categories.GET("/:id/path", api.GetCategoryPath)
+			categories.GET("/:id/attributes", api.GetCategoryAttributes)
+			categories.POST("/:id/attributes", api.CreateCategoryAttribute)
+			categories.PUT("/:id/attributes/:attribute_id", api.UpdateCategoryAttribute)
+			categories.DELETE("/:id/attributes/:attribute_id", api.DeleteCategoryAttribute)
 		}
 	}
 	
@@ -1,16 +1,21 @@
 package api
 
 import (
+	"context"
+	"errors"
 	"net/http"
 	"strconv"
 	
 	"gaetanjaminon/GoTuto/internal/billing/models"
+	"gaetanjaminon/GoTuto/internal/billing/services"
 	
 	"github.com/gin-gonic/gin"
 	"gorm.io/gorm"
 )
 
-// GetClients retrieves all clients with optional pagination
+// GetClients retrieves all clients with optional pagination. Clients can be filtered by
+// search text, tags (?tag=retail&tag=gold), custom fields (?cf.industry=retail) and a saved
+// segment (?segment_id=); the filters combine with AND.
 func GetClients(db *gorm.DB) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		var clients []models.Client
@@ -20,13 +25,13 @@ func GetClients(db *gorm.DB) gin.HandlerFunc {
 		limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
 		offset := (page - 1) * limit
 		
-		// Optional search by name or email
-		search := c.Query("search")
-		query := db.Limit(limit).Offset(offset)
-		
-		if search != "" {
-			query = query.Where("name ILIKE ? OR email ILIKE ?", "%"+search+"%", "%"+search+"%")
+		// Optional search, tag, custom field and segment filters
+		filter, err := clientFilterFromQuery(c, db)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
 		}
+		query := applyClientFilter(db.Preload("Tags").Limit(limit).Offset(offset), filter)
 		
 		if err := query.Find(&clients).Error; err != nil {
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve clients"})
@@ -35,11 +40,7 @@ func GetClients(db *gorm.DB) gin.HandlerFunc {
 		
 		// Get total count for pagination
 		var total int64
-		countQuery := db.Model(&models.Client{})
-		if search != "" {
-			countQuery = countQuery.Where("name ILIKE ? OR email ILIKE ?", "%"+search+"%", "%"+search+"%")
-		}
-		countQuery.Count(&total)
+		applyClientFilter(db.Model(&models.Client{}), filter).Count(&total)
 		
 		c.JSON(http.StatusOK, gin.H{
 			"clients": clients,
@@ -59,7 +60,7 @@ func GetClient(db *gorm.DB) gin.HandlerFunc {
 		var client models.Client
 		
 		// Include invoices in the response
-		if err := db.Preload("Invoices").First(&client, id).Error; err != nil {
+		if err := db.Preload("Invoices").Preload("PaymentTerm").Preload("Addresses").Preload("Contacts").Preload("Tags").First(&client, id).Error; err != nil {
 			c.JSON(http.StatusNotFound, gin.H{"error": "Client not found"})
 			return
 		}
@@ -69,7 +70,7 @@ func GetClient(db *gorm.DB) gin.HandlerFunc {
 }
 
 // CreateClient creates a new client
-func CreateClient(db *gorm.DB) gin.HandlerFunc {
+func CreateClient(db *gorm.DB, verifier services.TaxIDVerifier) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		var req models.CreateClientRequest
 		
@@ -78,11 +79,10 @@ func CreateClient(db *gorm.DB) gin.HandlerFunc {
 			return
 		}
 		
-		client := models.Client{
-			Name:    req.Name,
-			Email:   req.Email,
-			Phone:   req.Phone,
-			Address: req.Address,
+		client, err := newClientFromRequest(c.Request.Context(), db, verifier, req)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
 		}
 		
 		if err := db.Create(&client).Error; err != nil {
@@ -94,8 +94,82 @@ func CreateClient(db *gorm.DB) gin.HandlerFunc {
 	}
 }
 
+// newClientFromRequest builds a validated client, with its addresses and contacts, from a
+// create request. Returned errors describe invalid input.
+func newClientFromRequest(ctx context.Context, db *gorm.DB, verifier services.TaxIDVerifier, req models.CreateClientRequest) (models.Client, error) {
+	// Verify payment terms exist if provided
+	if req.PaymentTermID != nil {
+		var term models.PaymentTerm
+		if err := db.First(&term, *req.PaymentTermID).Error; err != nil {
+			return models.Client{}, errors.New("Payment term not found")
+		}
+	}
+	
+	client := models.Client{
+		Name:          req.Name,
+		Email:         req.Email,
+		Phone:         req.Phone,
+		Address:       req.Address,
+		PaymentTermID: req.PaymentTermID,
+	}
+	
+	// Tax identifier
+	if req.TaxID != "" {
+		client.TaxID = req.TaxID
+		client.TaxIDScheme = req.TaxIDScheme
+		if err := verifyClientTaxID(ctx, verifier, &client); err != nil {
+			return client, err
+		}
+	}
+	
+	// Structured addresses
+	if req.BillingAddress != nil {
+		client.Addresses = append(client.Addresses, req.BillingAddress.ToAddress(models.AddressTypeBilling))
+	}
+	if req.ShippingAddress != nil {
+		client.Addresses = append(client.Addresses, req.ShippingAddress.ToAddress(models.AddressTypeShipping))
+	}
+	for _, address := range client.Addresses {
+		if err := address.Validate(); err != nil {
+			return client, errors.New(string(address.Type) + " address: " + err.Error())
+		}
+	}
+	// Keep the legacy free-text address filled for older consumers
+	if billing := client.AddressOfType(models.AddressTypeBilling); billing != nil && client.Address == "" {
+		client.Address = billing.String()
+	}
+	
+	// Tags and custom fields
+	tags, err := models.NormalizeTags(req.Tags)
+	if err != nil {
+		return models.Client{}, err
+	}
+	for _, tag := range tags {
+		client.Tags = append(client.Tags, models.ClientTag{Name: tag})
+	}
+	if client.CustomFields, err = validateClientCustomFields(db, req.CustomFields); err != nil {
+		return models.Client{}, err
+	}
+	
+	// Contacts; the client email becomes the primary billing contact when none is given
+	for _, contactReq := range req.Contacts {
+		client.Contacts = append(client.Contacts, contactReq.ToContact())
+	}
+	if len(client.ContactsWithRole(models.ContactRoleBilling)) == 0 {
+		client.Contacts = append(client.Contacts, models.ClientContact{
+			Name:      client.Name,
+			Email:     client.Email,
+			Phone:     client.Phone,
+			Role:      models.ContactRoleBilling,
+			IsPrimary: true,
+		})
+	}
+	
+	return client, nil
+}
+
 // UpdateClient updates an existing client
-func UpdateClient(db *gorm.DB) gin.HandlerFunc {
+func UpdateClient(db *gorm.DB, verifier services.TaxIDVerifier) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		id := c.Param("id")
 		var client models.Client
@@ -104,6 +178,10 @@ func UpdateClient(db *gorm.DB) gin.HandlerFunc {
 			c.JSON(http.StatusNotFound, gin.H{"error": "Client not found"})
 			return
 		}
+		if client.IsErased() {
+			c.JSON(http.StatusConflict, gin.H{"error": "Client data has been erased"})
+			return
+		}
 		
 		var req models.UpdateClientRequest
 		if err := c.ShouldBindJSON(&req); err != nil {
@@ -124,16 +202,123 @@ func UpdateClient(db *gorm.DB) gin.HandlerFunc {
 		if req.Address != "" {
 			client.Address = req.Address
 		}
+		if req.TaxID != "" || req.TaxIDScheme != "" {
+			if req.TaxID != "" {
+				client.TaxID = req.TaxID
+			}
+			client.TaxIDScheme = req.TaxIDScheme
+			if err := verifyClientTaxID(c.Request.Context(), verifier, &client); err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+				return
+			}
+		}
+		if req.PaymentTermID != nil {
+			// A payment_term_id of 0 removes the client's payment terms
+			if *req.PaymentTermID == 0 {
+				client.PaymentTermID = nil
+			} else {
+				var term models.PaymentTerm
+				if err := db.First(&term, *req.PaymentTermID).Error; err != nil {
+					c.JSON(http.StatusBadRequest, gin.H{"error": "Payment term not found"})
+					return
+				}
+				client.PaymentTermID = req.PaymentTermID
+			}
+		}
+		if req.CustomFields != nil {
+			values := models.CustomFieldValues{}
+			for key, value := range client.CustomFields {
+				values[key] = value
+			}
+			// A null value removes the field
+			for key, value := range req.CustomFields {
+				if value == nil {
+					delete(values, key)
+				} else {
+					values[key] = value
+				}
+			}
+			customFields, err := validateClientCustomFields(db, values)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+				return
+			}
+			client.CustomFields = customFields
+		}
+		var tags []string
+		if req.Tags != nil {
+			var err error
+			if tags, err = models.NormalizeTags(req.Tags); err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+				return
+			}
+		}
 		
-		if err := db.Save(&client).Error; err != nil {
+		err := db.Transaction(func(tx *gorm.DB) error {
+			if err := tx.Save(&client).Error; err != nil {
+				return err
+			}
+			if req.Tags == nil {
+				return nil
+			}
+			return replaceClientTags(tx, client.ID, tags)
+		})
+		if err != nil {
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update client"})
 			return
 		}
+		db.Where("client_id = ?", client.ID).Order("name").Find(&client.Tags)
 		
 		c.JSON(http.StatusOK, client)
 	}
 }
 
+// GetClientTags lists the tags in use with the number of clients carrying each
+func GetClientTags(db *gorm.DB) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		type tagUsage struct {
+			Name        string `json:"name"`
+			ClientCount int64  `json:"client_count"`
+		}
+		var tags []tagUsage
+		
+		if err := db.Model(&models.ClientTag{}).
+			Select("client_tags.name AS name, COUNT(*) AS client_count").
+			Joins("JOIN clients ON clients.id = client_tags.client_id AND clients.deleted_at IS NULL").
+			Group("client_tags.name").Order("client_tags.name").
+			Scan(&tags).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve tags"})
+			return
+		}
+		
+		c.JSON(http.StatusOK, gin.H{"tags": tags})
+	}
+}
+
+// replaceClientTags replaces all tags of a client with the given normalized tags
+func replaceClientTags(tx *gorm.DB, clientID uint, tags []string) error {
+	if err := tx.Where("client_id = ?", clientID).Delete(&models.ClientTag{}).Error; err != nil {
+		return err
+	}
+	if len(tags) == 0 {
+		return nil
+	}
+	rows := make([]models.ClientTag, 0, len(tags))
+	for _, tag := range tags {
+		rows = append(rows, models.ClientTag{ClientID: clientID, Name: tag})
+	}
+	return tx.Create(&rows).Error
+}
+
+// validateClientCustomFields checks custom field values against the current definitions
+func validateClientCustomFields(db *gorm.DB, values models.CustomFieldValues) (models.CustomFieldValues, error) {
+	var definitions []models.CustomFieldDefinition
+	if err := db.Find(&definitions).Error; err != nil {
+		return nil, errors.New("Failed to load custom field definitions")
+	}
+	return models.ValidateCustomFields(definitions, values)
+}
+
 // DeleteClient soft deletes a client
 func DeleteClient(db *gorm.DB) gin.HandlerFunc {
 	return func(c *gin.Context) {
@@ -153,6 +338,7 @@ func DeleteClient(db *gorm.DB) gin.HandlerFunc {
 			c.JSON(http.StatusBadRequest, gin.H{
 				"error": "Cannot delete client with existing invoices",
 				"invoice_count": invoiceCount,
+				"hint": "Merge a duplicate into the surviving client with POST /api/v1/clients/{survivor_id}/merge",
 			})
 			return
 		}
@@ -164,4 +350,67 @@ func DeleteClient(db *gorm.DB) gin.HandlerFunc {
 		
 		c.JSON(http.StatusOK, gin.H{"message": "Client deleted successfully"})
 	}
-}
\ No newline at end of file
+}
+
+// VerifyClientTaxID re-runs tax ID verification for a client and records the result
+func VerifyClientTaxID(db *gorm.DB, verifier services.TaxIDVerifier) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id := c.Param("id")
+		var client models.Client
+		
+		if err := db.First(&client, id).Error; err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Client not found"})
+			return
+		}
+		
+		if client.TaxID == "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Client has no tax ID"})
+			return
+		}
+		
+		result, err := verifier.Verify(c.Request.Context(), client.TaxIDScheme, client.TaxID)
+		if err != nil {
+			c.JSON(http.StatusBadGateway, gin.H{"error": "Tax ID verification unavailable: " + err.Error()})
+			return
+		}
+		
+		// Only a registry confirms that the number is actually registered
+		client.TaxIDVerifiedAt = nil
+		if result.Valid && result.Registry {
+			client.TaxIDVerifiedAt = &result.CheckedAt
+		}
+		if err := db.Model(&client).Update("tax_id_verified_at", client.TaxIDVerifiedAt).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to record verification"})
+			return
+		}
+		
+		c.JSON(http.StatusOK, result)
+	}
+}
+
+// verifyClientTaxID normalizes the client's tax ID and runs it through the verifier.
+// The ID is marked verified only by a registry; when the verifier cannot be reached or
+// only checks the format, the offline-validated ID is kept unverified.
+func verifyClientTaxID(ctx context.Context, verifier services.TaxIDVerifier, client *models.Client) error {
+	normalized, err := models.ValidateTaxID(client.TaxIDScheme, client.TaxID)
+	if err != nil {
+		return err
+	}
+	client.TaxID = normalized
+	if client.TaxIDScheme == "" {
+		client.TaxIDScheme = models.DetectTaxIDScheme(normalized)
+	}
+	client.TaxIDVerifiedAt = nil
+	
+	result, err := verifier.Verify(ctx, client.TaxIDScheme, normalized)
+	if err != nil {
+		return nil
+	}
+	if !result.Valid {
+		return errors.New(result.Reason)
+	}
+	if result.Registry {
+		client.TaxIDVerifiedAt = &result.CheckedAt
+	}
+	return nil
+}
@@ -0,0 +1,105 @@
+package api
+
+import (
+	"net/http"
+
+	"gaetanjaminon/GoTuto/internal/billing/models"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// GetClientAddresses retrieves the structured addresses of a client
+func GetClientAddresses(db *gorm.DB) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		clientID := c.Param("id")
+
+		// Verify client exists
+		var client models.Client
+		if err := db.First(&client, clientID).Error; err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Client not found"})
+			return
+		}
+
+		var addresses []models.ClientAddress
+		if err := db.Where("client_id = ?", client.ID).Order("type ASC").Find(&addresses).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve addresses"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"addresses": addresses})
+	}
+}
+
+// SetClientAddress creates or replaces the billing or shipping address of a client
+func SetClientAddress(db *gorm.DB) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		clientID := c.Param("id")
+		addressType := models.AddressType(c.Param("type"))
+
+		if addressType != models.AddressTypeBilling && addressType != models.AddressTypeShipping {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Address type must be 'billing' or 'shipping'"})
+			return
+		}
+
+		// Verify client exists
+		var client models.Client
+		if err := db.First(&client, clientID).Error; err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Client not found"})
+			return
+		}
+		if client.IsErased() {
+			c.JSON(http.StatusConflict, gin.H{"error": "Client data has been erased"})
+			return
+		}
+
+		var req models.AddressRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		address := req.ToAddress(addressType)
+		address.ClientID = client.ID
+		if err := address.Validate(); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		status := http.StatusCreated
+		var existing models.ClientAddress
+		if err := db.Where("client_id = ? AND type = ?", client.ID, addressType).First(&existing).Error; err == nil {
+			address.ID = existing.ID
+			address.CreatedAt = existing.CreatedAt
+			status = http.StatusOK
+		}
+
+		if err := db.Save(&address).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save address"})
+			return
+		}
+
+		c.JSON(status, address)
+	}
+}
+
+// DeleteClientAddress removes the billing or shipping address of a client
+func DeleteClientAddress(db *gorm.DB) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		clientID := c.Param("id")
+		addressType := c.Param("type")
+
+		var address models.ClientAddress
+		if err := db.Where("client_id = ? AND type = ?", clientID, addressType).First(&address).Error; err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Address not found"})
+			return
+		}
+
+		if err := db.Delete(&address).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete address"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"message": "Address deleted successfully"})
+	}
+}
@@ -0,0 +1,160 @@
+package api
+
+import (
+	"net/http"
+	"strings"
+
+	"gaetanjaminon/GoTuto/internal/billing/models"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// GetClientContacts retrieves the contacts of a client, optionally filtered by role
+func GetClientContacts(db *gorm.DB) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		clientID := c.Param("id")
+
+		// Verify client exists
+		var client models.Client
+		if err := db.First(&client, clientID).Error; err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Client not found"})
+			return
+		}
+
+		query := db.Where("client_id = ?", client.ID).Order("role ASC, is_primary DESC, name ASC")
+		if role := c.Query("role"); role != "" {
+			query = query.Where("role = ?", role)
+		}
+
+		var contacts []models.ClientContact
+		if err := query.Find(&contacts).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve contacts"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"contacts": contacts})
+	}
+}
+
+// CreateClientContact adds a contact to a client
+func CreateClientContact(db *gorm.DB) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		clientID := c.Param("id")
+
+		// Verify client exists
+		var client models.Client
+		if err := db.First(&client, clientID).Error; err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Client not found"})
+			return
+		}
+		if client.IsErased() {
+			c.JSON(http.StatusConflict, gin.H{"error": "Client data has been erased"})
+			return
+		}
+
+		var req models.ContactRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		contact := req.ToContact()
+		contact.ClientID = client.ID
+
+		err := db.Transaction(func(tx *gorm.DB) error {
+			if contact.IsPrimary {
+				if err := clearPrimaryContact(tx, client.ID, contact.Role); err != nil {
+					return err
+				}
+			}
+			return tx.Create(&contact).Error
+		})
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create contact"})
+			return
+		}
+
+		c.JSON(http.StatusCreated, contact)
+	}
+}
+
+// UpdateClientContact updates a contact of a client
+func UpdateClientContact(db *gorm.DB) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		clientID := c.Param("id")
+		contactID := c.Param("contact_id")
+
+		var contact models.ClientContact
+		if err := db.Where("client_id = ?", clientID).First(&contact, contactID).Error; err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Contact not found"})
+			return
+		}
+
+		var req models.UpdateContactRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		// Update only provided fields
+		if req.Name != "" {
+			contact.Name = strings.TrimSpace(req.Name)
+		}
+		if req.Email != "" {
+			contact.Email = strings.ToLower(strings.TrimSpace(req.Email))
+		}
+		if req.Phone != "" {
+			contact.Phone = strings.TrimSpace(req.Phone)
+		}
+		if req.Role != "" {
+			contact.Role = req.Role
+		}
+		if req.IsPrimary != nil {
+			contact.IsPrimary = *req.IsPrimary
+		}
+
+		err := db.Transaction(func(tx *gorm.DB) error {
+			if contact.IsPrimary {
+				if err := clearPrimaryContact(tx, contact.ClientID, contact.Role); err != nil {
+					return err
+				}
+			}
+			return tx.Save(&contact).Error
+		})
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update contact"})
+			return
+		}
+
+		c.JSON(http.StatusOK, contact)
+	}
+}
+
+// DeleteClientContact removes a contact from a client
+func DeleteClientContact(db *gorm.DB) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		clientID := c.Param("id")
+		contactID := c.Param("contact_id")
+
+		var contact models.ClientContact
+		if err := db.Where("client_id = ?", clientID).First(&contact, contactID).Error; err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Contact not found"})
+			return
+		}
+
+		if err := db.Delete(&contact).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete contact"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"message": "Contact deleted successfully"})
+	}
+}
+
+// clearPrimaryContact unsets the primary flag of the client's contacts with the given role
+func clearPrimaryContact(tx *gorm.DB, clientID uint, role models.ContactRole) error {
+	return tx.Model(&models.ClientContact{}).
+		Where("client_id = ? AND role = ? AND is_primary = ?", clientID, role, true).
+		Update("is_primary", false).Error
+}
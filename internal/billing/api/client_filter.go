@@ -0,0 +1,72 @@
+package api
+
+import (
+	"errors"
+	"sort"
+	"strings"
+
+	"gaetanjaminon/GoTuto/internal/billing/models"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// customFieldQueryPrefix prefixes query parameters filtering on a custom field, e.g. cf.industry=retail
+const customFieldQueryPrefix = "cf."
+
+var errSegmentNotFound = errors.New("Segment not found")
+
+// clientFilterFromQuery reads the search, tag (repeatable or comma separated) and cf.<key>
+// query parameters. With segment_id, the saved segment's filter is narrowed by them.
+func clientFilterFromQuery(c *gin.Context, db *gorm.DB) (models.ClientFilter, error) {
+	filter := models.ClientFilter{
+		Search:       c.Query("search"),
+		CustomFields: map[string]string{},
+	}
+	for _, value := range c.QueryArray("tag") {
+		filter.Tags = append(filter.Tags, strings.Split(value, ",")...)
+	}
+	for key, values := range c.Request.URL.Query() {
+		if strings.HasPrefix(key, customFieldQueryPrefix) && len(values) > 0 {
+			filter.CustomFields[strings.TrimPrefix(key, customFieldQueryPrefix)] = values[0]
+		}
+	}
+
+	if segmentID := c.Query("segment_id"); segmentID != "" {
+		var segment models.ClientSegment
+		if err := db.First(&segment, segmentID).Error; err != nil {
+			return filter, errSegmentNotFound
+		}
+		filter = segment.Filter.Merge(filter)
+	}
+
+	return filter, filter.Normalize()
+}
+
+// applyClientFilter restricts a query on the clients table to the clients matching filter
+func applyClientFilter(query *gorm.DB, filter models.ClientFilter) *gorm.DB {
+	if filter.Search != "" {
+		query = query.Where("(clients.name ILIKE ? OR clients.email ILIKE ?)", "%"+filter.Search+"%", "%"+filter.Search+"%")
+	}
+	for _, tag := range filter.Tags {
+		query = query.Where("EXISTS (SELECT 1 FROM client_tags WHERE client_tags.client_id = clients.id AND client_tags.name = ?)", tag)
+	}
+	for _, key := range sortedKeys(filter.CustomFields) {
+		query = query.Where("clients.custom_fields ->> CAST(? AS TEXT) = ?", key, filter.CustomFields[key])
+	}
+	return query
+}
+
+// clientIDsMatching returns a subquery selecting the IDs of the clients matching filter
+func clientIDsMatching(db *gorm.DB, filter models.ClientFilter) *gorm.DB {
+	return applyClientFilter(db.Model(&models.Client{}).Select("clients.id"), filter)
+}
+
+func sortedKeys(values map[string]string) []string {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
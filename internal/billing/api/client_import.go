@@ -0,0 +1,254 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"gaetanjaminon/GoTuto/internal/billing/models"
+	"gaetanjaminon/GoTuto/internal/billing/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"gorm.io/gorm"
+)
+
+// maxClientImportSize limits the size of an uploaded import file
+const maxClientImportSize = 10 << 20
+
+// errImportDryRun rolls back the import transaction of a dry run
+var errImportDryRun = errors.New("dry run")
+
+// ImportClients creates or updates clients in bulk from an uploaded CSV or vCard file.
+//
+// Multipart form fields:
+//   - file: the CSV or vCard file
+//   - format: "csv" or "vcard" (default: guessed from the file extension)
+//   - mapping: JSON object mapping client fields to CSV column headers
+//   - dry_run: "true" to validate and report without saving anything
+//
+// Each record is validated with the same rules as POST /clients. Records whose email
+// matches an existing client update that client; the others create new clients.
+func ImportClients(db *gorm.DB, verifier services.TaxIDVerifier) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxClientImportSize)
+
+		fileHeader, err := c.FormFile("file")
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "An import file is required in the 'file' field"})
+			return
+		}
+
+		format := services.ClientImportFormat(strings.ToLower(c.PostForm("format")))
+		if format == "" {
+			format = services.ClientImportFormatCSV
+			switch strings.ToLower(filepath.Ext(fileHeader.Filename)) {
+			case ".vcf", ".vcard":
+				format = services.ClientImportFormatVCard
+			}
+		}
+
+		var mapping map[string]string
+		if raw := c.PostForm("mapping"); raw != "" {
+			if err := json.Unmarshal([]byte(raw), &mapping); err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "mapping must be a JSON object of field to column header"})
+				return
+			}
+		}
+
+		dryRun := false
+		if raw := c.DefaultPostForm("dry_run", c.Query("dry_run")); raw != "" {
+			if dryRun, err = strconv.ParseBool(raw); err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "dry_run must be true or false"})
+				return
+			}
+		}
+
+		file, err := fileHeader.Open()
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Failed to read import file"})
+			return
+		}
+		defer file.Close()
+
+		var rows []services.ClientImportRow
+		switch format {
+		case services.ClientImportFormatCSV:
+			rows, err = services.ParseClientCSV(file, mapping)
+		case services.ClientImportFormatVCard:
+			rows, err = services.ParseClientVCards(file)
+		default:
+			c.JSON(http.StatusBadRequest, gin.H{"error": "format must be 'csv' or 'vcard'"})
+			return
+		}
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		report, err := importClientRows(c.Request.Context(), db, verifier, rows, dryRun)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to import clients"})
+			return
+		}
+
+		c.JSON(http.StatusOK, report)
+	}
+}
+
+// importClientRows imports all rows in one transaction, each row in its own savepoint so a
+// rejected row does not affect the others. A dry run rolls the whole transaction back.
+func importClientRows(ctx context.Context, db *gorm.DB, verifier services.TaxIDVerifier, rows []services.ClientImportRow, dryRun bool) (*services.ClientImportReport, error) {
+	report := &services.ClientImportReport{DryRun: dryRun, Results: []services.ClientImportResult{}}
+
+	err := db.Transaction(func(tx *gorm.DB) error {
+		seen := make(map[string]int, len(rows))
+		for _, row := range rows {
+			result := services.ClientImportResult{
+				Row:    row.Row,
+				Status: services.ClientImportRejected,
+				Name:   row.Request.Name,
+				Email:  row.Request.Email,
+				Errors: row.Errors,
+			}
+			if len(result.Errors) == 0 {
+				result.Errors = validateImportRow(row, seen)
+			}
+			if len(result.Errors) == 0 {
+				if err := tx.Transaction(func(rowTx *gorm.DB) error {
+					return importClientRow(ctx, rowTx, verifier, row.Request, &result)
+				}); err != nil && len(result.Errors) == 0 {
+					result.Errors = []string{"Failed to save client"}
+				}
+			}
+			if len(result.Errors) > 0 {
+				result.Status = services.ClientImportRejected
+				result.ClientID = 0
+			}
+			if dryRun && result.Status == services.ClientImportCreated {
+				// The ID will not exist once the transaction is rolled back
+				result.ClientID = 0
+			}
+			report.Add(result)
+		}
+
+		if dryRun {
+			return errImportDryRun
+		}
+		return nil
+	})
+	if err != nil && !errors.Is(err, errImportDryRun) {
+		return nil, err
+	}
+	return report, nil
+}
+
+// validateImportRow applies the CreateClientRequest binding rules and rejects emails
+// already used earlier in the same file
+func validateImportRow(row services.ClientImportRow, seen map[string]int) []string {
+	if err := binding.Validator.ValidateStruct(&row.Request); err != nil {
+		return strings.Split(err.Error(), "\n")
+	}
+
+	email := strings.ToLower(row.Request.Email)
+	if first, ok := seen[email]; ok {
+		return []string{"email already used by row " + strconv.Itoa(first)}
+	}
+	seen[email] = row.Row
+	return nil
+}
+
+// importClientRow creates the client or updates the one with the same email. Validation
+// problems are reported in result.Errors and returned so the savepoint is rolled back.
+func importClientRow(ctx context.Context, tx *gorm.DB, verifier services.TaxIDVerifier, req models.CreateClientRequest, result *services.ClientImportResult) error {
+	reject := func(message string) error {
+		result.Errors = append(result.Errors, message)
+		return errors.New(message)
+	}
+
+	var existing models.Client
+	err := tx.Unscoped().Where("LOWER(email) = LOWER(?)", req.Email).First(&existing).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		client, err := newClientFromRequest(ctx, tx, verifier, req)
+		if err != nil {
+			return reject(err.Error())
+		}
+		if err := tx.Create(&client).Error; err != nil {
+			return err
+		}
+		result.Status = services.ClientImportCreated
+		result.ClientID = client.ID
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	if existing.DeletedAt.Valid {
+		return reject("email belongs to a deleted client")
+	}
+
+	// Update the existing client, only overwriting fields present in the file
+	if err := applyClientImport(ctx, tx, verifier, &existing, req); err != nil {
+		return reject(err.Error())
+	}
+	if err := tx.Save(&existing).Error; err != nil {
+		return err
+	}
+	result.Status = services.ClientImportUpdated
+	result.ClientID = existing.ID
+	return nil
+}
+
+// applyClientImport merges the non-empty fields of an import record into an existing client.
+// Contacts are only imported for new clients, so re-running an import adds no duplicates.
+func applyClientImport(ctx context.Context, tx *gorm.DB, verifier services.TaxIDVerifier, client *models.Client, req models.CreateClientRequest) error {
+	client.Name = req.Name
+	if req.Phone != "" {
+		client.Phone = req.Phone
+	}
+	if req.Address != "" {
+		client.Address = req.Address
+	}
+	if req.TaxID != "" {
+		client.TaxID = req.TaxID
+		client.TaxIDScheme = req.TaxIDScheme
+		if err := verifyClientTaxID(ctx, verifier, client); err != nil {
+			return err
+		}
+	}
+	if req.PaymentTermID != nil {
+		var term models.PaymentTerm
+		if err := tx.First(&term, *req.PaymentTermID).Error; err != nil {
+			return errors.New("Payment term not found")
+		}
+		client.PaymentTermID = req.PaymentTermID
+	}
+
+	for addressType, addressReq := range map[models.AddressType]*models.AddressRequest{
+		models.AddressTypeBilling:  req.BillingAddress,
+		models.AddressTypeShipping: req.ShippingAddress,
+	} {
+		if addressReq == nil {
+			continue
+		}
+		address := addressReq.ToAddress(addressType)
+		address.ClientID = client.ID
+		if err := address.Validate(); err != nil {
+			return errors.New(string(addressType) + " address: " + err.Error())
+		}
+		var current models.ClientAddress
+		if err := tx.Where("client_id = ? AND type = ?", client.ID, addressType).First(&current).Error; err == nil {
+			address.ID = current.ID
+			address.CreatedAt = current.CreatedAt
+		}
+		if err := tx.Save(&address).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
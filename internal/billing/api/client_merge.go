@@ -0,0 +1,260 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"gaetanjaminon/GoTuto/internal/billing/models"
+	"gaetanjaminon/GoTuto/internal/billing/services"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// GetClientDuplicates lists pairs of clients that likely represent the same customer
+func GetClientDuplicates(db *gorm.DB) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		threshold, err := strconv.ParseFloat(c.DefaultQuery("threshold", "0"), 64)
+		if err != nil || threshold < 0 || threshold > 1 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "threshold must be between 0 and 1"})
+			return
+		}
+
+		var clients []models.Client
+		if err := db.Select("id", "name", "email", "phone").Where("erased_at IS NULL").Find(&clients).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve clients"})
+			return
+		}
+
+		matches := services.FindDuplicates(clients, threshold)
+
+		// Optionally restrict to the duplicates of one client
+		if clientID := c.Query("client_id"); clientID != "" {
+			id, _ := strconv.ParseUint(clientID, 10, 32)
+			filtered := []services.DuplicateMatch{}
+			for _, match := range matches {
+				if match.ClientID == uint(id) || match.DuplicateID == uint(id) {
+					filtered = append(filtered, match)
+				}
+			}
+			matches = filtered
+		}
+
+		byID := make(map[uint]models.Client, len(clients))
+		for _, client := range clients {
+			byID[client.ID] = client
+		}
+
+		results := make([]gin.H, 0, len(matches))
+		for _, match := range matches {
+			results = append(results, gin.H{
+				"client":    byID[match.ClientID],
+				"duplicate": byID[match.DuplicateID],
+				"score":     match.Score,
+				"reasons":   match.Reasons,
+			})
+		}
+
+		c.JSON(http.StatusOK, gin.H{"duplicates": results})
+	}
+}
+
+// MergeClients merges duplicate clients into the client identified by :id. Invoices,
+// contacts and missing addresses move to the survivor in a single transaction, the
+// duplicates are soft deleted and an audit record is written for each of them.
+func MergeClients(db *gorm.DB) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id := c.Param("id")
+		var survivor models.Client
+
+		if err := db.First(&survivor, id).Error; err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Client not found"})
+			return
+		}
+
+		var req models.MergeClientsRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		var merges []models.ClientMerge
+		err := db.Transaction(func(tx *gorm.DB) error {
+			// Lock the survivor so concurrent merges into it are serialized
+			if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&survivor, survivor.ID).Error; err != nil {
+				return err
+			}
+
+			for _, duplicateID := range req.DuplicateIDs {
+				if duplicateID == survivor.ID {
+					return errMergeIntoSelf
+				}
+
+				var duplicate models.Client
+				if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+					Preload("Addresses").Preload("Contacts").Preload("Tags").
+					First(&duplicate, duplicateID).Error; err != nil {
+					return fmt.Errorf("%w: %d", errMergeClientNotFound, duplicateID)
+				}
+
+				merge, err := mergeClient(tx, &survivor, duplicate, req.Reason)
+				if err != nil {
+					return err
+				}
+				merges = append(merges, merge)
+			}
+
+			return tx.Save(&survivor).Error
+		})
+		if errors.Is(err, errMergeIntoSelf) || errors.Is(err, errMergeClientNotFound) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to merge clients"})
+			return
+		}
+
+		// Load merged data for response
+		db.Preload("Addresses").Preload("Contacts").Preload("Tags").First(&survivor, survivor.ID)
+
+		c.JSON(http.StatusOK, gin.H{
+			"client": survivor,
+			"merges": merges,
+		})
+	}
+}
+
+// GetClientMerges retrieves the merge audit trail of a client
+func GetClientMerges(db *gorm.DB) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id := c.Param("id")
+
+		var merges []models.ClientMerge
+		if err := db.Where("survivor_id = ? OR merged_client_id = ?", id, id).Order("created_at DESC").Find(&merges).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve merges"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"merges": merges})
+	}
+}
+
+var (
+	errMergeIntoSelf       = errors.New("a client cannot be merged into itself")
+	errMergeClientNotFound = errors.New("duplicate client not found")
+)
+
+// mergeClient moves everything owned by the duplicate to the survivor and soft deletes the duplicate
+func mergeClient(tx *gorm.DB, survivor *models.Client, duplicate models.Client, reason string) (models.ClientMerge, error) {
+	snapshot, err := json.Marshal(duplicate)
+	if err != nil {
+		return models.ClientMerge{}, err
+	}
+	merge := models.ClientMerge{
+		SurvivorID:     survivor.ID,
+		MergedClientID: duplicate.ID,
+		MergedSnapshot: string(snapshot),
+		Reason:         reason,
+	}
+
+	// Invoices, including soft-deleted ones, keep their history under the survivor
+	result := tx.Unscoped().Model(&models.Invoice{}).Where("client_id = ?", duplicate.ID).Update("client_id", survivor.ID)
+	if result.Error != nil {
+		return merge, result.Error
+	}
+	merge.InvoicesMoved = result.RowsAffected
+
+	// Contacts: drop those the survivor already has (same email and role), move the rest as non-primary
+	var survivorContacts []models.ClientContact
+	if err := tx.Where("client_id = ?", survivor.ID).Find(&survivorContacts).Error; err != nil {
+		return merge, err
+	}
+	moved, redundant := models.SplitMergedContacts(survivorContacts, duplicate.Contacts)
+	if len(redundant) > 0 {
+		if err := tx.Delete(&redundant).Error; err != nil {
+			return merge, err
+		}
+	}
+	if len(moved) > 0 {
+		movedIDs := make([]uint, len(moved))
+		for i, contact := range moved {
+			movedIDs[i] = contact.ID
+		}
+		result = tx.Model(&models.ClientContact{}).Where("id IN ?", movedIDs).
+			Updates(map[string]interface{}{"client_id": survivor.ID, "is_primary": false})
+		if result.Error != nil {
+			return merge, result.Error
+		}
+		merge.ContactsMoved = result.RowsAffected
+	}
+
+	// Addresses: the survivor's addresses win, missing types are taken from the duplicate
+	for _, address := range duplicate.Addresses {
+		var count int64
+		tx.Model(&models.ClientAddress{}).Where("client_id = ? AND type = ?", survivor.ID, address.Type).Count(&count)
+		if count > 0 {
+			if err := tx.Delete(&address).Error; err != nil {
+				return merge, err
+			}
+			continue
+		}
+		if err := tx.Model(&address).Update("client_id", survivor.ID).Error; err != nil {
+			return merge, err
+		}
+		merge.AddressesMoved++
+	}
+
+	// Tags: the survivor gets the union of both sets
+	if err := tx.Where("client_id = ? AND name IN (?)", duplicate.ID,
+		tx.Model(&models.ClientTag{}).Select("name").Where("client_id = ?", survivor.ID),
+	).Delete(&models.ClientTag{}).Error; err != nil {
+		return merge, err
+	}
+	if err := tx.Model(&models.ClientTag{}).Where("client_id = ?", duplicate.ID).Update("client_id", survivor.ID).Error; err != nil {
+		return merge, err
+	}
+
+	// Fill gaps on the survivor from the duplicate
+	if survivor.Phone == "" {
+		survivor.Phone = duplicate.Phone
+	}
+	if survivor.Address == "" {
+		survivor.Address = duplicate.Address
+	}
+	if survivor.TaxID == "" && duplicate.TaxID != "" {
+		survivor.TaxID = duplicate.TaxID
+		survivor.TaxIDScheme = duplicate.TaxIDScheme
+		survivor.TaxIDVerifiedAt = duplicate.TaxIDVerifiedAt
+	}
+	if survivor.PaymentTermID == nil {
+		survivor.PaymentTermID = duplicate.PaymentTermID
+	}
+	for key, value := range duplicate.CustomFields {
+		if _, ok := survivor.CustomFields[key]; !ok {
+			if survivor.CustomFields == nil {
+				survivor.CustomFields = models.CustomFieldValues{}
+			}
+			survivor.CustomFields[key] = value
+		}
+	}
+
+	// Clients merged into the duplicate earlier now point at the survivor
+	if err := tx.Unscoped().Model(&models.Client{}).Where("merged_into_id = ?", duplicate.ID).
+		Update("merged_into_id", survivor.ID).Error; err != nil {
+		return merge, err
+	}
+
+	if err := tx.Model(&duplicate).Update("merged_into_id", survivor.ID).Error; err != nil {
+		return merge, err
+	}
+	if err := tx.Delete(&duplicate).Error; err != nil {
+		return merge, err
+	}
+
+	return merge, tx.Create(&merge).Error
+}
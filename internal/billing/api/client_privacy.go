@@ -0,0 +1,164 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"gaetanjaminon/GoTuto/internal/billing/models"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// ExportClientData returns every piece of personal data held about a client as a JSON
+// archive (GDPR subject access request). Soft-deleted clients and invoices are included.
+func ExportClientData(db *gorm.DB) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id := c.Param("id")
+		var client models.Client
+
+		if err := db.Unscoped().Preload("Tags").First(&client, id).Error; err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Client not found"})
+			return
+		}
+
+		export := models.ClientDataExport{
+			FormatVersion: models.ClientExportFormatVersion,
+			ExportedAt:    time.Now(),
+			Client:        client,
+		}
+
+		if err := db.Where("client_id = ?", client.ID).Find(&export.Addresses).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to export addresses"})
+			return
+		}
+		if err := db.Where("client_id = ?", client.ID).Find(&export.Contacts).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to export contacts"})
+			return
+		}
+		if err := db.Unscoped().Preload("Lines").Where("client_id = ?", client.ID).Order("issue_date").Find(&export.Invoices).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to export invoices"})
+			return
+		}
+		if err := db.Where("survivor_id = ? OR merged_client_id = ?", client.ID, client.ID).Find(&export.Merges).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to export merges"})
+			return
+		}
+
+		c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=client-%d-export.json", client.ID))
+		c.JSON(http.StatusOK, export)
+	}
+}
+
+// EraseClient pseudonymizes a client's personal data (GDPR right to erasure). Invoices are
+// legally retained and keep pointing at the pseudonymized client; the client's names,
+// emails, phones, address and tax ID are redacted from invoice and line descriptions.
+// Contacts, structured addresses and merge snapshots are deleted and portal access links
+// are revoked. Clients previously merged into this one are the same data subject and are
+// erased too. Erasure cannot be undone.
+func EraseClient(db *gorm.DB) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id := c.Param("id")
+		var client models.Client
+
+		if err := db.Unscoped().First(&client, id).Error; err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Client not found"})
+			return
+		}
+		if client.IsErased() {
+			c.JSON(http.StatusConflict, gin.H{"error": "Client data has already been erased"})
+			return
+		}
+
+		var invoiceCount int64
+		err := db.Transaction(func(tx *gorm.DB) error {
+			var merged []models.Client
+			if err := tx.Unscoped().Where("merged_into_id = ? AND erased_at IS NULL", client.ID).Find(&merged).Error; err != nil {
+				return err
+			}
+
+			subjects := append([]models.Client{client}, merged...)
+			ids := make([]uint, 0, len(subjects))
+			for _, subject := range subjects {
+				ids = append(ids, subject.ID)
+			}
+
+			// Collect the identifiers to redact before the personal data is gone
+			var contacts []models.ClientContact
+			if err := tx.Where("client_id IN ?", ids).Find(&contacts).Error; err != nil {
+				return err
+			}
+			identifiers := client.PersonalIdentifiers(contacts)
+			for _, subject := range merged {
+				identifiers = append(identifiers, subject.PersonalIdentifiers(nil)...)
+			}
+
+			now := time.Now()
+			for i := range subjects {
+				subjects[i].Erase(now)
+				if err := tx.Unscoped().Model(&subjects[i]).
+					Select("name", "email", "phone", "address", "tax_id", "tax_id_scheme", "tax_id_verified_at", "custom_fields", "erased_at").
+					Updates(&subjects[i]).Error; err != nil {
+					return err
+				}
+			}
+			client = subjects[0]
+
+			if err := redactInvoiceDescriptions(tx, ids, identifiers); err != nil {
+				return err
+			}
+
+			if err := tx.Where("client_id IN ?", ids).Delete(&models.ClientContact{}).Error; err != nil {
+				return err
+			}
+			if err := tx.Where("client_id IN ?", ids).Delete(&models.ClientAddress{}).Error; err != nil {
+				return err
+			}
+			if err := tx.Model(&models.PortalToken{}).Where("client_id IN ? AND revoked_at IS NULL", ids).
+				Update("revoked_at", now).Error; err != nil {
+				return err
+			}
+			if err := tx.Model(&models.ClientMerge{}).Where("merged_client_id IN ?", ids).
+				Update("merged_snapshot", "").Error; err != nil {
+				return err
+			}
+
+			return tx.Unscoped().Model(&models.Invoice{}).Where("client_id IN ?", ids).Count(&invoiceCount).Error
+		})
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to erase client data"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"client":            client,
+			"invoices_retained": invoiceCount,
+		})
+	}
+}
+
+// redactInvoiceDescriptions removes personal identifiers from the descriptions of the
+// invoices of the given clients and of their lines, soft-deleted invoices included
+func redactInvoiceDescriptions(tx *gorm.DB, clientIDs []uint, identifiers []string) error {
+	var invoices []models.Invoice
+	if err := tx.Unscoped().Preload("Lines").Where("client_id IN ?", clientIDs).Find(&invoices).Error; err != nil {
+		return err
+	}
+
+	for _, invoice := range invoices {
+		if redacted := models.RedactPersonalData(invoice.Description, identifiers); redacted != invoice.Description {
+			if err := tx.Unscoped().Model(&invoice).UpdateColumn("description", redacted).Error; err != nil {
+				return err
+			}
+		}
+		for _, line := range invoice.Lines {
+			if redacted := models.RedactPersonalData(line.Description, identifiers); redacted != line.Description {
+				if err := tx.Model(&line).UpdateColumn("description", redacted).Error; err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
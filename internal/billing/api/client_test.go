@@ -0,0 +1,67 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"gaetanjaminon/GoTuto/internal/billing/models"
+	"gaetanjaminon/GoTuto/internal/billing/services"
+	"github.com/stretchr/testify/assert"
+)
+
+// stubTaxIDVerifier is a local TaxIDVerifier returning a canned answer
+type stubTaxIDVerifier struct {
+	valid   bool
+	offline bool // Answers from format checks only, like OfflineTaxIDVerifier
+	err     error
+}
+
+func (s stubTaxIDVerifier) Verify(ctx context.Context, scheme models.TaxIDScheme, taxID string) (*services.TaxIDVerification, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+	result := &services.TaxIDVerification{TaxID: taxID, Scheme: scheme, Valid: s.valid, Source: "stub", Registry: !s.offline, CheckedAt: time.Now()}
+	if !s.valid {
+		result.Reason = "not registered"
+	}
+	return result, nil
+}
+
+func TestVerifyClientTaxID(t *testing.T) {
+	t.Run("registered number is marked verified", func(t *testing.T) {
+		client := models.Client{TaxID: "de 136 695 976"}
+		err := verifyClientTaxID(context.Background(), stubTaxIDVerifier{valid: true}, &client)
+		assert.NoError(t, err)
+		assert.Equal(t, "DE136695976", client.TaxID)
+		assert.Equal(t, models.TaxIDSchemeEUVAT, client.TaxIDScheme)
+		assert.True(t, client.HasVerifiedTaxID())
+	})
+
+	t.Run("unregistered number is rejected", func(t *testing.T) {
+		client := models.Client{TaxID: "DE136695976"}
+		err := verifyClientTaxID(context.Background(), stubTaxIDVerifier{valid: false}, &client)
+		assert.EqualError(t, err, "not registered")
+	})
+
+	t.Run("malformed number never reaches the verifier", func(t *testing.T) {
+		client := models.Client{TaxID: "DE136695977"}
+		err := verifyClientTaxID(context.Background(), stubTaxIDVerifier{valid: true}, &client)
+		assert.Error(t, err)
+	})
+
+	t.Run("format check alone keeps the number unverified", func(t *testing.T) {
+		client := models.Client{TaxID: "DE136695976"}
+		err := verifyClientTaxID(context.Background(), stubTaxIDVerifier{valid: true, offline: true}, &client)
+		assert.NoError(t, err)
+		assert.False(t, client.HasVerifiedTaxID())
+	})
+
+	t.Run("unreachable verifier keeps the number unverified", func(t *testing.T) {
+		client := models.Client{TaxID: "DE136695976"}
+		err := verifyClientTaxID(context.Background(), stubTaxIDVerifier{err: errors.New("timeout")}, &client)
+		assert.NoError(t, err)
+		assert.False(t, client.HasVerifiedTaxID())
+	})
+}
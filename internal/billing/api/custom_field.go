@@ -0,0 +1,149 @@
+package api
+
+import (
+	"net/http"
+	"strings"
+
+	"gaetanjaminon/GoTuto/internal/billing/models"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// GetCustomFields retrieves all client custom field definitions
+func GetCustomFields(db *gorm.DB) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var definitions []models.CustomFieldDefinition
+
+		if err := db.Order("key ASC").Find(&definitions).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve custom fields"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"custom_fields": definitions})
+	}
+}
+
+// GetCustomField retrieves a single custom field definition by ID
+func GetCustomField(db *gorm.DB) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id := c.Param("id")
+		var definition models.CustomFieldDefinition
+
+		if err := db.First(&definition, id).Error; err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Custom field not found"})
+			return
+		}
+
+		c.JSON(http.StatusOK, definition)
+	}
+}
+
+// CreateCustomField defines a new typed custom field for clients
+func CreateCustomField(db *gorm.DB) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req models.CreateCustomFieldRequest
+
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		definition := models.CustomFieldDefinition{
+			Key:      strings.ToLower(strings.TrimSpace(req.Key)),
+			Label:    req.Label,
+			Type:     req.Type,
+			Options:  req.Options,
+			Required: req.Required,
+		}
+
+		if err := definition.Validate(); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		// Check if key already exists
+		var existing models.CustomFieldDefinition
+		if err := db.Where("key = ?", definition.Key).First(&existing).Error; err == nil {
+			c.JSON(http.StatusConflict, gin.H{"error": "Custom field with this key already exists"})
+			return
+		}
+
+		if err := db.Create(&definition).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create custom field"})
+			return
+		}
+
+		c.JSON(http.StatusCreated, definition)
+	}
+}
+
+// UpdateCustomField updates the label, options or required flag of a custom field.
+// Values already stored on clients are re-validated the next time they change.
+func UpdateCustomField(db *gorm.DB) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id := c.Param("id")
+		var definition models.CustomFieldDefinition
+
+		if err := db.First(&definition, id).Error; err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Custom field not found"})
+			return
+		}
+
+		var req models.UpdateCustomFieldRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		// Update only provided fields
+		if req.Label != "" {
+			definition.Label = req.Label
+		}
+		if req.Options != nil {
+			definition.Options = req.Options
+		}
+		if req.Required != nil {
+			definition.Required = *req.Required
+		}
+
+		if err := definition.Validate(); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		if err := db.Save(&definition).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update custom field"})
+			return
+		}
+
+		c.JSON(http.StatusOK, definition)
+	}
+}
+
+// DeleteCustomField deletes a custom field definition and removes its values from all clients
+func DeleteCustomField(db *gorm.DB) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id := c.Param("id")
+		var definition models.CustomFieldDefinition
+
+		if err := db.First(&definition, id).Error; err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Custom field not found"})
+			return
+		}
+
+		err := db.Transaction(func(tx *gorm.DB) error {
+			if err := tx.Unscoped().Model(&models.Client{}).Where("custom_fields ->> CAST(? AS TEXT) IS NOT NULL", definition.Key).
+				Update("custom_fields", gorm.Expr("custom_fields - CAST(? AS TEXT)", definition.Key)).Error; err != nil {
+				return err
+			}
+			return tx.Delete(&definition).Error
+		})
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete custom field"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"message": "Custom field deleted successfully"})
+	}
+}
@@ -0,0 +1,192 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+
+	"gaetanjaminon/GoTuto/internal/billing/models"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// GetDiscountCodes retrieves all discount codes with optional pagination
+func GetDiscountCodes(db *gorm.DB) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var codes []models.DiscountCode
+
+		// Optional pagination
+		page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+		limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+		offset := (page - 1) * limit
+
+		// Optional active filter
+		isActive := c.Query("is_active")
+		query := db.Limit(limit).Offset(offset).Order("code ASC")
+
+		if isActive != "" {
+			query = query.Where("is_active = ?", isActive == "true")
+		}
+
+		if err := query.Find(&codes).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve discount codes"})
+			return
+		}
+
+		// Get total count for pagination
+		var total int64
+		countQuery := db.Model(&models.DiscountCode{})
+		if isActive != "" {
+			countQuery = countQuery.Where("is_active = ?", isActive == "true")
+		}
+		countQuery.Count(&total)
+
+		c.JSON(http.StatusOK, gin.H{
+			"discount_codes": codes,
+			"pagination": gin.H{
+				"page":  page,
+				"limit": limit,
+				"total": total,
+			},
+		})
+	}
+}
+
+// GetDiscountCode retrieves a single discount code by ID
+func GetDiscountCode(db *gorm.DB) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id := c.Param("id")
+		var code models.DiscountCode
+
+		if err := db.First(&code, id).Error; err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Discount code not found"})
+			return
+		}
+
+		c.JSON(http.StatusOK, code)
+	}
+}
+
+// CreateDiscountCode creates a new reusable discount code
+func CreateDiscountCode(db *gorm.DB) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req models.CreateDiscountCodeRequest
+
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		if err := req.Discount.Validate(); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		if req.ValidFrom != nil && req.ValidUntil != nil && req.ValidUntil.Before(*req.ValidFrom) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "valid_until cannot be before valid_from"})
+			return
+		}
+
+		code := models.DiscountCode{
+			Code:        models.NormalizeDiscountCode(req.Code),
+			Description: req.Description,
+			Discount:    req.Discount,
+			ValidFrom:   req.ValidFrom,
+			ValidUntil:  req.ValidUntil,
+			MaxUses:     req.MaxUses,
+			IsActive:    true,
+		}
+		if req.IsActive != nil {
+			code.IsActive = *req.IsActive
+		}
+
+		// Check if code already exists
+		var existing models.DiscountCode
+		if err := db.Where("code = ?", code.Code).First(&existing).Error; err == nil {
+			c.JSON(http.StatusConflict, gin.H{"error": "Discount code already exists"})
+			return
+		}
+
+		if err := db.Create(&code).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create discount code"})
+			return
+		}
+
+		c.JSON(http.StatusCreated, code)
+	}
+}
+
+// UpdateDiscountCode updates an existing discount code
+func UpdateDiscountCode(db *gorm.DB) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id := c.Param("id")
+		var code models.DiscountCode
+
+		if err := db.First(&code, id).Error; err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Discount code not found"})
+			return
+		}
+
+		var req models.UpdateDiscountCodeRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		// Update only provided fields
+		if req.Description != "" {
+			code.Description = req.Description
+		}
+		if req.Discount != nil {
+			if err := req.Discount.Validate(); err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+				return
+			}
+			code.Discount = *req.Discount
+		}
+		if req.ValidFrom != nil {
+			code.ValidFrom = req.ValidFrom
+		}
+		if req.ValidUntil != nil {
+			code.ValidUntil = req.ValidUntil
+		}
+		if req.MaxUses != nil {
+			code.MaxUses = *req.MaxUses
+		}
+		if req.IsActive != nil {
+			code.IsActive = *req.IsActive
+		}
+
+		if code.ValidFrom != nil && code.ValidUntil != nil && code.ValidUntil.Before(*code.ValidFrom) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "valid_until cannot be before valid_from"})
+			return
+		}
+
+		if err := db.Save(&code).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update discount code"})
+			return
+		}
+
+		c.JSON(http.StatusOK, code)
+	}
+}
+
+// DeleteDiscountCode soft deletes a discount code; invoices keep their applied discount
+func DeleteDiscountCode(db *gorm.DB) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id := c.Param("id")
+		var code models.DiscountCode
+
+		if err := db.First(&code, id).Error; err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Discount code not found"})
+			return
+		}
+
+		if err := db.Delete(&code).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete discount code"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"message": "Discount code deleted successfully"})
+	}
+}
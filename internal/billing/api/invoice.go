@@ -1,16 +1,21 @@
 package api
 
 import (
+	"errors"
+	"fmt"
 	"net/http"
 	"strconv"
 	"time"
 	
+	"gaetanjaminon/GoTuto/internal/billing/config"
 	"gaetanjaminon/GoTuto/internal/billing/models"
 	
 	"github.com/gin-gonic/gin"
 	"gorm.io/gorm"
 )
 
+var errDiscountCodeExhausted = errors.New("discount code has reached its usage limit")
+
 // GetInvoices retrieves all invoices with optional filters
 func GetInvoices(db *gorm.DB) gin.HandlerFunc {
 	return func(c *gin.Context) {
@@ -25,12 +30,26 @@ func GetInvoices(db *gorm.DB) gin.HandlerFunc {
 		clientID := c.Query("client_id")
 		status := c.Query("status")
 		
+		// Restrict to the clients of a saved segment
+		var segment *models.ClientSegment
+		if segmentID := c.Query("segment_id"); segmentID != "" {
+			segment = &models.ClientSegment{}
+			if err := db.First(segment, segmentID).Error; err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "Segment not found"})
+				return
+			}
+		}
+		
 		query := db.Preload("Client").Limit(limit).Offset(offset)
 		
 		if clientID != "" {
 			query = query.Where("client_id = ?", clientID)
 		}
 		
+		if segment != nil {
+			query = query.Where("client_id IN (?)", clientIDsMatching(db, segment.Filter))
+		}
+		
 		if status != "" {
 			query = query.Where("status = ?", status)
 		}
@@ -46,6 +65,9 @@ func GetInvoices(db *gorm.DB) gin.HandlerFunc {
 		if clientID != "" {
 			countQuery = countQuery.Where("client_id = ?", clientID)
 		}
+		if segment != nil {
+			countQuery = countQuery.Where("client_id IN (?)", clientIDsMatching(db, segment.Filter))
+		}
 		if status != "" {
 			countQuery = countQuery.Where("status = ?", status)
 		}
@@ -68,7 +90,7 @@ func GetInvoice(db *gorm.DB) gin.HandlerFunc {
 		id := c.Param("id")
 		var invoice models.Invoice
 		
-		if err := db.Preload("Client").First(&invoice, id).Error; err != nil {
+		if err := db.Preload("Client").Preload("Lines").Preload("PaymentTerm").First(&invoice, id).Error; err != nil {
 			c.JSON(http.StatusNotFound, gin.H{"error": "Invoice not found"})
 			return
 		}
@@ -77,8 +99,9 @@ func GetInvoice(db *gorm.DB) gin.HandlerFunc {
 	}
 }
 
-// CreateInvoice creates a new invoice
-func CreateInvoice(db *gorm.DB) gin.HandlerFunc {
+// CreateInvoice creates a new invoice. The due date and early-payment discount are
+// computed from the requested payment terms, the client's terms, or the configured default.
+func CreateInvoice(db *gorm.DB, invoiceCfg config.InvoiceConfig) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		var req models.CreateInvoiceRequest
 		
@@ -107,6 +130,7 @@ func CreateInvoice(db *gorm.DB) gin.HandlerFunc {
 			IssueDate:   req.IssueDate,
 			DueDate:     req.DueDate,
 			Description: req.Description,
+			Lines:       buildInvoiceLines(req.Lines),
 		}
 		
 		// Set default status if not provided
@@ -114,13 +138,95 @@ func CreateInvoice(db *gorm.DB) gin.HandlerFunc {
 			invoice.Status = models.InvoiceStatusDraft
 		}
 		
-		if err := db.Create(&invoice).Error; err != nil {
+		// Apply invoice-level discount, either given explicitly or through a discount code
+		if req.Discount != nil && req.DiscountCode != "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Provide either discount or discount_code, not both"})
+			return
+		}
+		if req.Discount != nil {
+			invoice.Discount = *req.Discount
+		}
+		
+		var discountCode *models.DiscountCode
+		if req.DiscountCode != "" {
+			discountCode = &models.DiscountCode{}
+			code := models.NormalizeDiscountCode(req.DiscountCode)
+			if err := db.Where("code = ?", code).First(discountCode).Error; err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "Discount code not found"})
+				return
+			}
+			// Validity is checked at redemption time, not against the invoice date
+			if err := discountCode.CanRedeem(time.Now()); err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+				return
+			}
+			invoice.Discount = discountCode.Discount
+			invoice.DiscountCode = discountCode.Code
+		}
+		
+		if err := validateInvoiceDiscounts(invoice); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		
+		invoice.CalculateTotals()
+		if invoice.Amount <= 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invoice amount must be greater than zero"})
+			return
+		}
+		
+		// Resolve payment terms: request override, then client default, then configured net days
+		term := models.NetPaymentTerm(invoiceCfg.PaymentTermsDays)
+		termID := req.PaymentTermID
+		if termID == nil {
+			termID = client.PaymentTermID
+		}
+		if termID != nil {
+			var assigned models.PaymentTerm
+			if err := db.First(&assigned, *termID).Error; err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "Payment term not found"})
+				return
+			}
+			if !assigned.IsActive {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "Payment term " + assigned.Code + " is not active"})
+				return
+			}
+			term = assigned
+			invoice.PaymentTermID = &assigned.ID
+		}
+		invoice.ApplyPaymentTerm(term)
+		
+		if invoice.DueDate.Before(invoice.IssueDate) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Due date cannot be before issue date"})
+			return
+		}
+		
+		err := db.Transaction(func(tx *gorm.DB) error {
+			if discountCode != nil {
+				// Count the redemption atomically so concurrent invoices cannot exceed max_uses
+				result := tx.Model(&models.DiscountCode{}).
+					Where("id = ? AND (max_uses = 0 OR used_count < max_uses)", discountCode.ID).
+					UpdateColumn("used_count", gorm.Expr("used_count + 1"))
+				if result.Error != nil {
+					return result.Error
+				}
+				if result.RowsAffected == 0 {
+					return errDiscountCodeExhausted
+				}
+			}
+			return tx.Create(&invoice).Error
+		})
+		if errors.Is(err, errDiscountCodeExhausted) {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
+		if err != nil {
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create invoice"})
 			return
 		}
 		
 		// Load client data for response
-		db.Preload("Client").First(&invoice, invoice.ID)
+		db.Preload("Client").Preload("Lines").Preload("PaymentTerm").First(&invoice, invoice.ID)
 		
 		c.JSON(http.StatusCreated, invoice)
 	}
@@ -132,7 +238,7 @@ func UpdateInvoice(db *gorm.DB) gin.HandlerFunc {
 		id := c.Param("id")
 		var invoice models.Invoice
 		
-		if err := db.First(&invoice, id).Error; err != nil {
+		if err := db.Preload("Lines").First(&invoice, id).Error; err != nil {
 			c.JSON(http.StatusNotFound, gin.H{"error": "Invoice not found"})
 			return
 		}
@@ -143,6 +249,31 @@ func UpdateInvoice(db *gorm.DB) gin.HandlerFunc {
 			return
 		}
 		
+		// Amount is derived from lines when the invoice has any
+		if req.Amount > 0 && (len(invoice.Lines) > 0 || len(req.Lines) > 0) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Amount is computed from invoice lines and cannot be set directly"})
+			return
+		}
+		
+		// Payment terms: a newly assigned term applies as it is now, otherwise the rules
+		// recorded on the invoice are kept
+		term, hasTerm := invoice.AppliedPaymentTerm()
+		termChanged := false
+		if req.PaymentTermID != nil {
+			var assigned models.PaymentTerm
+			if err := db.First(&assigned, *req.PaymentTermID).Error; err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "Payment term not found"})
+				return
+			}
+			if !assigned.IsActive {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "Payment term " + assigned.Code + " is not active"})
+				return
+			}
+			term, hasTerm, termChanged = assigned, true, true
+			invoice.PaymentTermID = &assigned.ID
+			invoice.PaymentTerm = nil
+		}
+		
 		// Update only provided fields
 		if req.Amount > 0 {
 			invoice.Amount = req.Amount
@@ -150,6 +281,16 @@ func UpdateInvoice(db *gorm.DB) gin.HandlerFunc {
 		if req.Status != "" {
 			invoice.Status = req.Status
 		}
+		issueDateChanged := !req.IssueDate.IsZero() && !req.IssueDate.Equal(invoice.IssueDate)
+		if req.DueDate.IsZero() && (termChanged || issueDateChanged) {
+			// Recompute the due date from the payment terms, or keep the payment
+			// interval of invoices without recorded terms
+			if hasTerm {
+				invoice.DueDate = time.Time{}
+			} else {
+				invoice.DueDate = req.IssueDate.Add(invoice.DueDate.Sub(invoice.IssueDate))
+			}
+		}
 		if !req.IssueDate.IsZero() {
 			invoice.IssueDate = req.IssueDate
 		}
@@ -159,14 +300,58 @@ func UpdateInvoice(db *gorm.DB) gin.HandlerFunc {
 		if req.Description != "" {
 			invoice.Description = req.Description
 		}
+		if req.Discount != nil {
+			invoice.Discount = *req.Discount
+			// A manual discount replaces the one granted by a code
+			invoice.DiscountCode = ""
+		}
+		replaceLines := req.Lines != nil
+		if replaceLines {
+			invoice.Lines = buildInvoiceLines(req.Lines)
+		}
+		
+		if err := validateInvoiceDiscounts(invoice); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		
+		invoice.CalculateTotals()
+		if invoice.Amount <= 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invoice amount must be greater than zero"})
+			return
+		}
 		
-		if err := db.Save(&invoice).Error; err != nil {
+		// Keep the early-payment discount in line with the new total
+		if hasTerm {
+			invoice.ApplyPaymentTerm(term)
+		}
+		if invoice.DueDate.IsZero() {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Due date is required"})
+			return
+		}
+		if invoice.DueDate.Before(invoice.IssueDate) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Due date cannot be before issue date"})
+			return
+		}
+		
+		err := db.Transaction(func(tx *gorm.DB) error {
+			if replaceLines {
+				if err := tx.Where("invoice_id = ?", invoice.ID).Delete(&models.InvoiceLine{}).Error; err != nil {
+					return err
+				}
+				for i := range invoice.Lines {
+					invoice.Lines[i].InvoiceID = invoice.ID
+				}
+			}
+			return tx.Session(&gorm.Session{FullSaveAssociations: true}).Save(&invoice).Error
+		})
+		if err != nil {
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update invoice"})
 			return
 		}
 		
 		// Load client data for response
-		db.Preload("Client").First(&invoice, invoice.ID)
+		db.Preload("Client").Preload("Lines").Preload("PaymentTerm").First(&invoice, invoice.ID)
 		
 		c.JSON(http.StatusOK, invoice)
 	}
@@ -201,7 +386,7 @@ func DeleteInvoice(db *gorm.DB) gin.HandlerFunc {
 // GetInvoicesByClient retrieves all invoices for a specific client
 func GetInvoicesByClient(db *gorm.DB) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		clientID := c.Param("client_id")
+		clientID := c.Param("id")
 		
 		// Verify client exists
 		var client models.Client
@@ -221,4 +406,34 @@ func GetInvoicesByClient(db *gorm.DB) gin.HandlerFunc {
 			"invoices": invoices,
 		})
 	}
-}
\ No newline at end of file
+}
+
+// buildInvoiceLines converts line requests into invoice lines
+func buildInvoiceLines(reqs []models.InvoiceLineRequest) []models.InvoiceLine {
+	lines := make([]models.InvoiceLine, 0, len(reqs))
+	for _, req := range reqs {
+		line := models.InvoiceLine{
+			Description: req.Description,
+			Quantity:    req.Quantity,
+			UnitPrice:   req.UnitPrice,
+		}
+		if req.Discount != nil {
+			line.Discount = *req.Discount
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+// validateInvoiceDiscounts checks the invoice-level and line-level discounts
+func validateInvoiceDiscounts(invoice models.Invoice) error {
+	if err := invoice.Discount.Validate(); err != nil {
+		return err
+	}
+	for i, line := range invoice.Lines {
+		if err := line.Discount.Validate(); err != nil {
+			return fmt.Errorf("line %d: %w", i+1, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,87 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+
+	"gaetanjaminon/GoTuto/internal/billing/config"
+	"gaetanjaminon/GoTuto/internal/billing/models"
+	"gaetanjaminon/GoTuto/internal/billing/services"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// DownloadInvoicePDF renders an invoice as a PDF document
+func DownloadInvoicePDF(db *gorm.DB, invoiceCfg config.InvoiceConfig) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		invoice, err := loadInvoiceForDocument(db.Where("invoices.id = ?", c.Param("id")))
+		if err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Invoice not found"})
+			return
+		}
+		sendInvoiceDocument(c, newInvoiceDocument(invoice, invoiceCfg), "pdf")
+	}
+}
+
+// DownloadInvoiceUBL renders an invoice as a UBL 2.1 XML e-invoice
+func DownloadInvoiceUBL(db *gorm.DB, invoiceCfg config.InvoiceConfig) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		invoice, err := loadInvoiceForDocument(db.Where("invoices.id = ?", c.Param("id")))
+		if err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Invoice not found"})
+			return
+		}
+		sendInvoiceDocument(c, newInvoiceDocument(invoice, invoiceCfg), "ubl")
+	}
+}
+
+// loadInvoiceForDocument loads the invoice matched by query with everything printed on it.
+// The client is loaded even when soft-deleted, as its invoices are retained.
+func loadInvoiceForDocument(query *gorm.DB) (models.Invoice, error) {
+	var invoice models.Invoice
+	err := query.
+		Preload("Client", func(tx *gorm.DB) *gorm.DB { return tx.Unscoped() }).
+		Preload("Client.Addresses").
+		Preload("Lines", func(tx *gorm.DB) *gorm.DB { return tx.Order("id") }).
+		First(&invoice).Error
+	return invoice, err
+}
+
+func newInvoiceDocument(invoice models.Invoice, invoiceCfg config.InvoiceConfig) services.InvoiceDocument {
+	issuer := services.InvoiceParty{
+		Name:       invoiceCfg.Issuer.Name,
+		Email:      invoiceCfg.Issuer.Email,
+		TaxID:      invoiceCfg.Issuer.TaxID,
+		Street:     invoiceCfg.Issuer.Street,
+		City:       invoiceCfg.Issuer.City,
+		PostalCode: invoiceCfg.Issuer.PostalCode,
+		Country:    invoiceCfg.Issuer.Country,
+	}
+	return services.NewInvoiceDocument(invoice, issuer, invoiceCfg.DefaultCurrency)
+}
+
+// sendInvoiceDocument renders the document in the given format ("pdf" or "ubl") as a download
+func sendInvoiceDocument(c *gin.Context, doc services.InvoiceDocument, format string) {
+	var (
+		body        []byte
+		err         error
+		contentType string
+		extension   string
+	)
+	switch format {
+	case "pdf":
+		body, err = services.RenderInvoicePDF(doc)
+		contentType, extension = "application/pdf", "pdf"
+	default:
+		body, err = services.RenderInvoiceUBL(doc)
+		contentType, extension = "application/xml", "xml"
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to render invoice"})
+		return
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%s.%s", doc.FileName(), extension))
+	c.Data(http.StatusOK, contentType, body)
+}
@@ -0,0 +1,170 @@
+package api
+
+import (
+	"net/http"
+	"strings"
+
+	"gaetanjaminon/GoTuto/internal/billing/models"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// GetPaymentTerms retrieves all payment terms
+func GetPaymentTerms(db *gorm.DB) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var terms []models.PaymentTerm
+
+		query := db.Order("code ASC")
+		if isActive := c.Query("is_active"); isActive != "" {
+			query = query.Where("is_active = ?", isActive == "true")
+		}
+
+		if err := query.Find(&terms).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve payment terms"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"payment_terms": terms})
+	}
+}
+
+// GetPaymentTerm retrieves a single payment term by ID
+func GetPaymentTerm(db *gorm.DB) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id := c.Param("id")
+		var term models.PaymentTerm
+
+		if err := db.First(&term, id).Error; err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Payment term not found"})
+			return
+		}
+
+		c.JSON(http.StatusOK, term)
+	}
+}
+
+// CreatePaymentTerm creates a new named payment term
+func CreatePaymentTerm(db *gorm.DB) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req models.CreatePaymentTermRequest
+
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		term := models.PaymentTerm{
+			Code:                        strings.ToUpper(strings.TrimSpace(req.Code)),
+			Name:                        req.Name,
+			Type:                        req.Type,
+			Days:                        req.Days,
+			EarlyPaymentDays:            req.EarlyPaymentDays,
+			EarlyPaymentDiscountPercent: req.EarlyPaymentDiscountPercent,
+			IsActive:                    true,
+		}
+
+		if err := term.Validate(); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		// Check if code already exists
+		var existing models.PaymentTerm
+		if err := db.Where("code = ?", term.Code).First(&existing).Error; err == nil {
+			c.JSON(http.StatusConflict, gin.H{"error": "Payment term with this code already exists"})
+			return
+		}
+
+		if err := db.Create(&term).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create payment term"})
+			return
+		}
+
+		c.JSON(http.StatusCreated, term)
+	}
+}
+
+// UpdatePaymentTerm updates an existing payment term. Invoices already issued keep
+// their computed due date and early-payment discount.
+func UpdatePaymentTerm(db *gorm.DB) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id := c.Param("id")
+		var term models.PaymentTerm
+
+		if err := db.First(&term, id).Error; err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Payment term not found"})
+			return
+		}
+
+		var req models.UpdatePaymentTermRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		// Update only provided fields
+		if req.Name != "" {
+			term.Name = req.Name
+		}
+		if req.Type != "" {
+			term.Type = req.Type
+		}
+		if req.Days != nil {
+			term.Days = *req.Days
+		}
+		if req.EarlyPaymentDays != nil {
+			term.EarlyPaymentDays = *req.EarlyPaymentDays
+		}
+		if req.EarlyPaymentDiscountPercent != nil {
+			term.EarlyPaymentDiscountPercent = *req.EarlyPaymentDiscountPercent
+		}
+		if req.IsActive != nil {
+			term.IsActive = *req.IsActive
+		}
+
+		if err := term.Validate(); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		if err := db.Save(&term).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update payment term"})
+			return
+		}
+
+		c.JSON(http.StatusOK, term)
+	}
+}
+
+// DeletePaymentTerm soft deletes a payment term that is not assigned to any client
+func DeletePaymentTerm(db *gorm.DB) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id := c.Param("id")
+		var term models.PaymentTerm
+
+		if err := db.First(&term, id).Error; err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Payment term not found"})
+			return
+		}
+
+		// Check if payment term is assigned to clients
+		var clientCount int64
+		db.Model(&models.Client{}).Where("payment_term_id = ?", id).Count(&clientCount)
+
+		if clientCount > 0 {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":        "Cannot delete payment term assigned to clients",
+				"client_count": clientCount,
+			})
+			return
+		}
+
+		if err := db.Delete(&term).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete payment term"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"message": "Payment term deleted successfully"})
+	}
+}
@@ -0,0 +1,243 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"gaetanjaminon/GoTuto/internal/billing/config"
+	"gaetanjaminon/GoTuto/internal/billing/models"
+	"gaetanjaminon/GoTuto/internal/billing/services"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// portalClientIDKey is the gin context key holding the client authenticated by PortalAuth
+const portalClientIDKey = "portal_client_id"
+
+// PortalAuth authenticates portal requests with a signed access token, passed as a Bearer
+// token or as the token query parameter of an access link. The token must be validly
+// signed, unexpired and not revoked, and its client must still exist and not be erased.
+// Every portal handler is scoped to that client.
+func PortalAuth(db *gorm.DB, signer *services.PortalTokenSigner) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		raw := c.Query("token")
+		if header := c.GetHeader("Authorization"); strings.HasPrefix(header, "Bearer ") {
+			raw = strings.TrimPrefix(header, "Bearer ")
+		}
+		if raw == "" {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Access token required"})
+			return
+		}
+
+		now := time.Now()
+		claims, err := signer.Verify(raw, now)
+		if err != nil {
+			message := "Invalid access token"
+			if errors.Is(err, services.ErrPortalTokenExpired) {
+				message = "Access token has expired"
+			}
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": message})
+			return
+		}
+
+		var token models.PortalToken
+		if err := db.Where("id = ? AND client_id = ?", claims.TokenID, claims.ClientID).First(&token).Error; err != nil || !token.IsActive(now) {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Access token has been revoked"})
+			return
+		}
+
+		var client models.Client
+		if err := db.First(&client, claims.ClientID).Error; err != nil || client.IsErased() {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Access token has been revoked"})
+			return
+		}
+
+		db.Model(&token).UpdateColumn("last_used_at", now)
+
+		c.Set(portalClientIDKey, client.ID)
+		c.Next()
+	}
+}
+
+// portalInvoices returns a query restricted to the authenticated client's invoices.
+// Drafts are never shown in the portal.
+func portalInvoices(c *gin.Context, db *gorm.DB) *gorm.DB {
+	return db.Where("invoices.client_id = ? AND invoices.status <> ?", c.GetUint(portalClientIDKey), models.InvoiceStatusDraft)
+}
+
+// GetPortalClient returns the authenticated client's own profile
+func GetPortalClient(db *gorm.DB) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var client models.Client
+
+		if err := db.Preload("Addresses").First(&client, c.GetUint(portalClientIDKey)).Error; err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Client not found"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"id":        client.ID,
+			"name":      client.Name,
+			"email":     client.Email,
+			"tax_id":    client.TaxID,
+			"addresses": client.Addresses,
+		})
+	}
+}
+
+// GetPortalInvoices lists the authenticated client's invoices
+func GetPortalInvoices(db *gorm.DB) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var invoices []models.Invoice
+
+		// Pagination
+		page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+		limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+		if page < 1 {
+			page = 1
+		}
+		if limit < 1 || limit > 100 {
+			limit = 10
+		}
+		offset := (page - 1) * limit
+
+		query := portalInvoices(c, db)
+		if status := c.Query("status"); status != "" {
+			query = query.Where("invoices.status = ?", status)
+		}
+		query = query.Session(&gorm.Session{}) // Shared by the count and the page query
+
+		var total int64
+		if err := query.Model(&models.Invoice{}).Count(&total).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve invoices"})
+			return
+		}
+		if err := query.Order("issue_date DESC").Limit(limit).Offset(offset).Find(&invoices).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve invoices"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"invoices": invoices,
+			"pagination": gin.H{
+				"page":  page,
+				"limit": limit,
+				"total": total,
+			},
+		})
+	}
+}
+
+// GetPortalInvoice retrieves one of the authenticated client's invoices with its lines
+func GetPortalInvoice(db *gorm.DB) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var invoice models.Invoice
+
+		if err := portalInvoices(c, db).Preload("Lines").Where("invoices.id = ?", c.Param("id")).First(&invoice).Error; err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Invoice not found"})
+			return
+		}
+
+		c.JSON(http.StatusOK, invoice)
+	}
+}
+
+// DownloadPortalInvoicePDF renders one of the authenticated client's invoices as PDF
+func DownloadPortalInvoicePDF(db *gorm.DB, invoiceCfg config.InvoiceConfig) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		invoice, err := loadInvoiceForDocument(portalInvoices(c, db).Where("invoices.id = ?", c.Param("id")))
+		if err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Invoice not found"})
+			return
+		}
+		sendInvoiceDocument(c, newInvoiceDocument(invoice, invoiceCfg), "pdf")
+	}
+}
+
+// DownloadPortalInvoiceUBL renders one of the authenticated client's invoices as UBL XML
+func DownloadPortalInvoiceUBL(db *gorm.DB, invoiceCfg config.InvoiceConfig) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		invoice, err := loadInvoiceForDocument(portalInvoices(c, db).Where("invoices.id = ?", c.Param("id")))
+		if err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Invoice not found"})
+			return
+		}
+		sendInvoiceDocument(c, newInvoiceDocument(invoice, invoiceCfg), "ubl")
+	}
+}
+
+// GetPortalStatement returns the authenticated client's statement, optionally limited to
+// invoices issued between from and to (YYYY-MM-DD, inclusive)
+func GetPortalStatement(db *gorm.DB, invoiceCfg config.InvoiceConfig) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		query := portalInvoices(c, db)
+
+		var from, to *time.Time
+		if value := c.Query("from"); value != "" {
+			date, err := time.Parse("2006-01-02", value)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "from must be a date (YYYY-MM-DD)"})
+				return
+			}
+			from = &date
+			query = query.Where("invoices.issue_date >= ?", date)
+		}
+		if value := c.Query("to"); value != "" {
+			date, err := time.Parse("2006-01-02", value)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "to must be a date (YYYY-MM-DD)"})
+				return
+			}
+			to = &date
+			query = query.Where("invoices.issue_date < ?", date.AddDate(0, 0, 1))
+		}
+
+		statement, ok := buildPortalStatement(c, db, query, invoiceCfg)
+		if !ok {
+			return
+		}
+		statement.From, statement.To = from, to
+
+		c.JSON(http.StatusOK, statement)
+	}
+}
+
+// GetPortalBalance returns the authenticated client's outstanding and overdue balance
+func GetPortalBalance(db *gorm.DB, invoiceCfg config.InvoiceConfig) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		statement, ok := buildPortalStatement(c, db, portalInvoices(c, db), invoiceCfg)
+		if !ok {
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"client_id":       statement.ClientID,
+			"currency":        statement.Currency,
+			"balance":         statement.Balance,
+			"overdue_balance": statement.OverdueBalance,
+			"generated_at":    statement.GeneratedAt,
+		})
+	}
+}
+
+// buildPortalStatement builds the statement of the invoices matched by query; on failure
+// it writes the error response and returns false
+func buildPortalStatement(c *gin.Context, db *gorm.DB, query *gorm.DB, invoiceCfg config.InvoiceConfig) (models.ClientStatement, bool) {
+	var client models.Client
+	if err := db.First(&client, c.GetUint(portalClientIDKey)).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Client not found"})
+		return models.ClientStatement{}, false
+	}
+
+	var invoices []models.Invoice
+	if err := query.Find(&invoices).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to build statement"})
+		return models.ClientStatement{}, false
+	}
+
+	return models.BuildClientStatement(client, invoices, invoiceCfg.DefaultCurrency, time.Now()), true
+}
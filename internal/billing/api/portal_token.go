@@ -0,0 +1,127 @@
+package api
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"gaetanjaminon/GoTuto/internal/billing/config"
+	"gaetanjaminon/GoTuto/internal/billing/models"
+	"gaetanjaminon/GoTuto/internal/billing/services"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// GetPortalTokens lists the portal access links issued to a client, including revoked and expired ones
+func GetPortalTokens(db *gorm.DB) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		clientID := c.Param("id")
+		var client models.Client
+
+		if err := db.First(&client, clientID).Error; err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Client not found"})
+			return
+		}
+
+		var tokens []models.PortalToken
+		if err := db.Where("client_id = ?", client.ID).Order("created_at DESC").Find(&tokens).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve portal tokens"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"portal_tokens": tokens})
+	}
+}
+
+// CreatePortalToken issues a signed, expiring access link to the client's portal. The
+// signed token is only returned here; it cannot be retrieved again, only revoked.
+func CreatePortalToken(db *gorm.DB, portalCfg config.PortalConfig, signer *services.PortalTokenSigner) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		clientID := c.Param("id")
+		var client models.Client
+
+		if err := db.First(&client, clientID).Error; err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Client not found"})
+			return
+		}
+		if client.IsErased() {
+			c.JSON(http.StatusConflict, gin.H{"error": "Client data has been erased"})
+			return
+		}
+
+		var req models.CreatePortalTokenRequest
+		if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		ttl := portalCfg.TokenTTL
+		if req.TTLHours > 0 {
+			ttl = time.Duration(req.TTLHours) * time.Hour
+		}
+		if ttl > portalCfg.MaxTokenTTL {
+			ttl = portalCfg.MaxTokenTTL
+		}
+
+		record := models.PortalToken{
+			ClientID:  client.ID,
+			Label:     req.Label,
+			ExpiresAt: time.Now().Add(ttl).Truncate(time.Second),
+		}
+
+		var token string
+		err := db.Transaction(func(tx *gorm.DB) error {
+			if err := tx.Create(&record).Error; err != nil {
+				return err
+			}
+			var err error
+			token, err = signer.Sign(services.PortalClaims{
+				TokenID:   record.ID,
+				ClientID:  client.ID,
+				ExpiresAt: record.ExpiresAt,
+			})
+			return err
+		})
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create portal token"})
+			return
+		}
+
+		response := gin.H{
+			"id":         record.ID,
+			"token":      token,
+			"expires_at": record.ExpiresAt,
+		}
+		if portalCfg.BaseURL != "" {
+			response["url"] = strings.TrimRight(portalCfg.BaseURL, "/") + "?token=" + url.QueryEscape(token)
+		}
+		c.JSON(http.StatusCreated, response)
+	}
+}
+
+// RevokePortalToken revokes a portal access link; requests using it are rejected from now on
+func RevokePortalToken(db *gorm.DB) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var token models.PortalToken
+
+		if err := db.Where("id = ? AND client_id = ?", c.Param("token_id"), c.Param("id")).First(&token).Error; err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Portal token not found"})
+			return
+		}
+
+		if token.RevokedAt == nil {
+			now := time.Now()
+			token.RevokedAt = &now
+			if err := db.Model(&token).Update("revoked_at", now).Error; err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to revoke portal token"})
+				return
+			}
+		}
+
+		c.JSON(http.StatusOK, token)
+	}
+}
@@ -0,0 +1,150 @@
+package api
+
+import (
+	"net/http"
+	"strings"
+
+	"gaetanjaminon/GoTuto/internal/billing/models"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// GetSegments retrieves all saved client segments
+func GetSegments(db *gorm.DB) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var segments []models.ClientSegment
+
+		if err := db.Order("name ASC").Find(&segments).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve segments"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"segments": segments})
+	}
+}
+
+// GetSegment retrieves a saved segment with the number of clients it currently matches
+func GetSegment(db *gorm.DB) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id := c.Param("id")
+		var segment models.ClientSegment
+
+		if err := db.First(&segment, id).Error; err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Segment not found"})
+			return
+		}
+
+		var clientCount int64
+		applyClientFilter(db.Model(&models.Client{}), segment.Filter).Count(&clientCount)
+
+		c.JSON(http.StatusOK, gin.H{
+			"segment":      segment,
+			"client_count": clientCount,
+		})
+	}
+}
+
+// CreateSegment saves a named client filter. Segments are applied with ?segment_id= on
+// client and invoice listings.
+func CreateSegment(db *gorm.DB) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req models.CreateSegmentRequest
+
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		segment := models.ClientSegment{
+			Name:        strings.TrimSpace(req.Name),
+			Description: req.Description,
+			Filter:      req.Filter,
+		}
+		if err := segment.Filter.Normalize(); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		// Check if name already exists
+		var existing models.ClientSegment
+		if err := db.Where("name = ?", segment.Name).First(&existing).Error; err == nil {
+			c.JSON(http.StatusConflict, gin.H{"error": "Segment with this name already exists"})
+			return
+		}
+
+		if err := db.Create(&segment).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create segment"})
+			return
+		}
+
+		c.JSON(http.StatusCreated, segment)
+	}
+}
+
+// UpdateSegment updates a saved segment
+func UpdateSegment(db *gorm.DB) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id := c.Param("id")
+		var segment models.ClientSegment
+
+		if err := db.First(&segment, id).Error; err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Segment not found"})
+			return
+		}
+
+		var req models.UpdateSegmentRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		// Update only provided fields
+		if req.Name != "" {
+			segment.Name = strings.TrimSpace(req.Name)
+		}
+		if req.Description != "" {
+			segment.Description = req.Description
+		}
+		if req.Filter != nil {
+			segment.Filter = *req.Filter
+			if err := segment.Filter.Normalize(); err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+				return
+			}
+		}
+
+		var existing models.ClientSegment
+		if err := db.Where("name = ? AND id <> ?", segment.Name, segment.ID).First(&existing).Error; err == nil {
+			c.JSON(http.StatusConflict, gin.H{"error": "Segment with this name already exists"})
+			return
+		}
+
+		if err := db.Save(&segment).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update segment"})
+			return
+		}
+
+		c.JSON(http.StatusOK, segment)
+	}
+}
+
+// DeleteSegment deletes a saved segment; clients are not affected
+func DeleteSegment(db *gorm.DB) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id := c.Param("id")
+		var segment models.ClientSegment
+
+		if err := db.First(&segment, id).Error; err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Segment not found"})
+			return
+		}
+
+		if err := db.Delete(&segment).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete segment"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"message": "Segment deleted successfully"})
+	}
+}
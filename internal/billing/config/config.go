@@ -2,6 +2,7 @@ package config
 
 import (
 	"fmt"
+	"time"
 	"gaetanjaminon/GoTuto/internal/shared/infrastructure"
 )
 
@@ -17,6 +18,7 @@ type BillingConfig struct {
 	Pagination PaginationConfig `mapstructure:"pagination"`
 	Invoice    InvoiceConfig    `mapstructure:"invoice"`
 	Client     ClientConfig     `mapstructure:"client"`
+	Portal     PortalConfig     `mapstructure:"portal"`
 }
 
 // PaginationConfig holds pagination settings for billing domain
@@ -27,9 +29,21 @@ type PaginationConfig struct {
 
 // InvoiceConfig holds invoice-specific settings
 type InvoiceConfig struct {
-	NumberPrefix      string `mapstructure:"number_prefix"`
-	DefaultCurrency   string `mapstructure:"default_currency"`
-	PaymentTermsDays  int    `mapstructure:"payment_terms_days"`
+	NumberPrefix     string       `mapstructure:"number_prefix"`
+	DefaultCurrency  string       `mapstructure:"default_currency"`
+	PaymentTermsDays int          `mapstructure:"payment_terms_days"`
+	Issuer           IssuerConfig `mapstructure:"issuer"`
+}
+
+// IssuerConfig identifies the company issuing invoices, printed on PDF and UBL documents
+type IssuerConfig struct {
+	Name       string `mapstructure:"name"`
+	Email      string `mapstructure:"email"`
+	TaxID      string `mapstructure:"tax_id"`
+	Street     string `mapstructure:"street"`
+	City       string `mapstructure:"city"`
+	PostalCode string `mapstructure:"postal_code"`
+	Country    string `mapstructure:"country"` // ISO 3166-1 alpha-2
 }
 
 // ClientConfig holds client-specific settings
@@ -38,6 +52,19 @@ type ClientConfig struct {
 	MaxNameLength           int  `mapstructure:"max_name_length"`
 }
 
+// PortalConfig holds settings of the client self-service portal
+type PortalConfig struct {
+	SigningSecret string        `mapstructure:"signing_secret"` // HMAC key for access tokens; the portal is disabled when empty
+	TokenTTL      time.Duration `mapstructure:"token_ttl"`
+	MaxTokenTTL   time.Duration `mapstructure:"max_token_ttl"`
+	BaseURL       string        `mapstructure:"base_url"` // Public URL prefixed to generated access links
+}
+
+// Enabled reports whether portal access tokens can be issued and verified
+func (c PortalConfig) Enabled() bool {
+	return c.SigningSecret != ""
+}
+
 // Validate checks if the configuration is valid
 func (c *BillingConfig) Validate() error {
 	// Server validation
@@ -80,6 +107,16 @@ func (c *BillingConfig) Validate() error {
 		return fmt.Errorf("client max name length must be positive")
 	}
 
+	// Portal validation
+	if c.Portal.Enabled() {
+		if len(c.Portal.SigningSecret) < 32 {
+			return fmt.Errorf("portal signing secret must be at least 32 characters")
+		}
+		if c.Portal.TokenTTL <= 0 || c.Portal.TokenTTL > c.Portal.MaxTokenTTL {
+			return fmt.Errorf("portal token ttl must be positive and at most max_token_ttl (%s)", c.Portal.MaxTokenTTL)
+		}
+	}
+
 	return nil
 }
 
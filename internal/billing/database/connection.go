@@ -65,8 +65,18 @@ func Connect(cfg *config.BillingConfig) (*gorm.DB, error) {
 
 func AutoMigrate(db *gorm.DB) error {
 	err := db.AutoMigrate(
+		&models.PaymentTerm{},
 		&models.Client{},
+		&models.ClientAddress{},
+		&models.ClientContact{},
 		&models.Invoice{},
+		&models.InvoiceLine{},
+		&models.DiscountCode{},
+		&models.ClientMerge{},
+		&models.ClientTag{},
+		&models.CustomFieldDefinition{},
+		&models.ClientSegment{},
+		&models.PortalToken{},
 	)
 
 	if err != nil {
@@ -0,0 +1,14 @@
+-- Ensure we're in the billing schema
+SET search_path TO billing;
+
+-- Drop tables
+DROP TABLE IF EXISTS discount_codes;
+DROP TABLE IF EXISTS invoice_lines;
+
+-- Drop invoice discount columns
+ALTER TABLE invoices DROP CONSTRAINT IF EXISTS check_invoice_discount_type;
+ALTER TABLE invoices DROP COLUMN IF EXISTS total;
+ALTER TABLE invoices DROP COLUMN IF EXISTS discount_amount;
+ALTER TABLE invoices DROP COLUMN IF EXISTS discount_code;
+ALTER TABLE invoices DROP COLUMN IF EXISTS discount_value;
+ALTER TABLE invoices DROP COLUMN IF EXISTS discount_type;
\ No newline at end of file
@@ -0,0 +1,59 @@
+-- Ensure we're in the billing schema
+SET search_path TO billing;
+
+-- Add discount and total columns to invoices
+ALTER TABLE invoices ADD COLUMN IF NOT EXISTS discount_type VARCHAR(20);
+ALTER TABLE invoices ADD COLUMN IF NOT EXISTS discount_value DECIMAL(10,2) DEFAULT 0;
+ALTER TABLE invoices ADD COLUMN IF NOT EXISTS discount_code VARCHAR(50);
+ALTER TABLE invoices ADD COLUMN IF NOT EXISTS discount_amount DECIMAL(10,2) NOT NULL DEFAULT 0;
+ALTER TABLE invoices ADD COLUMN IF NOT EXISTS total DECIMAL(10,2);
+
+-- Existing invoices had discounts folded into amount
+UPDATE invoices SET total = amount WHERE total IS NULL;
+ALTER TABLE invoices ALTER COLUMN total SET NOT NULL;
+
+-- Create invoice_lines table
+CREATE TABLE IF NOT EXISTS invoice_lines (
+    id SERIAL PRIMARY KEY,
+    invoice_id INTEGER NOT NULL REFERENCES invoices(id) ON DELETE CASCADE,
+    description VARCHAR(255) NOT NULL,
+    quantity DECIMAL(10,3) NOT NULL DEFAULT 1,
+    unit_price DECIMAL(10,2) NOT NULL,
+    discount_type VARCHAR(20),
+    discount_value DECIMAL(10,2) DEFAULT 0,
+    discount_amount DECIMAL(10,2) NOT NULL DEFAULT 0,
+    total DECIMAL(10,2) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_invoice_lines_invoice_id ON invoice_lines(invoice_id);
+
+-- Create discount_codes table
+CREATE TABLE IF NOT EXISTS discount_codes (
+    id SERIAL PRIMARY KEY,
+    code VARCHAR(50) UNIQUE NOT NULL,
+    description VARCHAR(255),
+    discount_type VARCHAR(20) NOT NULL,
+    discount_value DECIMAL(10,2) NOT NULL,
+    valid_from TIMESTAMP WITH TIME ZONE,
+    valid_until TIMESTAMP WITH TIME ZONE,
+    max_uses INTEGER NOT NULL DEFAULT 0,
+    used_count INTEGER NOT NULL DEFAULT 0,
+    is_active BOOLEAN DEFAULT true,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX IF NOT EXISTS idx_discount_codes_deleted_at ON discount_codes(deleted_at);
+
+-- Add constraints for discounts
+ALTER TABLE invoices ADD CONSTRAINT check_invoice_discount_type
+    CHECK (discount_type IS NULL OR discount_type IN ('', 'percentage', 'fixed'));
+ALTER TABLE invoice_lines ADD CONSTRAINT check_invoice_line_discount_type
+    CHECK (discount_type IS NULL OR discount_type IN ('', 'percentage', 'fixed'));
+ALTER TABLE discount_codes ADD CONSTRAINT check_discount_code_type
+    CHECK (discount_type IN ('percentage', 'fixed'));
+ALTER TABLE discount_codes ADD CONSTRAINT check_discount_code_usage
+    CHECK (max_uses >= 0 AND used_count >= 0);
\ No newline at end of file
@@ -0,0 +1,15 @@
+-- Ensure we're in the billing schema
+SET search_path TO billing;
+
+-- Drop invoice payment term columns
+ALTER TABLE invoices DROP COLUMN IF EXISTS early_payment_discount;
+ALTER TABLE invoices DROP COLUMN IF EXISTS early_payment_date;
+ALTER TABLE invoices DROP COLUMN IF EXISTS payment_term_code;
+ALTER TABLE invoices DROP COLUMN IF EXISTS payment_term_id;
+
+-- Drop client payment term column
+DROP INDEX IF EXISTS idx_clients_payment_term_id;
+ALTER TABLE clients DROP COLUMN IF EXISTS payment_term_id;
+
+-- Drop payment_terms table
+DROP TABLE IF EXISTS payment_terms;
\ No newline at end of file
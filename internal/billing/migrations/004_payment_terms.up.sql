@@ -0,0 +1,43 @@
+-- Ensure we're in the billing schema
+SET search_path TO billing;
+
+-- Create payment_terms table
+CREATE TABLE IF NOT EXISTS payment_terms (
+    id SERIAL PRIMARY KEY,
+    code VARCHAR(20) UNIQUE NOT NULL,
+    name VARCHAR(100) NOT NULL,
+    type VARCHAR(20) NOT NULL DEFAULT 'net',
+    days INTEGER NOT NULL,
+    early_payment_days INTEGER NOT NULL DEFAULT 0,
+    early_payment_discount_percent DECIMAL(5,2) NOT NULL DEFAULT 0,
+    is_active BOOLEAN DEFAULT true,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX IF NOT EXISTS idx_payment_terms_deleted_at ON payment_terms(deleted_at);
+
+ALTER TABLE payment_terms ADD CONSTRAINT check_payment_term_type
+    CHECK (type IN ('net', 'end_of_month'));
+ALTER TABLE payment_terms ADD CONSTRAINT check_payment_term_days
+    CHECK (days >= 0 AND early_payment_days >= 0);
+ALTER TABLE payment_terms ADD CONSTRAINT check_payment_term_discount
+    CHECK (early_payment_discount_percent >= 0 AND early_payment_discount_percent <= 100);
+
+-- Seed the common payment terms
+INSERT INTO payment_terms (code, name, type, days, early_payment_days, early_payment_discount_percent) VALUES
+    ('NET30', 'Net 30', 'net', 30, 0, 0),
+    ('EOM15', 'End of month + 15', 'end_of_month', 15, 0, 0),
+    ('2/10NET30', '2/10 net 30', 'net', 30, 10, 2)
+ON CONFLICT (code) DO NOTHING;
+
+-- Assign payment terms to clients
+ALTER TABLE clients ADD COLUMN IF NOT EXISTS payment_term_id INTEGER REFERENCES payment_terms(id) ON DELETE SET NULL;
+CREATE INDEX IF NOT EXISTS idx_clients_payment_term_id ON clients(payment_term_id);
+
+-- Record the applied payment terms on invoices
+ALTER TABLE invoices ADD COLUMN IF NOT EXISTS payment_term_id INTEGER REFERENCES payment_terms(id) ON DELETE SET NULL;
+ALTER TABLE invoices ADD COLUMN IF NOT EXISTS payment_term_code VARCHAR(20);
+ALTER TABLE invoices ADD COLUMN IF NOT EXISTS early_payment_date DATE;
+ALTER TABLE invoices ADD COLUMN IF NOT EXISTS early_payment_discount DECIMAL(10,2) NOT NULL DEFAULT 0;
\ No newline at end of file
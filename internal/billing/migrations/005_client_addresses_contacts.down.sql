@@ -0,0 +1,6 @@
+-- Ensure we're in the billing schema
+SET search_path TO billing;
+
+-- The legacy clients.address column is kept, so dropping the tables loses no original data
+DROP TABLE IF EXISTS client_contacts;
+DROP TABLE IF EXISTS client_addresses;
\ No newline at end of file
@@ -0,0 +1,51 @@
+-- Ensure we're in the billing schema
+SET search_path TO billing;
+
+-- Create client_addresses table (one billing and one shipping address per client)
+CREATE TABLE IF NOT EXISTS client_addresses (
+    id SERIAL PRIMARY KEY,
+    client_id INTEGER NOT NULL REFERENCES clients(id) ON DELETE CASCADE,
+    type VARCHAR(20) NOT NULL,
+    street VARCHAR(255) NOT NULL,
+    street2 VARCHAR(255),
+    city VARCHAR(100) NOT NULL,
+    postal_code VARCHAR(12),
+    region VARCHAR(100),
+    country VARCHAR(2) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE(client_id, type)
+);
+
+ALTER TABLE client_addresses ADD CONSTRAINT check_client_address_type
+    CHECK (type IN ('billing', 'shipping'));
+
+-- Create client_contacts table
+CREATE TABLE IF NOT EXISTS client_contacts (
+    id SERIAL PRIMARY KEY,
+    client_id INTEGER NOT NULL REFERENCES clients(id) ON DELETE CASCADE,
+    name VARCHAR(255) NOT NULL,
+    email VARCHAR(255) NOT NULL,
+    phone VARCHAR(50),
+    role VARCHAR(20) NOT NULL DEFAULT 'general',
+    is_primary BOOLEAN NOT NULL DEFAULT false,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_client_contacts_client_id ON client_contacts(client_id);
+
+ALTER TABLE client_contacts ADD CONSTRAINT check_client_contact_role
+    CHECK (role IN ('billing', 'technical', 'general'));
+
+-- Free-text addresses stay on clients.address, used on invoices until a structured
+-- address is set through PUT /api/v1/clients/{id}/addresses/billing. City and country
+-- cannot be derived from them reliably.
+
+-- The client email becomes its primary billing contact
+INSERT INTO client_contacts (client_id, name, email, phone, role, is_primary)
+SELECT id, name, email, phone, 'billing', true
+FROM clients
+WHERE NOT EXISTS (
+    SELECT 1 FROM client_contacts cc WHERE cc.client_id = clients.id AND cc.role = 'billing'
+);
\ No newline at end of file
@@ -0,0 +1,8 @@
+-- Ensure we're in the billing schema
+SET search_path TO billing;
+
+ALTER TABLE clients DROP CONSTRAINT IF EXISTS check_client_tax_id_scheme;
+DROP INDEX IF EXISTS idx_clients_tax_id;
+ALTER TABLE clients DROP COLUMN IF EXISTS tax_id_verified_at;
+ALTER TABLE clients DROP COLUMN IF EXISTS tax_id_scheme;
+ALTER TABLE clients DROP COLUMN IF EXISTS tax_id;
\ No newline at end of file
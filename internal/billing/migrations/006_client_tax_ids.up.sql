@@ -0,0 +1,12 @@
+-- Ensure we're in the billing schema
+SET search_path TO billing;
+
+-- Add tax identifier columns to clients
+ALTER TABLE clients ADD COLUMN IF NOT EXISTS tax_id VARCHAR(30);
+ALTER TABLE clients ADD COLUMN IF NOT EXISTS tax_id_scheme VARCHAR(20);
+ALTER TABLE clients ADD COLUMN IF NOT EXISTS tax_id_verified_at TIMESTAMP WITH TIME ZONE;
+
+CREATE INDEX IF NOT EXISTS idx_clients_tax_id ON clients(tax_id);
+
+ALTER TABLE clients ADD CONSTRAINT check_client_tax_id_scheme
+    CHECK (tax_id_scheme IS NULL OR tax_id_scheme IN ('', 'eu_vat', 'gb_vat', 'ch_uid', 'no_vat', 'us_ein', 'au_abn'));
\ No newline at end of file
@@ -0,0 +1,7 @@
+-- Ensure we're in the billing schema
+SET search_path TO billing;
+
+DROP TABLE IF EXISTS client_merges;
+
+DROP INDEX IF EXISTS idx_clients_merged_into_id;
+ALTER TABLE clients DROP COLUMN IF EXISTS merged_into_id;
\ No newline at end of file
@@ -0,0 +1,23 @@
+-- Ensure we're in the billing schema
+SET search_path TO billing;
+
+-- Track which client a merged duplicate was folded into
+ALTER TABLE clients ADD COLUMN IF NOT EXISTS merged_into_id INTEGER REFERENCES clients(id);
+
+CREATE INDEX IF NOT EXISTS idx_clients_merged_into_id ON clients(merged_into_id);
+
+-- Audit trail of client merges
+CREATE TABLE IF NOT EXISTS client_merges (
+    id SERIAL PRIMARY KEY,
+    survivor_id INTEGER NOT NULL REFERENCES clients(id),
+    merged_client_id INTEGER NOT NULL REFERENCES clients(id),
+    merged_snapshot TEXT,
+    invoices_moved BIGINT NOT NULL DEFAULT 0,
+    contacts_moved BIGINT NOT NULL DEFAULT 0,
+    addresses_moved BIGINT NOT NULL DEFAULT 0,
+    reason VARCHAR(500),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_client_merges_survivor_id ON client_merges(survivor_id);
+CREATE INDEX IF NOT EXISTS idx_client_merges_merged_client_id ON client_merges(merged_client_id);
\ No newline at end of file
@@ -0,0 +1,5 @@
+-- Ensure we're in the billing schema
+SET search_path TO billing;
+
+COMMENT ON COLUMN clients.deleted_at IS NULL;
+ALTER TABLE clients DROP COLUMN IF EXISTS erased_at;
\ No newline at end of file
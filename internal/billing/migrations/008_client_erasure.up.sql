@@ -0,0 +1,8 @@
+-- Ensure we're in the billing schema
+SET search_path TO billing;
+
+-- Set when a client's personal data is pseudonymized (right to erasure)
+ALTER TABLE clients ADD COLUMN IF NOT EXISTS erased_at TIMESTAMP WITH TIME ZONE;
+
+COMMENT ON COLUMN clients.deleted_at IS 'Soft delete: hides the client but keeps its personal data for retained invoices';
+COMMENT ON COLUMN clients.erased_at IS 'Personal data pseudonymized; invoices are retained';
\ No newline at end of file
@@ -0,0 +1,10 @@
+-- Ensure we're in the billing schema
+SET search_path TO billing;
+
+DROP TABLE IF EXISTS client_segments;
+
+DROP INDEX IF EXISTS idx_clients_custom_fields;
+ALTER TABLE clients DROP COLUMN IF EXISTS custom_fields;
+DROP TABLE IF EXISTS custom_field_definitions;
+
+DROP TABLE IF EXISTS client_tags;
\ No newline at end of file
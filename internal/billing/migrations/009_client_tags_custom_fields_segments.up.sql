@@ -0,0 +1,39 @@
+-- Ensure we're in the billing schema
+SET search_path TO billing;
+
+-- Tags grouping clients (industry, tier, ...)
+CREATE TABLE IF NOT EXISTS client_tags (
+    id SERIAL PRIMARY KEY,
+    client_id INTEGER NOT NULL REFERENCES clients(id) ON DELETE CASCADE,
+    name VARCHAR(50) NOT NULL
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_client_tags_client_name ON client_tags(client_id, name);
+CREATE INDEX IF NOT EXISTS idx_client_tags_name ON client_tags(name);
+
+-- Typed custom field definitions and their values on clients
+CREATE TABLE IF NOT EXISTS custom_field_definitions (
+    id SERIAL PRIMARY KEY,
+    key VARCHAR(50) NOT NULL UNIQUE,
+    label VARCHAR(100) NOT NULL,
+    type VARCHAR(20) NOT NULL,
+    options JSONB,
+    required BOOLEAN NOT NULL DEFAULT false,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT check_custom_field_type CHECK (type IN ('text', 'number', 'boolean', 'date', 'select'))
+);
+
+ALTER TABLE clients ADD COLUMN IF NOT EXISTS custom_fields JSONB NOT NULL DEFAULT '{}';
+
+CREATE INDEX IF NOT EXISTS idx_clients_custom_fields ON clients USING GIN (custom_fields);
+
+-- Saved client segments
+CREATE TABLE IF NOT EXISTS client_segments (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(100) NOT NULL UNIQUE,
+    description VARCHAR(255),
+    filter JSONB NOT NULL DEFAULT '{}',
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
\ No newline at end of file
@@ -0,0 +1,4 @@
+-- Ensure we're in the billing schema
+SET search_path TO billing;
+
+DROP TABLE IF EXISTS portal_tokens;
\ No newline at end of file
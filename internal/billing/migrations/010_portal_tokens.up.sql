@@ -0,0 +1,16 @@
+-- Ensure we're in the billing schema
+SET search_path TO billing;
+
+-- Signed access links to the client self-service portal. Only the record is stored,
+-- the signed token is handed out once; revoking the record invalidates the token.
+CREATE TABLE IF NOT EXISTS portal_tokens (
+    id SERIAL PRIMARY KEY,
+    client_id INTEGER NOT NULL REFERENCES clients(id) ON DELETE CASCADE,
+    label VARCHAR(100),
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    revoked_at TIMESTAMP WITH TIME ZONE,
+    last_used_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_portal_tokens_client_id ON portal_tokens(client_id);
\ No newline at end of file
@@ -0,0 +1,7 @@
+-- Ensure we're in the billing schema
+SET search_path TO billing;
+
+ALTER TABLE invoices DROP COLUMN IF EXISTS early_payment_discount_percent;
+ALTER TABLE invoices DROP COLUMN IF EXISTS early_payment_days;
+ALTER TABLE invoices DROP COLUMN IF EXISTS payment_term_days;
+ALTER TABLE invoices DROP COLUMN IF EXISTS payment_term_type;
\ No newline at end of file
@@ -0,0 +1,23 @@
+-- Ensure we're in the billing schema
+SET search_path TO billing;
+
+-- Record the rules of the applied payment term on invoices, so editing a term does not
+-- change the invoices it was applied to
+ALTER TABLE invoices ADD COLUMN IF NOT EXISTS payment_term_type VARCHAR(20);
+ALTER TABLE invoices ADD COLUMN IF NOT EXISTS payment_term_days INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE invoices ADD COLUMN IF NOT EXISTS early_payment_days INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE invoices ADD COLUMN IF NOT EXISTS early_payment_discount_percent DECIMAL(5,2) NOT NULL DEFAULT 0;
+
+-- Backfill from the dates already computed on the invoices
+UPDATE invoices i SET
+    payment_term_type = t.type,
+    payment_term_days = t.days,
+    early_payment_days = COALESCE(i.early_payment_date - i.issue_date, 0),
+    early_payment_discount_percent = CASE WHEN i.early_payment_date IS NULL THEN 0 ELSE t.early_payment_discount_percent END
+FROM payment_terms t
+WHERE i.payment_term_id = t.id AND i.payment_term_type IS NULL;
+
+UPDATE invoices SET
+    payment_term_type = 'net',
+    payment_term_days = due_date - issue_date
+WHERE payment_term_type IS NULL AND payment_term_code <> '';
\ No newline at end of file
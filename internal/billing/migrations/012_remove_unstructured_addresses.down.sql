@@ -0,0 +1,4 @@
+-- Ensure we're in the billing schema
+SET search_path TO billing;
+
+-- The removed addresses were incomplete and are not restored
\ No newline at end of file
@@ -0,0 +1,11 @@
+-- Ensure we're in the billing schema
+SET search_path TO billing;
+
+-- Remove the billing addresses created from free-text addresses without a city or
+-- country. The text is kept on the client, where invoices fall back to it.
+UPDATE clients c SET address = ca.street
+FROM client_addresses ca
+WHERE ca.client_id = c.id AND ca.city = '' AND ca.country = ''
+    AND (c.address IS NULL OR TRIM(c.address) = '');
+
+DELETE FROM client_addresses WHERE city = '' AND country = '';
\ No newline at end of file
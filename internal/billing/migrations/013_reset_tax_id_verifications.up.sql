@@ -0,0 +1,6 @@
+-- Ensure we're in the billing schema
+SET search_path TO billing;
+
+-- Tax IDs were marked verified after offline format checks only; no registry has
+-- confirmed them, so they are reset to unverified
+UPDATE clients SET tax_id_verified_at = NULL WHERE tax_id_verified_at IS NOT NULL;
\ No newline at end of file
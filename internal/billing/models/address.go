@@ -0,0 +1,185 @@
+package models
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+)
+
+type AddressType string
+
+const (
+	AddressTypeBilling  AddressType = "billing"
+	AddressTypeShipping AddressType = "shipping"
+)
+
+// ClientAddress is a structured postal address of a client (one per type)
+type ClientAddress struct {
+	ID         uint        `json:"id" gorm:"primaryKey"`
+	ClientID   uint        `json:"client_id" gorm:"not null;uniqueIndex:idx_client_addresses_client_type"`
+	Type       AddressType `json:"type" gorm:"not null;uniqueIndex:idx_client_addresses_client_type"`
+	Street     string      `json:"street" gorm:"not null"`
+	Street2    string      `json:"street2,omitempty"`
+	City       string      `json:"city" gorm:"not null"`
+	PostalCode string      `json:"postal_code"`
+	Region     string      `json:"region,omitempty"`
+	Country    string      `json:"country" gorm:"size:2;not null"` // ISO 3166-1 alpha-2
+	CreatedAt  time.Time   `json:"created_at"`
+	UpdatedAt  time.Time   `json:"updated_at"`
+}
+
+// Normalize trims fields and upper-cases the country and postal code
+func (a *ClientAddress) Normalize() {
+	a.Street = strings.TrimSpace(a.Street)
+	a.Street2 = strings.TrimSpace(a.Street2)
+	a.City = strings.TrimSpace(a.City)
+	a.Region = strings.TrimSpace(a.Region)
+	a.Country = strings.ToUpper(strings.TrimSpace(a.Country))
+	a.PostalCode = strings.ToUpper(strings.TrimSpace(a.PostalCode))
+}
+
+// Validate checks the address business rules, including the country-specific postal code format
+func (a ClientAddress) Validate() error {
+	if a.Type != AddressTypeBilling && a.Type != AddressTypeShipping {
+		return fmt.Errorf("address type must be 'billing' or 'shipping'")
+	}
+	if strings.TrimSpace(a.Street) == "" {
+		return fmt.Errorf("street is required")
+	}
+	if strings.TrimSpace(a.City) == "" {
+		return fmt.Errorf("city is required")
+	}
+	if !IsValidCountryCode(a.Country) {
+		return fmt.Errorf("invalid ISO 3166-1 country code: %q", a.Country)
+	}
+	return ValidatePostalCode(a.Country, a.PostalCode)
+}
+
+// String formats the address on a single line
+func (a ClientAddress) String() string {
+	parts := []string{a.Street}
+	if a.Street2 != "" {
+		parts = append(parts, a.Street2)
+	}
+	cityLine := strings.TrimSpace(a.PostalCode + " " + a.City)
+	if cityLine != "" {
+		parts = append(parts, cityLine)
+	}
+	if a.Region != "" {
+		parts = append(parts, a.Region)
+	}
+	if a.Country != "" {
+		parts = append(parts, a.Country)
+	}
+	return strings.Join(parts, ", ")
+}
+
+// postalCodeFormats holds postal code patterns for countries we invoice most often.
+// Countries not listed accept any non-empty code of reasonable length.
+var postalCodeFormats = map[string]*regexp.Regexp{
+	"AT": regexp.MustCompile(`^\d{4}$`),
+	"AU": regexp.MustCompile(`^\d{4}$`),
+	"BE": regexp.MustCompile(`^\d{4}$`),
+	"BR": regexp.MustCompile(`^\d{5}-?\d{3}$`),
+	"CA": regexp.MustCompile(`^[A-Z]\d[A-Z] ?\d[A-Z]\d$`),
+	"CH": regexp.MustCompile(`^\d{4}$`),
+	"CZ": regexp.MustCompile(`^\d{3} ?\d{2}$`),
+	"DE": regexp.MustCompile(`^\d{5}$`),
+	"DK": regexp.MustCompile(`^\d{4}$`),
+	"ES": regexp.MustCompile(`^\d{5}$`),
+	"FI": regexp.MustCompile(`^\d{5}$`),
+	"FR": regexp.MustCompile(`^\d{5}$`),
+	"GB": regexp.MustCompile(`^[A-Z]{1,2}\d[A-Z\d]? ?\d[A-Z]{2}$`),
+	"IE": regexp.MustCompile(`^[A-Z]\d[\dW] ?[A-Z\d]{4}$`),
+	"IN": regexp.MustCompile(`^\d{6}$`),
+	"IT": regexp.MustCompile(`^\d{5}$`),
+	"JP": regexp.MustCompile(`^\d{3}-?\d{4}$`),
+	"LU": regexp.MustCompile(`^(L-)?\d{4}$`),
+	"NL": regexp.MustCompile(`^\d{4} ?[A-Z]{2}$`),
+	"NO": regexp.MustCompile(`^\d{4}$`),
+	"PL": regexp.MustCompile(`^\d{2}-\d{3}$`),
+	"PT": regexp.MustCompile(`^\d{4}-\d{3}$`),
+	"SE": regexp.MustCompile(`^\d{3} ?\d{2}$`),
+	"US": regexp.MustCompile(`^\d{5}(-\d{4})?$`),
+}
+
+// countriesWithoutPostalCodes lists countries where addresses commonly have no postal code
+var countriesWithoutPostalCodes = map[string]bool{
+	"AE": true, "AG": true, "AO": true, "BS": true, "BZ": true, "FJ": true,
+	"GH": true, "HK": true, "JM": true, "MO": true, "QA": true, "ZW": true,
+}
+
+// ValidatePostalCode checks a postal code against the format of the given country
+func ValidatePostalCode(country, postalCode string) error {
+	country = strings.ToUpper(strings.TrimSpace(country))
+	postalCode = strings.ToUpper(strings.TrimSpace(postalCode))
+
+	if postalCode == "" {
+		if countriesWithoutPostalCodes[country] {
+			return nil
+		}
+		return fmt.Errorf("postal code is required for country %s", country)
+	}
+
+	if format, ok := postalCodeFormats[country]; ok {
+		if !format.MatchString(postalCode) {
+			return fmt.Errorf("invalid postal code %q for country %s", postalCode, country)
+		}
+		return nil
+	}
+
+	if len(postalCode) > 12 {
+		return fmt.Errorf("postal code cannot exceed 12 characters")
+	}
+	return nil
+}
+
+// isoCountryCodes lists the officially assigned ISO 3166-1 alpha-2 codes
+var isoCountryCodes = buildCountrySet(`
+AD AE AF AG AI AL AM AO AQ AR AS AT AU AW AX AZ BA BB BD BE BF BG BH BI BJ BL BM BN BO BQ BR BS
+BT BV BW BY BZ CA CC CD CF CG CH CI CK CL CM CN CO CR CU CV CW CX CY CZ DE DJ DK DM DO DZ EC EE
+EG EH ER ES ET FI FJ FK FM FO FR GA GB GD GE GF GG GH GI GL GM GN GP GQ GR GS GT GU GW GY HK HM
+HN HR HT HU ID IE IL IM IN IO IQ IR IS IT JE JM JO JP KE KG KH KI KM KN KP KR KW KY KZ LA LB LC
+LI LK LR LS LT LU LV LY MA MC MD ME MF MG MH MK ML MM MN MO MP MQ MR MS MT MU MV MW MX MY MZ NA
+NC NE NF NG NI NL NO NP NR NU NZ OM PA PE PF PG PH PK PL PM PN PR PS PT PW PY QA RE RO RS RU RW
+SA SB SC SD SE SG SH SI SJ SK SL SM SN SO SR SS ST SV SX SY SZ TC TD TF TG TH TJ TK TL TM TN TO
+TR TT TV TW TZ UA UG UM US UY UZ VA VC VE VG VI VN VU WF WS YE YT ZA ZM ZW
+`)
+
+func buildCountrySet(codes string) map[string]bool {
+	set := make(map[string]bool)
+	for _, code := range strings.Fields(codes) {
+		set[code] = true
+	}
+	return set
+}
+
+// IsValidCountryCode checks that the code is an ISO 3166-1 alpha-2 country code
+func IsValidCountryCode(code string) bool {
+	return isoCountryCodes[strings.ToUpper(strings.TrimSpace(code))]
+}
+
+type AddressRequest struct {
+	Street     string `json:"street" binding:"required,max=255"`
+	Street2    string `json:"street2" binding:"max=255"`
+	City       string `json:"city" binding:"required,max=100"`
+	PostalCode string `json:"postal_code" binding:"max=12"`
+	Region     string `json:"region" binding:"max=100"`
+	Country    string `json:"country" binding:"required,len=2"`
+}
+
+// ToAddress builds a normalized client address of the given type
+func (r AddressRequest) ToAddress(addressType AddressType) ClientAddress {
+	address := ClientAddress{
+		Type:       addressType,
+		Street:     r.Street,
+		Street2:    r.Street2,
+		City:       r.City,
+		PostalCode: r.PostalCode,
+		Region:     r.Region,
+		Country:    r.Country,
+	}
+	address.Normalize()
+	return address
+}
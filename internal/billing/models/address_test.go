@@ -0,0 +1,99 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidatePostalCode(t *testing.T) {
+	valid := []struct{ country, postalCode string }{
+		{"US", "94105"},
+		{"US", "94105-1234"},
+		{"CA", "K1A 0B1"},
+		{"GB", "SW1A 1AA"},
+		{"GB", "M1 1AE"},
+		{"FR", "75008"},
+		{"BE", "1000"},
+		{"NL", "1012 AB"},
+		{"LU", "L-1234"},
+		{"PT", "1000-001"},
+		{"PL", "00-950"},
+		{"IE", "D02 X285"},
+		{"be", " 1000 "},
+		{"HK", ""},
+		{"KR", "03187"}, // No specific format, any reasonable code
+	}
+	for _, tc := range valid {
+		t.Run("valid "+tc.country+" "+tc.postalCode, func(t *testing.T) {
+			assert.NoError(t, ValidatePostalCode(tc.country, tc.postalCode))
+		})
+	}
+
+	invalid := []struct{ country, postalCode string }{
+		{"US", "9410"},
+		{"US", "ABCDE"},
+		{"CA", "12345"},
+		{"FR", "7500"},
+		{"BE", "10000"},
+		{"NL", "1012"},
+		{"PT", "1000"},
+		{"DE", ""},
+		{"KR", "0123456789012"},
+	}
+	for _, tc := range invalid {
+		t.Run("invalid "+tc.country+" "+tc.postalCode, func(t *testing.T) {
+			assert.Error(t, ValidatePostalCode(tc.country, tc.postalCode))
+		})
+	}
+}
+
+func TestIsValidCountryCode(t *testing.T) {
+	assert.True(t, IsValidCountryCode("BE"))
+	assert.True(t, IsValidCountryCode("us"))
+	assert.False(t, IsValidCountryCode("UK")) // ISO code for the United Kingdom is GB
+	assert.False(t, IsValidCountryCode("EU"))
+	assert.False(t, IsValidCountryCode(""))
+}
+
+func TestClientAddress_Validate(t *testing.T) {
+	address := AddressRequest{
+		Street:     "Rue de la Loi 16",
+		City:       "Brussels",
+		PostalCode: "1000",
+		Country:    "be",
+	}.ToAddress(AddressTypeBilling)
+
+	assert.Equal(t, "BE", address.Country)
+	assert.NoError(t, address.Validate())
+	assert.Equal(t, "Rue de la Loi 16, 1000 Brussels, BE", address.String())
+
+	missingCity := address
+	missingCity.City = ""
+	assert.Error(t, missingCity.Validate())
+
+	badCountry := address
+	badCountry.Country = "XX"
+	assert.Error(t, badCountry.Validate())
+
+	badType := address
+	badType.Type = "office"
+	assert.Error(t, badType.Validate())
+}
+
+func TestClient_ContactsWithRole(t *testing.T) {
+	client := Client{
+		Contacts: []ClientContact{
+			{Name: "Ops", Role: ContactRoleTechnical},
+			{Name: "Accounts payable", Role: ContactRoleBilling},
+			{Name: "CFO", Role: ContactRoleBilling, IsPrimary: true},
+		},
+	}
+
+	billing := client.ContactsWithRole(ContactRoleBilling)
+	if assert.Len(t, billing, 2) {
+		assert.Equal(t, "CFO", billing[0].Name)
+	}
+	assert.Len(t, client.ContactsWithRole(ContactRoleTechnical), 1)
+	assert.Empty(t, client.ContactsWithRole(ContactRoleGeneral))
+}
@@ -5,30 +5,100 @@ import (
 	"gorm.io/gorm"
 )
 
+// Client is a billing customer.
+//
+// DeletedAt is a soft delete: the client disappears from listings and lookups, but the
+// row and its personal data are kept so retained invoices still resolve. Soft deletion
+// is therefore not erasure; personal data is removed with Erase, which sets ErasedAt.
 type Client struct {
 	ID        uint           `json:"id" gorm:"primaryKey"`
 	Name      string         `json:"name" gorm:"not null"`
 	Email     string         `json:"email" gorm:"uniqueIndex;not null"`
 	Phone     string         `json:"phone"`
-	Address   string         `json:"address"`
+	Address   string         `json:"address"` // Deprecated: free-text address kept for compatibility, use Addresses
 	CreatedAt time.Time      `json:"created_at"`
 	UpdatedAt time.Time      `json:"updated_at"`
 	DeletedAt gorm.DeletedAt `json:"-" gorm:"index"`
 	
-	// Relationship
-	Invoices []Invoice `json:"invoices,omitempty" gorm:"foreignKey:ClientID"`
+	// Tax identifier, required for reverse-charge invoices
+	TaxID           string      `json:"tax_id,omitempty"`
+	TaxIDScheme     TaxIDScheme `json:"tax_id_scheme,omitempty"`
+	TaxIDVerifiedAt *time.Time  `json:"tax_id_verified_at,omitempty"`
+	
+	// Grouping by account managers (industry, tier, ...) and typed custom fields
+	Tags         []ClientTag       `json:"tags,omitempty" gorm:"foreignKey:ClientID"`
+	CustomFields CustomFieldValues `json:"custom_fields,omitempty" gorm:"type:jsonb;not null;default:'{}'"`
+	
+	// Set when the client's personal data was erased (see Erase)
+	ErasedAt *time.Time `json:"erased_at,omitempty"`
+	
+	// Set when the client was merged into another one (see ClientMerge)
+	MergedIntoID *uint `json:"merged_into_id,omitempty"`
+	
+	// Default payment terms for new invoices
+	PaymentTermID *uint        `json:"payment_term_id,omitempty"`
+	PaymentTerm   *PaymentTerm `json:"payment_term,omitempty" gorm:"foreignKey:PaymentTermID"`
+	
+	// Relationships
+	Invoices  []Invoice       `json:"invoices,omitempty" gorm:"foreignKey:ClientID"`
+	Addresses []ClientAddress `json:"addresses,omitempty" gorm:"foreignKey:ClientID"`
+	Contacts  []ClientContact `json:"contacts,omitempty" gorm:"foreignKey:ClientID"`
 }
 
 type CreateClientRequest struct {
-	Name    string `json:"name" binding:"required,min=2,max=100"`
-	Email   string `json:"email" binding:"required,email"`
-	Phone   string `json:"phone" binding:"max=20"`
-	Address string `json:"address" binding:"max=255"`
+	Name            string            `json:"name" binding:"required,min=2,max=100"`
+	Email           string            `json:"email" binding:"required,email"`
+	Phone           string            `json:"phone" binding:"max=20"`
+	Address         string            `json:"address" binding:"max=255"`
+	PaymentTermID   *uint             `json:"payment_term_id"`
+	TaxID           string            `json:"tax_id" binding:"max=30"`
+	TaxIDScheme     TaxIDScheme       `json:"tax_id_scheme" binding:"omitempty,oneof=eu_vat gb_vat ch_uid no_vat us_ein au_abn"`
+	BillingAddress  *AddressRequest   `json:"billing_address"`
+	ShippingAddress *AddressRequest   `json:"shipping_address"`
+	Contacts        []ContactRequest  `json:"contacts" binding:"omitempty,dive"`
+	Tags            []string          `json:"tags" binding:"omitempty,max=20"`
+	CustomFields    CustomFieldValues `json:"custom_fields"`
 }
 
 type UpdateClientRequest struct {
-	Name    string `json:"name" binding:"omitempty,min=2,max=100"`
-	Email   string `json:"email" binding:"omitempty,email"`
-	Phone   string `json:"phone" binding:"omitempty,max=20"`
-	Address string `json:"address" binding:"omitempty,max=255"`
-}
\ No newline at end of file
+	Name          string            `json:"name" binding:"omitempty,min=2,max=100"`
+	Email         string            `json:"email" binding:"omitempty,email"`
+	Phone         string            `json:"phone" binding:"omitempty,max=20"`
+	Address       string            `json:"address" binding:"omitempty,max=255"`
+	PaymentTermID *uint             `json:"payment_term_id"`
+	TaxID         string            `json:"tax_id" binding:"omitempty,max=30"`
+	TaxIDScheme   TaxIDScheme       `json:"tax_id_scheme" binding:"omitempty,oneof=eu_vat gb_vat ch_uid no_vat us_ein au_abn"`
+	Tags          []string          `json:"tags" binding:"omitempty,max=20"` // Replaces all tags when present
+	CustomFields  CustomFieldValues `json:"custom_fields"`                   // Merged into existing values; null removes a field
+}
+
+// HasVerifiedTaxID reports whether the client has a tax ID that passed verification
+func (c Client) HasVerifiedTaxID() bool {
+	return c.TaxID != "" && c.TaxIDVerifiedAt != nil
+}
+
+// AddressOfType returns the client's address of the given type, or nil
+func (c Client) AddressOfType(addressType AddressType) *ClientAddress {
+	for i := range c.Addresses {
+		if c.Addresses[i].Type == addressType {
+			return &c.Addresses[i]
+		}
+	}
+	return nil
+}
+
+// ContactsWithRole returns the client's contacts with the given role, primary contacts first
+func (c Client) ContactsWithRole(role ContactRole) []ClientContact {
+	var primary, others []ClientContact
+	for _, contact := range c.Contacts {
+		if contact.Role != role {
+			continue
+		}
+		if contact.IsPrimary {
+			primary = append(primary, contact)
+		} else {
+			others = append(others, contact)
+		}
+	}
+	return append(primary, others...)
+}
@@ -0,0 +1,42 @@
+package models
+
+import (
+	"strings"
+	"time"
+)
+
+// ClientMerge is the audit record written when a duplicate client is merged into a surviving client
+type ClientMerge struct {
+	ID             uint      `json:"id" gorm:"primaryKey"`
+	SurvivorID     uint      `json:"survivor_id" gorm:"not null;index"`
+	MergedClientID uint      `json:"merged_client_id" gorm:"not null;index"`
+	MergedSnapshot string    `json:"merged_snapshot" gorm:"type:text"` // JSON copy of the merged client before the merge
+	InvoicesMoved  int64     `json:"invoices_moved"`
+	ContactsMoved  int64     `json:"contacts_moved"`
+	AddressesMoved int64     `json:"addresses_moved"`
+	Reason         string    `json:"reason,omitempty"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+type MergeClientsRequest struct {
+	DuplicateIDs []uint `json:"duplicate_ids" binding:"required,min=1,dive,gt=0"`
+	Reason       string `json:"reason" binding:"max=500"`
+}
+
+// SplitMergedContacts separates the contacts of a merged duplicate into those that move
+// to the survivor and those the survivor already has (same email, ignoring case, and role)
+func SplitMergedContacts(survivor, duplicate []ClientContact) (moved, redundant []ClientContact) {
+	existing := make(map[string]bool, len(survivor))
+	for _, contact := range survivor {
+		existing[strings.ToLower(contact.Email)+"|"+string(contact.Role)] = true
+	}
+
+	for _, contact := range duplicate {
+		if existing[strings.ToLower(contact.Email)+"|"+string(contact.Role)] {
+			redundant = append(redundant, contact)
+		} else {
+			moved = append(moved, contact)
+		}
+	}
+	return moved, redundant
+}
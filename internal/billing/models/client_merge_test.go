@@ -0,0 +1,40 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSplitMergedContacts(t *testing.T) {
+	survivor := []ClientContact{
+		{ID: 1, Email: "billing@acme.com", Role: ContactRoleBilling},
+	}
+
+	t.Run("different contacts are moved", func(t *testing.T) {
+		duplicate := []ClientContact{
+			{ID: 2, Email: "ops@acme.com", Role: ContactRoleTechnical},
+			{ID: 3, Email: "jane@acme.com", Role: ContactRoleGeneral},
+		}
+		moved, redundant := SplitMergedContacts(survivor, duplicate)
+		assert.Equal(t, duplicate, moved)
+		assert.Empty(t, redundant)
+	})
+
+	t.Run("contacts the survivor already has are dropped", func(t *testing.T) {
+		duplicate := []ClientContact{
+			{ID: 2, Email: "Billing@ACME.com", Role: ContactRoleBilling},
+			{ID: 3, Email: "billing@acme.com", Role: ContactRoleTechnical},
+		}
+		moved, redundant := SplitMergedContacts(survivor, duplicate)
+		assert.Equal(t, []ClientContact{duplicate[1]}, moved, "same email with another role is kept")
+		assert.Equal(t, []ClientContact{duplicate[0]}, redundant)
+	})
+
+	t.Run("survivor without contacts takes them all", func(t *testing.T) {
+		duplicate := []ClientContact{{ID: 2, Email: "billing@acme.com", Role: ContactRoleBilling}}
+		moved, redundant := SplitMergedContacts(nil, duplicate)
+		assert.Equal(t, duplicate, moved)
+		assert.Empty(t, redundant)
+	})
+}
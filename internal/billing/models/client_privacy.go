@@ -0,0 +1,131 @@
+package models
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+// ClientExportFormatVersion identifies the layout of ClientDataExport
+const ClientExportFormatVersion = 1
+
+// ClientDataExport is the machine-readable archive of the personal data held about a
+// client, answering a GDPR subject access request. Soft-deleted records are included
+// since their data is still stored.
+type ClientDataExport struct {
+	FormatVersion int             `json:"format_version"`
+	ExportedAt    time.Time       `json:"exported_at"`
+	Client        Client          `json:"client"`
+	Addresses     []ClientAddress `json:"addresses"`
+	Contacts      []ClientContact `json:"contacts"`
+	Invoices      []Invoice       `json:"invoices"`
+	Merges        []ClientMerge   `json:"merges"`
+}
+
+// IsErased reports whether the client's personal data has been erased
+func (c Client) IsErased() bool {
+	return c.ErasedAt != nil
+}
+
+// Erase pseudonymizes the client's personal fields in place. The ID is kept so that
+// legally retained invoices still reference the client; the email is replaced with a
+// unique placeholder to keep the unique index satisfied.
+func (c *Client) Erase(at time.Time) {
+	c.Name = fmt.Sprintf("Erased client %d", c.ID)
+	c.Email = ErasedClientEmail(c.ID)
+	c.Phone = ""
+	c.Address = ""
+	c.TaxID = ""
+	c.TaxIDScheme = ""
+	c.TaxIDVerifiedAt = nil
+	c.CustomFields = CustomFieldValues{}
+	c.ErasedAt = &at
+}
+
+// ErasedClientEmail is the placeholder email of an erased client
+func ErasedClientEmail(clientID uint) string {
+	return fmt.Sprintf("erased-%d@erased.invalid", clientID)
+}
+
+// ErasedPlaceholder replaces personal data redacted from retained free text
+const ErasedPlaceholder = "[erased]"
+
+// PersonalIdentifiers returns the personal values of the client and its contacts that
+// may appear in free text such as invoice descriptions
+func (c Client) PersonalIdentifiers(contacts []ClientContact) []string {
+	values := []string{c.Name, c.Email, c.Phone, c.Address, c.TaxID}
+	for _, contact := range contacts {
+		values = append(values, contact.Name, contact.Email, contact.Phone)
+	}
+
+	var identifiers []string
+	for _, value := range values {
+		if value = strings.TrimSpace(value); value != "" {
+			identifiers = append(identifiers, value)
+		}
+	}
+	return identifiers
+}
+
+// RedactPersonalData replaces the identifiers found in text, ignoring case, with
+// ErasedPlaceholder. Identifiers only match whole words, so a short name is not erased
+// from inside longer words ("Al" leaves "Total" alone). Longer identifiers are replaced
+// first so an email is not left half-redacted by the name it contains.
+func RedactPersonalData(text string, identifiers []string) string {
+	sorted := append([]string(nil), identifiers...)
+	sort.SliceStable(sorted, func(i, j int) bool { return len(sorted[i]) > len(sorted[j]) })
+
+	for _, identifier := range sorted {
+		pattern := regexp.MustCompile("(?i)" + regexp.QuoteMeta(identifier))
+		text = redactWholeWords(text, pattern)
+	}
+	return text
+}
+
+// redactWholeWords replaces the matches of pattern that are not part of a longer word
+func redactWholeWords(text string, pattern *regexp.Regexp) string {
+	var redacted strings.Builder
+	start := 0
+	for offset := 0; offset < len(text); {
+		match := pattern.FindStringIndex(text[offset:])
+		if match == nil || match[0] == match[1] {
+			break
+		}
+		from, to := offset+match[0], offset+match[1]
+		if !isWholeWord(text, from, to) {
+			// Retry from the next rune: a whole-word match may overlap this one
+			_, size := utf8.DecodeRuneInString(text[from:])
+			offset = from + size
+			continue
+		}
+		redacted.WriteString(text[start:from])
+		redacted.WriteString(ErasedPlaceholder)
+		start, offset = to, to
+	}
+	redacted.WriteString(text[start:])
+	return redacted.String()
+}
+
+// isWholeWord reports whether text[from:to] is not glued to a word character on either
+// side. Matches that begin or end with punctuation (emails, phone numbers) only need the
+// boundary where they end with a word character.
+func isWholeWord(text string, from, to int) bool {
+	first, _ := utf8.DecodeRuneInString(text[from:to])
+	before, _ := utf8.DecodeLastRuneInString(text[:from])
+	if from > 0 && isWordRune(first) && isWordRune(before) {
+		return false
+	}
+
+	last, _ := utf8.DecodeLastRuneInString(text[from:to])
+	after, _ := utf8.DecodeRuneInString(text[to:])
+	return to == len(text) || !isWordRune(last) || !isWordRune(after)
+}
+
+// isWordRune reports whether r is part of a word
+func isWordRune(r rune) bool {
+	return r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r)
+}
@@ -0,0 +1,47 @@
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// maxTagLength bounds the length of a client tag
+const maxTagLength = 50
+
+// ClientTag is a free-form label grouping clients, e.g. an industry or a tier
+type ClientTag struct {
+	ID       uint   `gorm:"primaryKey"`
+	ClientID uint   `gorm:"not null;uniqueIndex:idx_client_tags_client_name"`
+	Name     string `gorm:"not null;uniqueIndex:idx_client_tags_client_name;index"`
+}
+
+// MarshalJSON renders a tag as its name
+func (t ClientTag) MarshalJSON() ([]byte, error) {
+	return json.Marshal(t.Name)
+}
+
+// UnmarshalJSON reads a tag from its name
+func (t *ClientTag) UnmarshalJSON(data []byte) error {
+	return json.Unmarshal(data, &t.Name)
+}
+
+// NormalizeTags lower-cases and trims tags, dropping blanks and duplicates
+func NormalizeTags(tags []string) ([]string, error) {
+	seen := make(map[string]bool, len(tags))
+	normalized := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		tag = strings.ToLower(strings.TrimSpace(tag))
+		if tag == "" || seen[tag] {
+			continue
+		}
+		if len(tag) > maxTagLength {
+			return nil, fmt.Errorf("tag %q is longer than %d characters", tag, maxTagLength)
+		}
+		seen[tag] = true
+		normalized = append(normalized, tag)
+	}
+	sort.Strings(normalized)
+	return normalized, nil
+}
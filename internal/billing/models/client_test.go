@@ -185,4 +185,51 @@ func (c Client) String() string {
 	return "Client{ID: " + string(rune(c.ID + '0')) + 
 		   ", Name: " + c.Name + 
 		   ", Email: " + c.Email + "}"
-}
\ No newline at end of file
+}
+
+func TestClient_Erase(t *testing.T) {
+	verifiedAt := time.Now()
+	client := Client{
+		ID:              42,
+		Name:            "John Doe",
+		Email:           "john@example.com",
+		Phone:           "+1234567890",
+		Address:         "1 Main Street",
+		TaxID:           "BE0403019261",
+		TaxIDScheme:     TaxIDSchemeEUVAT,
+		TaxIDVerifiedAt: &verifiedAt,
+	}
+	require.False(t, client.IsErased())
+
+	erasedAt := time.Now()
+	client.Erase(erasedAt)
+
+	assert.True(t, client.IsErased())
+	assert.Equal(t, uint(42), client.ID)
+	assert.Equal(t, "Erased client 42", client.Name)
+	assert.Equal(t, "erased-42@erased.invalid", client.Email)
+	assert.Empty(t, client.Phone)
+	assert.Empty(t, client.Address)
+	assert.Empty(t, client.TaxID)
+	assert.Empty(t, client.TaxIDScheme)
+	assert.Nil(t, client.TaxIDVerifiedAt)
+	assert.Equal(t, erasedAt, *client.ErasedAt)
+}
+
+func TestRedactPersonalData(t *testing.T) {
+	client := Client{Name: "John Doe", Email: "john@example.com", Phone: " "}
+	contacts := []ClientContact{{Name: "Jane Roe", Email: "jane@example.com"}}
+
+	identifiers := client.PersonalIdentifiers(contacts)
+	assert.Equal(t, []string{"John Doe", "john@example.com", "Jane Roe", "jane@example.com"}, identifiers)
+
+	text := RedactPersonalData("Consulting for JOHN DOE, contact jane@example.com (Jane Roe)", identifiers)
+	assert.Equal(t, "Consulting for [erased], contact [erased] ([erased])", text)
+
+	assert.Equal(t, "Hosting 2024", RedactPersonalData("Hosting 2024", identifiers))
+
+	short := []string{"Al", "Art"}
+	assert.Equal(t, "Total for [erased]: Article 3", RedactPersonalData("Total for Al: Article 3", short),
+		"short names inside longer words are left alone")
+	assert.Equal(t, "[erased] [erased], [erased]'s artwork", RedactPersonalData("Al AL, al's artwork", short))
+}
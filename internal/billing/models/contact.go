@@ -0,0 +1,64 @@
+package models
+
+import (
+	"strings"
+	"time"
+)
+
+type ContactRole string
+
+const (
+	ContactRoleBilling   ContactRole = "billing"
+	ContactRoleTechnical ContactRole = "technical"
+	ContactRoleGeneral   ContactRole = "general"
+)
+
+// ClientContact is a person to reach at a client for a given role (e.g. invoices go to billing contacts)
+type ClientContact struct {
+	ID        uint        `json:"id" gorm:"primaryKey"`
+	ClientID  uint        `json:"client_id" gorm:"not null;index"`
+	Name      string      `json:"name" gorm:"not null"`
+	Email     string      `json:"email" gorm:"not null"`
+	Phone     string      `json:"phone,omitempty"`
+	Role      ContactRole `json:"role" gorm:"not null;default:'general'"`
+	IsPrimary bool        `json:"is_primary" gorm:"not null;default:false"` // Primary contact for its role
+	CreatedAt time.Time   `json:"created_at"`
+	UpdatedAt time.Time   `json:"updated_at"`
+}
+
+// IsValidContactRole checks if the contact role is valid
+func IsValidContactRole(role ContactRole) bool {
+	switch role {
+	case ContactRoleBilling, ContactRoleTechnical, ContactRoleGeneral:
+		return true
+	default:
+		return false
+	}
+}
+
+type ContactRequest struct {
+	Name      string      `json:"name" binding:"required,min=2,max=100"`
+	Email     string      `json:"email" binding:"required,email"`
+	Phone     string      `json:"phone" binding:"max=20"`
+	Role      ContactRole `json:"role" binding:"required,oneof=billing technical general"`
+	IsPrimary bool        `json:"is_primary"`
+}
+
+type UpdateContactRequest struct {
+	Name      string      `json:"name" binding:"omitempty,min=2,max=100"`
+	Email     string      `json:"email" binding:"omitempty,email"`
+	Phone     string      `json:"phone" binding:"omitempty,max=20"`
+	Role      ContactRole `json:"role" binding:"omitempty,oneof=billing technical general"`
+	IsPrimary *bool       `json:"is_primary"`
+}
+
+// ToContact builds a client contact from the request
+func (r ContactRequest) ToContact() ClientContact {
+	return ClientContact{
+		Name:      strings.TrimSpace(r.Name),
+		Email:     strings.ToLower(strings.TrimSpace(r.Email)),
+		Phone:     strings.TrimSpace(r.Phone),
+		Role:      r.Role,
+		IsPrimary: r.IsPrimary,
+	}
+}
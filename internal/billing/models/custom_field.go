@@ -0,0 +1,235 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type CustomFieldType string
+
+const (
+	CustomFieldTypeText    CustomFieldType = "text"
+	CustomFieldTypeNumber  CustomFieldType = "number"
+	CustomFieldTypeBoolean CustomFieldType = "boolean"
+	CustomFieldTypeDate    CustomFieldType = "date"
+	CustomFieldTypeSelect  CustomFieldType = "select"
+)
+
+// maxCustomTextLength bounds the length of a text custom field value
+const maxCustomTextLength = 255
+
+var customFieldKeyPattern = regexp.MustCompile(`^[a-z][a-z0-9_]{0,49}$`)
+
+// IsValidCustomFieldKey reports whether key can be used as a custom field key
+func IsValidCustomFieldKey(key string) bool {
+	return customFieldKeyPattern.MatchString(key)
+}
+
+// CustomFieldDefinition declares a typed custom field that clients can carry
+type CustomFieldDefinition struct {
+	ID        uint            `json:"id" gorm:"primaryKey"`
+	Key       string          `json:"key" gorm:"uniqueIndex;not null"`
+	Label     string          `json:"label" gorm:"not null"`
+	Type      CustomFieldType `json:"type" gorm:"not null"`
+	Options   StringList      `json:"options,omitempty" gorm:"type:jsonb"` // Allowed values of a select field
+	Required  bool            `json:"required"`
+	CreatedAt time.Time       `json:"created_at"`
+	UpdatedAt time.Time       `json:"updated_at"`
+}
+
+// Validate checks the definition itself
+func (d CustomFieldDefinition) Validate() error {
+	if !IsValidCustomFieldKey(d.Key) {
+		return fmt.Errorf("key must be lower-case letters, digits and underscores, starting with a letter")
+	}
+	switch d.Type {
+	case CustomFieldTypeText, CustomFieldTypeNumber, CustomFieldTypeBoolean, CustomFieldTypeDate:
+		if len(d.Options) > 0 {
+			return fmt.Errorf("options are only allowed for select fields")
+		}
+	case CustomFieldTypeSelect:
+		if len(d.Options) == 0 {
+			return fmt.Errorf("select fields need at least one option")
+		}
+	default:
+		return fmt.Errorf("invalid custom field type: %q", d.Type)
+	}
+	return nil
+}
+
+// NormalizeValue checks a value against the field type and returns it in canonical form:
+// a string for text, date (YYYY-MM-DD) and select fields, float64 for numbers and bool for booleans.
+// Strings are accepted for numbers and booleans so values can come from CSV files or query strings.
+func (d CustomFieldDefinition) NormalizeValue(value interface{}) (interface{}, error) {
+	switch d.Type {
+	case CustomFieldTypeText:
+		text, ok := value.(string)
+		if !ok {
+			return nil, fmt.Errorf("%s must be a string", d.Key)
+		}
+		if len(text) > maxCustomTextLength {
+			return nil, fmt.Errorf("%s must be at most %d characters", d.Key, maxCustomTextLength)
+		}
+		return text, nil
+	case CustomFieldTypeNumber:
+		switch v := value.(type) {
+		case float64:
+			return v, nil
+		case string:
+			number, err := strconv.ParseFloat(strings.TrimSpace(v), 64)
+			if err != nil {
+				return nil, fmt.Errorf("%s must be a number", d.Key)
+			}
+			return number, nil
+		}
+		return nil, fmt.Errorf("%s must be a number", d.Key)
+	case CustomFieldTypeBoolean:
+		switch v := value.(type) {
+		case bool:
+			return v, nil
+		case string:
+			flag, err := strconv.ParseBool(strings.TrimSpace(v))
+			if err != nil {
+				return nil, fmt.Errorf("%s must be true or false", d.Key)
+			}
+			return flag, nil
+		}
+		return nil, fmt.Errorf("%s must be true or false", d.Key)
+	case CustomFieldTypeDate:
+		text, ok := value.(string)
+		if !ok {
+			return nil, fmt.Errorf("%s must be a date (YYYY-MM-DD)", d.Key)
+		}
+		date, err := time.Parse("2006-01-02", strings.TrimSpace(text))
+		if err != nil {
+			return nil, fmt.Errorf("%s must be a date (YYYY-MM-DD)", d.Key)
+		}
+		return date.Format("2006-01-02"), nil
+	case CustomFieldTypeSelect:
+		text, ok := value.(string)
+		if !ok {
+			return nil, fmt.Errorf("%s must be one of %s", d.Key, strings.Join(d.Options, ", "))
+		}
+		for _, option := range d.Options {
+			if text == option {
+				return text, nil
+			}
+		}
+		return nil, fmt.Errorf("%s must be one of %s", d.Key, strings.Join(d.Options, ", "))
+	}
+	return nil, fmt.Errorf("invalid custom field type: %q", d.Type)
+}
+
+// ValidateCustomFields checks values against the definitions and returns them normalized.
+// Unknown keys are rejected, and every required field must have a value.
+func ValidateCustomFields(definitions []CustomFieldDefinition, values CustomFieldValues) (CustomFieldValues, error) {
+	byKey := make(map[string]CustomFieldDefinition, len(definitions))
+	for _, definition := range definitions {
+		byKey[definition.Key] = definition
+	}
+
+	normalized := make(CustomFieldValues, len(values))
+	var problems []string
+	for _, key := range values.Keys() {
+		definition, ok := byKey[key]
+		if !ok {
+			problems = append(problems, fmt.Sprintf("unknown custom field: %s", key))
+			continue
+		}
+		value, err := definition.NormalizeValue(values[key])
+		if err != nil {
+			problems = append(problems, err.Error())
+			continue
+		}
+		normalized[key] = value
+	}
+	for _, definition := range definitions {
+		if _, ok := values[definition.Key]; definition.Required && !ok {
+			problems = append(problems, fmt.Sprintf("%s is required", definition.Key))
+		}
+	}
+
+	if len(problems) > 0 {
+		return nil, errors.New(strings.Join(problems, "; "))
+	}
+	return normalized, nil
+}
+
+// CustomFieldValues holds a client's custom field values by key, stored as JSONB
+type CustomFieldValues map[string]interface{}
+
+// Keys returns the keys in sorted order
+func (v CustomFieldValues) Keys() []string {
+	keys := make([]string, 0, len(v))
+	for key := range v {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// Value implements driver.Valuer
+func (v CustomFieldValues) Value() (driver.Value, error) {
+	if v == nil {
+		return "{}", nil
+	}
+	encoded, err := json.Marshal(v)
+	return string(encoded), err
+}
+
+// Scan implements sql.Scanner
+func (v *CustomFieldValues) Scan(src interface{}) error {
+	return scanJSON(src, v)
+}
+
+// StringList is a list of strings stored as a JSON array
+type StringList []string
+
+// Value implements driver.Valuer
+func (l StringList) Value() (driver.Value, error) {
+	if l == nil {
+		return "[]", nil
+	}
+	encoded, err := json.Marshal(l)
+	return string(encoded), err
+}
+
+// Scan implements sql.Scanner
+func (l *StringList) Scan(src interface{}) error {
+	return scanJSON(src, l)
+}
+
+// scanJSON decodes a JSON/JSONB column into dest
+func scanJSON(src interface{}, dest interface{}) error {
+	switch data := src.(type) {
+	case nil:
+		return nil
+	case []byte:
+		return json.Unmarshal(data, dest)
+	case string:
+		return json.Unmarshal([]byte(data), dest)
+	}
+	return fmt.Errorf("cannot scan %T into %T", src, dest)
+}
+
+type CreateCustomFieldRequest struct {
+	Key      string          `json:"key" binding:"required,max=50"`
+	Label    string          `json:"label" binding:"required,max=100"`
+	Type     CustomFieldType `json:"type" binding:"required,oneof=text number boolean date select"`
+	Options  []string        `json:"options" binding:"omitempty,dive,min=1,max=100"`
+	Required bool            `json:"required"`
+}
+
+// Key and type are immutable once values exist, so only presentation and rules can change
+type UpdateCustomFieldRequest struct {
+	Label    string   `json:"label" binding:"omitempty,max=100"`
+	Options  []string `json:"options" binding:"omitempty,dive,min=1,max=100"`
+	Required *bool    `json:"required"`
+}
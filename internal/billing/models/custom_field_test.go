@@ -0,0 +1,85 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCustomFieldDefinition_Validate(t *testing.T) {
+	assert.NoError(t, CustomFieldDefinition{Key: "industry", Type: CustomFieldTypeText}.Validate())
+	assert.NoError(t, CustomFieldDefinition{Key: "tier", Type: CustomFieldTypeSelect, Options: StringList{"gold", "silver"}}.Validate())
+
+	assert.Error(t, CustomFieldDefinition{Key: "Industry", Type: CustomFieldTypeText}.Validate())
+	assert.Error(t, CustomFieldDefinition{Key: "1st_contact", Type: CustomFieldTypeDate}.Validate())
+	assert.Error(t, CustomFieldDefinition{Key: "tier", Type: CustomFieldTypeSelect}.Validate())
+	assert.Error(t, CustomFieldDefinition{Key: "size", Type: CustomFieldTypeNumber, Options: StringList{"1"}}.Validate())
+	assert.Error(t, CustomFieldDefinition{Key: "size", Type: "integer"}.Validate())
+}
+
+func TestCustomFieldDefinition_NormalizeValue(t *testing.T) {
+	tests := []struct {
+		name       string
+		definition CustomFieldDefinition
+		value      interface{}
+		expected   interface{}
+		wantErr    bool
+	}{
+		{"text", CustomFieldDefinition{Key: "industry", Type: CustomFieldTypeText}, "Retail", "Retail", false},
+		{"text rejects numbers", CustomFieldDefinition{Key: "industry", Type: CustomFieldTypeText}, 12.0, nil, true},
+		{"number", CustomFieldDefinition{Key: "employees", Type: CustomFieldTypeNumber}, 250.0, 250.0, false},
+		{"number from string", CustomFieldDefinition{Key: "employees", Type: CustomFieldTypeNumber}, " 250 ", 250.0, false},
+		{"invalid number", CustomFieldDefinition{Key: "employees", Type: CustomFieldTypeNumber}, "many", nil, true},
+		{"boolean", CustomFieldDefinition{Key: "vip", Type: CustomFieldTypeBoolean}, true, true, false},
+		{"boolean from string", CustomFieldDefinition{Key: "vip", Type: CustomFieldTypeBoolean}, "false", false, false},
+		{"date", CustomFieldDefinition{Key: "since", Type: CustomFieldTypeDate}, "2024-02-29", "2024-02-29", false},
+		{"invalid date", CustomFieldDefinition{Key: "since", Type: CustomFieldTypeDate}, "2023-02-29", nil, true},
+		{"select", CustomFieldDefinition{Key: "tier", Type: CustomFieldTypeSelect, Options: StringList{"gold", "silver"}}, "gold", "gold", false},
+		{"select unknown option", CustomFieldDefinition{Key: "tier", Type: CustomFieldTypeSelect, Options: StringList{"gold", "silver"}}, "bronze", nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			value, err := tt.definition.NormalizeValue(tt.value)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, value)
+		})
+	}
+}
+
+func TestValidateCustomFields(t *testing.T) {
+	definitions := []CustomFieldDefinition{
+		{Key: "industry", Type: CustomFieldTypeText, Required: true},
+		{Key: "employees", Type: CustomFieldTypeNumber},
+	}
+
+	values, err := ValidateCustomFields(definitions, CustomFieldValues{"industry": "Retail", "employees": "40"})
+	require.NoError(t, err)
+	assert.Equal(t, CustomFieldValues{"industry": "Retail", "employees": 40.0}, values)
+
+	_, err = ValidateCustomFields(definitions, CustomFieldValues{"employees": 40.0})
+	assert.ErrorContains(t, err, "industry is required")
+
+	_, err = ValidateCustomFields(definitions, CustomFieldValues{"industry": "Retail", "color": "blue"})
+	assert.ErrorContains(t, err, "unknown custom field: color")
+}
+
+func TestCustomFieldValues_ValueAndScan(t *testing.T) {
+	value, err := CustomFieldValues{"tier": "gold", "employees": 40.0}.Value()
+	require.NoError(t, err)
+
+	var scanned CustomFieldValues
+	require.NoError(t, scanned.Scan([]byte(value.(string))))
+	assert.Equal(t, CustomFieldValues{"tier": "gold", "employees": 40.0}, scanned)
+
+	empty, err := CustomFieldValues(nil).Value()
+	require.NoError(t, err)
+	assert.Equal(t, "{}", empty)
+
+	assert.Error(t, scanned.Scan(42))
+}
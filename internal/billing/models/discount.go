@@ -0,0 +1,125 @@
+package models
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type DiscountType string
+
+const (
+	DiscountTypePercentage DiscountType = "percentage"
+	DiscountTypeFixed      DiscountType = "fixed"
+)
+
+// Discount is a percentage or fixed reduction applied to an invoice or an invoice line.
+// It is embedded in the owning table as discount_type / discount_value columns.
+type Discount struct {
+	Type  DiscountType `json:"type,omitempty" binding:"required,oneof=percentage fixed"`
+	Value float64      `json:"value,omitempty" binding:"gte=0"`
+}
+
+// IsZero reports whether no discount is set
+func (d Discount) IsZero() bool {
+	return d.Type == "" || d.Value == 0
+}
+
+// Validate checks the discount business rules
+func (d Discount) Validate() error {
+	if d.Type == "" && d.Value == 0 {
+		return nil
+	}
+	if d.Type != DiscountTypePercentage && d.Type != DiscountTypeFixed {
+		return fmt.Errorf("discount type must be 'percentage' or 'fixed'")
+	}
+	if d.Value < 0 {
+		return fmt.Errorf("discount value cannot be negative")
+	}
+	if d.Type == DiscountTypePercentage && d.Value > 100 {
+		return fmt.Errorf("percentage discount cannot exceed 100")
+	}
+	return nil
+}
+
+// AmountOf returns the discount amount for the given base, never exceeding the base itself
+func (d Discount) AmountOf(base float64) float64 {
+	if d.IsZero() || base <= 0 {
+		return 0
+	}
+
+	var amount float64
+	switch d.Type {
+	case DiscountTypePercentage:
+		amount = base * math.Min(d.Value, 100) / 100
+	case DiscountTypeFixed:
+		amount = d.Value
+	}
+
+	return roundMoney(math.Min(amount, base))
+}
+
+// DiscountCode is a reusable promo code that grants an invoice-level discount
+type DiscountCode struct {
+	ID          uint           `json:"id" gorm:"primaryKey"`
+	Code        string         `json:"code" gorm:"uniqueIndex;not null"`
+	Description string         `json:"description"`
+	Discount    Discount       `json:"discount" gorm:"embedded;embeddedPrefix:discount_"`
+	ValidFrom   *time.Time     `json:"valid_from,omitempty"`
+	ValidUntil  *time.Time     `json:"valid_until,omitempty"`
+	MaxUses     int            `json:"max_uses" gorm:"not null;default:0"` // 0 means unlimited
+	UsedCount   int            `json:"used_count" gorm:"not null;default:0"`
+	IsActive    bool           `json:"is_active" gorm:"not null"` // Always set on insert
+	CreatedAt   time.Time      `json:"created_at"`
+	UpdatedAt   time.Time      `json:"updated_at"`
+	DeletedAt   gorm.DeletedAt `json:"-" gorm:"index"`
+}
+
+// CanRedeem checks whether the code may be applied to an invoice at the given time
+func (d DiscountCode) CanRedeem(at time.Time) error {
+	if !d.IsActive {
+		return fmt.Errorf("discount code %s is not active", d.Code)
+	}
+	if d.ValidFrom != nil && at.Before(*d.ValidFrom) {
+		return fmt.Errorf("discount code %s is not valid before %s", d.Code, d.ValidFrom.Format("2006-01-02"))
+	}
+	if d.ValidUntil != nil && at.After(*d.ValidUntil) {
+		return fmt.Errorf("discount code %s expired on %s", d.Code, d.ValidUntil.Format("2006-01-02"))
+	}
+	if d.MaxUses > 0 && d.UsedCount >= d.MaxUses {
+		return fmt.Errorf("discount code %s has reached its usage limit", d.Code)
+	}
+	return nil
+}
+
+// NormalizeDiscountCode returns the canonical (trimmed, upper-case) form of a code
+func NormalizeDiscountCode(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
+}
+
+type CreateDiscountCodeRequest struct {
+	Code        string     `json:"code" binding:"required,min=3,max=50"`
+	Description string     `json:"description" binding:"max=255"`
+	Discount    Discount   `json:"discount" binding:"required"`
+	ValidFrom   *time.Time `json:"valid_from"`
+	ValidUntil  *time.Time `json:"valid_until"`
+	MaxUses     int        `json:"max_uses" binding:"gte=0"`
+	IsActive    *bool      `json:"is_active"`
+}
+
+type UpdateDiscountCodeRequest struct {
+	Description string     `json:"description" binding:"omitempty,max=255"`
+	Discount    *Discount  `json:"discount"`
+	ValidFrom   *time.Time `json:"valid_from"`
+	ValidUntil  *time.Time `json:"valid_until"`
+	MaxUses     *int       `json:"max_uses" binding:"omitempty,gte=0"`
+	IsActive    *bool      `json:"is_active"`
+}
+
+// roundMoney rounds an amount to cents
+func roundMoney(amount float64) float64 {
+	return math.Round(amount*100) / 100
+}
@@ -0,0 +1,117 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiscount_AmountOf(t *testing.T) {
+	tests := []struct {
+		name     string
+		discount Discount
+		base     float64
+		expected float64
+	}{
+		{"no discount", Discount{}, 100, 0},
+		{"percentage", Discount{Type: DiscountTypePercentage, Value: 10}, 250, 25},
+		{"percentage rounds to cents", Discount{Type: DiscountTypePercentage, Value: 3}, 33.33, 1},
+		{"fixed", Discount{Type: DiscountTypeFixed, Value: 15}, 100, 15},
+		{"fixed capped at base", Discount{Type: DiscountTypeFixed, Value: 150}, 100, 100},
+		{"zero base", Discount{Type: DiscountTypeFixed, Value: 15}, 0, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, tt.discount.AmountOf(tt.base))
+		})
+	}
+}
+
+func TestDiscount_Validate(t *testing.T) {
+	assert.NoError(t, Discount{}.Validate())
+	assert.NoError(t, Discount{Type: DiscountTypePercentage, Value: 100}.Validate())
+	assert.NoError(t, Discount{Type: DiscountTypeFixed, Value: 500}.Validate())
+
+	assert.Error(t, Discount{Type: DiscountTypePercentage, Value: 101}.Validate())
+	assert.Error(t, Discount{Type: DiscountTypeFixed, Value: -1}.Validate())
+	assert.Error(t, Discount{Type: "bogus", Value: 5}.Validate())
+}
+
+func TestInvoice_CalculateTotals(t *testing.T) {
+	t.Run("amount only", func(t *testing.T) {
+		invoice := Invoice{
+			Amount:   200,
+			Discount: Discount{Type: DiscountTypePercentage, Value: 5},
+		}
+		invoice.CalculateTotals()
+
+		assert.Equal(t, 200.0, invoice.Amount)
+		assert.Equal(t, 10.0, invoice.DiscountAmount)
+		assert.Equal(t, 190.0, invoice.Total)
+	})
+
+	t.Run("lines with line and invoice discounts", func(t *testing.T) {
+		invoice := Invoice{
+			Amount: 1, // ignored when lines are present
+			Lines: []InvoiceLine{
+				{Description: "Consulting", Quantity: 10, UnitPrice: 100, Discount: Discount{Type: DiscountTypePercentage, Value: 10}},
+				{Description: "Hosting", Quantity: 1, UnitPrice: 50},
+			},
+			Discount: Discount{Type: DiscountTypeFixed, Value: 50},
+		}
+		invoice.CalculateTotals()
+
+		assert.Equal(t, 1050.0, invoice.Amount)
+		assert.Equal(t, 100.0, invoice.Lines[0].DiscountAmount)
+		assert.Equal(t, 900.0, invoice.Lines[0].Total)
+		assert.Equal(t, 50.0, invoice.Lines[1].Total)
+		// 100 line discount + 50 invoice discount on the remaining 950
+		assert.Equal(t, 150.0, invoice.DiscountAmount)
+		assert.Equal(t, 900.0, invoice.Total)
+	})
+
+	t.Run("no discount", func(t *testing.T) {
+		invoice := Invoice{Amount: 99.99}
+		invoice.CalculateTotals()
+
+		assert.Equal(t, 0.0, invoice.DiscountAmount)
+		assert.Equal(t, 99.99, invoice.Total)
+	})
+}
+
+func TestDiscountCode_CanRedeem(t *testing.T) {
+	now := time.Now()
+	yesterday := now.AddDate(0, 0, -1)
+	tomorrow := now.AddDate(0, 0, 1)
+
+	tests := []struct {
+		name      string
+		code      DiscountCode
+		expectErr bool
+	}{
+		{"active unlimited", DiscountCode{Code: "WELCOME", IsActive: true}, false},
+		{"within window", DiscountCode{Code: "SPRING", IsActive: true, ValidFrom: &yesterday, ValidUntil: &tomorrow}, false},
+		{"inactive", DiscountCode{Code: "OLD", IsActive: false}, true},
+		{"not yet valid", DiscountCode{Code: "SOON", IsActive: true, ValidFrom: &tomorrow}, true},
+		{"expired", DiscountCode{Code: "PAST", IsActive: true, ValidUntil: &yesterday}, true},
+		{"usage limit reached", DiscountCode{Code: "ONCE", IsActive: true, MaxUses: 1, UsedCount: 1}, true},
+		{"usage limit not reached", DiscountCode{Code: "TWICE", IsActive: true, MaxUses: 2, UsedCount: 1}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.code.CanRedeem(now)
+			if tt.expectErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestNormalizeDiscountCode(t *testing.T) {
+	assert.Equal(t, "SUMMER10", NormalizeDiscountCode("  summer10 "))
+}
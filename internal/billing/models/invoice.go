@@ -19,7 +19,7 @@ type Invoice struct {
 	ID          uint           `json:"id" gorm:"primaryKey"`
 	Number      string         `json:"number" gorm:"uniqueIndex;not null"`
 	ClientID    uint           `json:"client_id" gorm:"not null"`
-	Amount      float64        `json:"amount" gorm:"not null"`
+	Amount      float64        `json:"amount" gorm:"not null"` // Gross amount before discounts
 	Status      InvoiceStatus  `json:"status" gorm:"default:'draft'"`
 	IssueDate   time.Time      `json:"issue_date"`
 	DueDate     time.Time      `json:"due_date"`
@@ -28,25 +28,74 @@ type Invoice struct {
 	UpdatedAt   time.Time      `json:"updated_at"`
 	DeletedAt   gorm.DeletedAt `json:"-" gorm:"index"`
 	
-	// Relationship
-	Client Client `json:"client,omitempty" gorm:"foreignKey:ClientID"`
+	// Discounts and totals
+	Discount       Discount `json:"discount" gorm:"embedded;embeddedPrefix:discount_"`
+	DiscountCode   string   `json:"discount_code,omitempty"`
+	DiscountAmount float64  `json:"discount_amount" gorm:"not null;default:0"` // Line and invoice discounts combined
+	Total          float64  `json:"total" gorm:"not null"`
+	
+	// Payment terms
+	PaymentTermID        *uint      `json:"payment_term_id,omitempty"`
+	PaymentTermCode      string     `json:"payment_term_code,omitempty"`
+	EarlyPaymentDate     *time.Time `json:"early_payment_date,omitempty"`
+	EarlyPaymentDiscount float64    `json:"early_payment_discount" gorm:"not null;default:0"`
+	
+	// Rules of the applied payment term, so later edits of the term leave the invoice unchanged
+	PaymentTermType             PaymentTermType `json:"-"`
+	PaymentTermDays             int             `json:"-" gorm:"not null;default:0"`
+	EarlyPaymentDays            int             `json:"-" gorm:"not null;default:0"`
+	EarlyPaymentDiscountPercent float64         `json:"-" gorm:"not null;default:0"`
+	
+	// Relationships
+	Client      Client        `json:"client,omitempty" gorm:"foreignKey:ClientID"`
+	Lines       []InvoiceLine `json:"lines,omitempty" gorm:"foreignKey:InvoiceID"`
+	PaymentTerm *PaymentTerm  `json:"payment_term,omitempty" gorm:"foreignKey:PaymentTermID"`
+}
+
+// InvoiceLine is a single billable item of an invoice
+type InvoiceLine struct {
+	ID             uint      `json:"id" gorm:"primaryKey"`
+	InvoiceID      uint      `json:"invoice_id" gorm:"not null;index"`
+	Description    string    `json:"description" gorm:"not null"`
+	Quantity       float64   `json:"quantity" gorm:"not null;default:1"`
+	UnitPrice      float64   `json:"unit_price" gorm:"not null"`
+	Discount       Discount  `json:"discount" gorm:"embedded;embeddedPrefix:discount_"`
+	DiscountAmount float64   `json:"discount_amount" gorm:"not null;default:0"`
+	Total          float64   `json:"total" gorm:"not null"`
+	CreatedAt      time.Time `json:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at"`
+}
+
+type InvoiceLineRequest struct {
+	Description string    `json:"description" binding:"required,max=255"`
+	Quantity    float64   `json:"quantity" binding:"required,gt=0"`
+	UnitPrice   float64   `json:"unit_price" binding:"gte=0"`
+	Discount    *Discount `json:"discount"`
 }
 
 type CreateInvoiceRequest struct {
-	ClientID    uint          `json:"client_id" binding:"required"`
-	Amount      float64       `json:"amount" binding:"required,gt=0"`
-	Status      InvoiceStatus `json:"status" binding:"omitempty,oneof=draft sent paid overdue cancelled"`
-	IssueDate   time.Time     `json:"issue_date" binding:"required"`
-	DueDate     time.Time     `json:"due_date" binding:"required"`
-	Description string        `json:"description" binding:"max=500"`
+	ClientID      uint                 `json:"client_id" binding:"required"`
+	Amount        float64              `json:"amount" binding:"required_without=Lines,omitempty,gt=0"`
+	Status        InvoiceStatus        `json:"status" binding:"omitempty,oneof=draft sent paid overdue cancelled"`
+	IssueDate     time.Time            `json:"issue_date" binding:"required"`
+	DueDate       time.Time            `json:"due_date"` // Computed from payment terms when omitted
+	Description   string               `json:"description" binding:"max=500"`
+	Lines         []InvoiceLineRequest `json:"lines" binding:"omitempty,dive"`
+	Discount      *Discount            `json:"discount"`
+	DiscountCode  string               `json:"discount_code" binding:"omitempty,max=50"`
+	PaymentTermID *uint                `json:"payment_term_id"` // Overrides the client's payment terms
 }
 
 type UpdateInvoiceRequest struct {
-	Amount      float64       `json:"amount" binding:"omitempty,gt=0"`
-	Status      InvoiceStatus `json:"status" binding:"omitempty,oneof=draft sent paid overdue cancelled"`
-	IssueDate   time.Time     `json:"issue_date" binding:"omitempty"`
-	DueDate     time.Time     `json:"due_date" binding:"omitempty"`
-	Description string        `json:"description" binding:"omitempty,max=500"`
+	Amount      float64              `json:"amount" binding:"omitempty,gt=0"`
+	Status      InvoiceStatus        `json:"status" binding:"omitempty,oneof=draft sent paid overdue cancelled"`
+	IssueDate   time.Time            `json:"issue_date" binding:"omitempty"`
+	DueDate     time.Time            `json:"due_date" binding:"omitempty"`
+	Description string               `json:"description" binding:"omitempty,max=500"`
+	Lines       []InvoiceLineRequest `json:"lines" binding:"omitempty,dive"` // Replaces all existing lines when provided
+	Discount    *Discount            `json:"discount"`
+	
+	PaymentTermID *uint `json:"payment_term_id"` // Applies another payment term
 }
 
 // IsOverdue checks if the invoice is overdue
@@ -55,4 +104,66 @@ func (i Invoice) IsOverdue() bool {
 		return false
 	}
 	return time.Now().After(i.DueDate)
-}
\ No newline at end of file
+}
+
+// ApplyPaymentTerm records the payment term and computes the early-payment discount
+// from the current Total. A due date that is already set is kept.
+func (i *Invoice) ApplyPaymentTerm(term PaymentTerm) {
+	if i.DueDate.IsZero() {
+		i.DueDate = term.DueDate(i.IssueDate)
+	}
+	i.PaymentTermCode = term.Code
+	i.PaymentTermType = term.Type
+	i.PaymentTermDays = term.Days
+	i.EarlyPaymentDays = term.EarlyPaymentDays
+	i.EarlyPaymentDiscountPercent = term.EarlyPaymentDiscountPercent
+	i.EarlyPaymentDate = term.EarlyPaymentDate(i.IssueDate)
+	i.EarlyPaymentDiscount = term.EarlyPaymentDiscount(i.Total)
+}
+
+// AppliedPaymentTerm returns the payment term rules recorded on the invoice, as they
+// were when the term was applied. Invoices without recorded terms return false.
+func (i Invoice) AppliedPaymentTerm() (PaymentTerm, bool) {
+	if i.PaymentTermType == "" {
+		return PaymentTerm{}, false
+	}
+	return PaymentTerm{
+		Code:                        i.PaymentTermCode,
+		Type:                        i.PaymentTermType,
+		Days:                        i.PaymentTermDays,
+		EarlyPaymentDays:            i.EarlyPaymentDays,
+		EarlyPaymentDiscountPercent: i.EarlyPaymentDiscountPercent,
+	}, true
+}
+
+// Gross returns the line amount before discount
+func (l InvoiceLine) Gross() float64 {
+	return roundMoney(l.Quantity * l.UnitPrice)
+}
+
+// CalculateTotals computes the line discount and net total
+func (l *InvoiceLine) CalculateTotals() {
+	gross := l.Gross()
+	l.DiscountAmount = l.Discount.AmountOf(gross)
+	l.Total = roundMoney(gross - l.DiscountAmount)
+}
+
+// CalculateTotals recomputes discounts and the payable total.
+// When the invoice has lines, Amount is derived from them; line discounts are
+// applied first and the invoice-level discount applies to the remaining net amount.
+func (i *Invoice) CalculateTotals() {
+	var lineDiscounts float64
+	if len(i.Lines) > 0 {
+		var gross float64
+		for idx := range i.Lines {
+			i.Lines[idx].CalculateTotals()
+			gross += i.Lines[idx].Gross()
+			lineDiscounts += i.Lines[idx].DiscountAmount
+		}
+		i.Amount = roundMoney(gross)
+	}
+
+	invoiceDiscount := i.Discount.AmountOf(roundMoney(i.Amount - lineDiscounts))
+	i.DiscountAmount = roundMoney(lineDiscounts + invoiceDiscount)
+	i.Total = roundMoney(i.Amount - i.DiscountAmount)
+}
@@ -0,0 +1,117 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type PaymentTermType string
+
+const (
+	// PaymentTermNet makes the invoice due a number of days after the issue date (e.g. net 30)
+	PaymentTermNet PaymentTermType = "net"
+	// PaymentTermEndOfMonth makes the invoice due a number of days after the end of the issue month (e.g. EOM + 15)
+	PaymentTermEndOfMonth PaymentTermType = "end_of_month"
+)
+
+// PaymentTerm is a named rule for computing due dates and early-payment discounts (e.g. "2/10 net 30")
+type PaymentTerm struct {
+	ID                          uint            `json:"id" gorm:"primaryKey"`
+	Code                        string          `json:"code" gorm:"uniqueIndex;not null"`
+	Name                        string          `json:"name" gorm:"not null"`
+	Type                        PaymentTermType `json:"type" gorm:"not null;default:'net'"`
+	Days                        int             `json:"days" gorm:"not null"`
+	EarlyPaymentDays            int             `json:"early_payment_days" gorm:"not null;default:0"`
+	EarlyPaymentDiscountPercent float64         `json:"early_payment_discount_percent" gorm:"not null;default:0"`
+	IsActive                    bool            `json:"is_active" gorm:"default:true"`
+	CreatedAt                   time.Time       `json:"created_at"`
+	UpdatedAt                   time.Time       `json:"updated_at"`
+	DeletedAt                   gorm.DeletedAt  `json:"-" gorm:"index"`
+}
+
+// NetPaymentTerm returns an unsaved "net N days" term, used when a client has no terms assigned
+func NetPaymentTerm(days int) PaymentTerm {
+	return PaymentTerm{
+		Code:     fmt.Sprintf("NET%d", days),
+		Name:     fmt.Sprintf("Net %d", days),
+		Type:     PaymentTermNet,
+		Days:     days,
+		IsActive: true,
+	}
+}
+
+// Validate checks the payment term business rules
+func (p PaymentTerm) Validate() error {
+	if strings.TrimSpace(p.Code) == "" {
+		return fmt.Errorf("payment term code is required")
+	}
+	if p.Type != PaymentTermNet && p.Type != PaymentTermEndOfMonth {
+		return fmt.Errorf("payment term type must be 'net' or 'end_of_month'")
+	}
+	if p.Days < 0 {
+		return fmt.Errorf("payment term days cannot be negative")
+	}
+	if p.EarlyPaymentDiscountPercent < 0 || p.EarlyPaymentDiscountPercent > 100 {
+		return fmt.Errorf("early payment discount must be between 0 and 100 percent")
+	}
+	if p.EarlyPaymentDiscountPercent > 0 && p.EarlyPaymentDays <= 0 {
+		return fmt.Errorf("early payment days are required when an early payment discount is set")
+	}
+	if p.Type == PaymentTermNet && p.EarlyPaymentDays > p.Days {
+		return fmt.Errorf("early payment days cannot exceed payment days")
+	}
+	return nil
+}
+
+// DueDate computes the due date for an invoice issued on the given date
+func (p PaymentTerm) DueDate(issueDate time.Time) time.Time {
+	if p.Type == PaymentTermEndOfMonth {
+		firstOfMonth := time.Date(issueDate.Year(), issueDate.Month(), 1, 0, 0, 0, 0, issueDate.Location())
+		endOfMonth := firstOfMonth.AddDate(0, 1, -1)
+		return endOfMonth.AddDate(0, 0, p.Days)
+	}
+	return issueDate.AddDate(0, 0, p.Days)
+}
+
+// HasEarlyPaymentDiscount reports whether the term grants a discount for early payment
+func (p PaymentTerm) HasEarlyPaymentDiscount() bool {
+	return p.EarlyPaymentDiscountPercent > 0 && p.EarlyPaymentDays > 0
+}
+
+// EarlyPaymentDate returns the last day the early-payment discount applies, or nil
+func (p PaymentTerm) EarlyPaymentDate(issueDate time.Time) *time.Time {
+	if !p.HasEarlyPaymentDiscount() {
+		return nil
+	}
+	date := issueDate.AddDate(0, 0, p.EarlyPaymentDays)
+	return &date
+}
+
+// EarlyPaymentDiscount returns the discount granted on the given total when paid early
+func (p PaymentTerm) EarlyPaymentDiscount(total float64) float64 {
+	if !p.HasEarlyPaymentDiscount() {
+		return 0
+	}
+	return Discount{Type: DiscountTypePercentage, Value: p.EarlyPaymentDiscountPercent}.AmountOf(total)
+}
+
+type CreatePaymentTermRequest struct {
+	Code                        string          `json:"code" binding:"required,max=20"`
+	Name                        string          `json:"name" binding:"required,max=100"`
+	Type                        PaymentTermType `json:"type" binding:"required,oneof=net end_of_month"`
+	Days                        int             `json:"days" binding:"gte=0"`
+	EarlyPaymentDays            int             `json:"early_payment_days" binding:"gte=0"`
+	EarlyPaymentDiscountPercent float64         `json:"early_payment_discount_percent" binding:"gte=0,lte=100"`
+}
+
+type UpdatePaymentTermRequest struct {
+	Name                        string          `json:"name" binding:"omitempty,max=100"`
+	Type                        PaymentTermType `json:"type" binding:"omitempty,oneof=net end_of_month"`
+	Days                        *int            `json:"days" binding:"omitempty,gte=0"`
+	EarlyPaymentDays            *int            `json:"early_payment_days" binding:"omitempty,gte=0"`
+	EarlyPaymentDiscountPercent *float64        `json:"early_payment_discount_percent" binding:"omitempty,gte=0,lte=100"`
+	IsActive                    *bool           `json:"is_active"`
+}
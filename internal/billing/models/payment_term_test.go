@@ -0,0 +1,127 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPaymentTerm_DueDate(t *testing.T) {
+	tests := []struct {
+		name      string
+		term      PaymentTerm
+		issueDate time.Time
+		expected  time.Time
+	}{
+		{
+			name:      "net 30",
+			term:      PaymentTerm{Code: "NET30", Type: PaymentTermNet, Days: 30},
+			issueDate: time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC),
+			expected:  time.Date(2024, 2, 14, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:      "end of month + 15",
+			term:      PaymentTerm{Code: "EOM15", Type: PaymentTermEndOfMonth, Days: 15},
+			issueDate: time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC),
+			expected:  time.Date(2024, 2, 15, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:      "end of month in leap february",
+			term:      PaymentTerm{Code: "EOM15", Type: PaymentTermEndOfMonth, Days: 15},
+			issueDate: time.Date(2024, 2, 29, 0, 0, 0, 0, time.UTC),
+			expected:  time.Date(2024, 3, 15, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:      "due on receipt",
+			term:      PaymentTerm{Code: "NET0", Type: PaymentTermNet, Days: 0},
+			issueDate: time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC),
+			expected:  time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, tt.term.DueDate(tt.issueDate))
+		})
+	}
+}
+
+func TestPaymentTerm_EarlyPayment(t *testing.T) {
+	term := PaymentTerm{Code: "2/10NET30", Type: PaymentTermNet, Days: 30, EarlyPaymentDays: 10, EarlyPaymentDiscountPercent: 2}
+	issueDate := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	require.True(t, term.HasEarlyPaymentDiscount())
+	earlyDate := term.EarlyPaymentDate(issueDate)
+	require.NotNil(t, earlyDate)
+	assert.Equal(t, time.Date(2024, 3, 11, 0, 0, 0, 0, time.UTC), *earlyDate)
+	assert.Equal(t, 25.0, term.EarlyPaymentDiscount(1250))
+
+	net := NetPaymentTerm(30)
+	assert.False(t, net.HasEarlyPaymentDiscount())
+	assert.Nil(t, net.EarlyPaymentDate(issueDate))
+	assert.Equal(t, 0.0, net.EarlyPaymentDiscount(1250))
+}
+
+func TestPaymentTerm_Validate(t *testing.T) {
+	assert.NoError(t, NetPaymentTerm(30).Validate())
+	assert.NoError(t, PaymentTerm{Code: "EOM15", Type: PaymentTermEndOfMonth, Days: 15}.Validate())
+
+	assert.Error(t, PaymentTerm{Type: PaymentTermNet, Days: 30}.Validate(), "missing code")
+	assert.Error(t, PaymentTerm{Code: "X", Type: "weekly", Days: 7}.Validate(), "invalid type")
+	assert.Error(t, PaymentTerm{Code: "X", Type: PaymentTermNet, Days: -1}.Validate(), "negative days")
+	assert.Error(t, PaymentTerm{Code: "X", Type: PaymentTermNet, Days: 30, EarlyPaymentDiscountPercent: 2}.Validate(), "discount without days")
+	assert.Error(t, PaymentTerm{Code: "X", Type: PaymentTermNet, Days: 10, EarlyPaymentDays: 20, EarlyPaymentDiscountPercent: 2}.Validate(), "early window beyond due date")
+}
+
+func TestInvoice_ApplyPaymentTerm(t *testing.T) {
+	issueDate := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	term := PaymentTerm{Code: "2/10NET30", Type: PaymentTermNet, Days: 30, EarlyPaymentDays: 10, EarlyPaymentDiscountPercent: 2}
+
+	t.Run("computes due date", func(t *testing.T) {
+		invoice := Invoice{Amount: 500, IssueDate: issueDate}
+		invoice.CalculateTotals()
+		invoice.ApplyPaymentTerm(term)
+
+		assert.Equal(t, time.Date(2024, 3, 31, 0, 0, 0, 0, time.UTC), invoice.DueDate)
+		assert.Equal(t, "2/10NET30", invoice.PaymentTermCode)
+		assert.Equal(t, 10.0, invoice.EarlyPaymentDiscount)
+	})
+
+	t.Run("keeps explicit due date", func(t *testing.T) {
+		dueDate := time.Date(2024, 4, 15, 0, 0, 0, 0, time.UTC)
+		invoice := Invoice{Amount: 500, IssueDate: issueDate, DueDate: dueDate}
+		invoice.CalculateTotals()
+		invoice.ApplyPaymentTerm(term)
+
+		assert.Equal(t, dueDate, invoice.DueDate)
+	})
+}
+
+func TestInvoice_AppliedPaymentTerm(t *testing.T) {
+	invoice := Invoice{Amount: 500, IssueDate: time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)}
+	_, ok := invoice.AppliedPaymentTerm()
+	assert.False(t, ok, "no terms recorded yet")
+
+	term := PaymentTerm{Code: "2/10NET30", Type: PaymentTermNet, Days: 30, EarlyPaymentDays: 10, EarlyPaymentDiscountPercent: 2}
+	invoice.CalculateTotals()
+	invoice.ApplyPaymentTerm(term)
+
+	// Later edits of the term do not reach the invoice
+	term.Days, term.EarlyPaymentDiscountPercent = 45, 5
+
+	applied, ok := invoice.AppliedPaymentTerm()
+	require.True(t, ok)
+	assert.Equal(t, 30, applied.Days)
+	assert.Equal(t, 2.0, applied.EarlyPaymentDiscountPercent)
+
+	// Re-applying the recorded rules follows a new total and issue date
+	invoice.Amount = 1000
+	invoice.IssueDate = time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC)
+	invoice.DueDate = time.Time{}
+	invoice.CalculateTotals()
+	invoice.ApplyPaymentTerm(applied)
+	assert.Equal(t, time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC), invoice.DueDate)
+	assert.Equal(t, 20.0, invoice.EarlyPaymentDiscount)
+}
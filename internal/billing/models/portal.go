@@ -0,0 +1,25 @@
+package models
+
+import "time"
+
+// PortalToken records a signed access link issued to a client for the self-service portal.
+// The signed token itself is never stored; revoking the record invalidates it.
+type PortalToken struct {
+	ID         uint       `json:"id" gorm:"primaryKey"`
+	ClientID   uint       `json:"client_id" gorm:"not null;index"`
+	Label      string     `json:"label,omitempty"`
+	ExpiresAt  time.Time  `json:"expires_at" gorm:"not null"`
+	RevokedAt  *time.Time `json:"revoked_at,omitempty"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+// IsActive reports whether the token is neither revoked nor expired at the given time
+func (t PortalToken) IsActive(at time.Time) bool {
+	return t.RevokedAt == nil && at.Before(t.ExpiresAt)
+}
+
+type CreatePortalTokenRequest struct {
+	Label    string `json:"label" binding:"max=100"`
+	TTLHours int    `json:"ttl_hours" binding:"omitempty,gt=0"` // Defaults to the configured token TTL
+}
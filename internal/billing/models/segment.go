@@ -0,0 +1,90 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ClientFilter selects clients; it backs both the GetClients query parameters and saved segments
+type ClientFilter struct {
+	Search       string            `json:"search,omitempty"`
+	Tags         []string          `json:"tags,omitempty"`          // Clients must carry every tag
+	CustomFields map[string]string `json:"custom_fields,omitempty"` // Custom field key to exact value
+}
+
+// IsEmpty reports whether the filter matches every client
+func (f ClientFilter) IsEmpty() bool {
+	return f.Search == "" && len(f.Tags) == 0 && len(f.CustomFields) == 0
+}
+
+// Normalize normalizes tags and checks custom field keys
+func (f *ClientFilter) Normalize() error {
+	tags, err := NormalizeTags(f.Tags)
+	if err != nil {
+		return err
+	}
+	f.Tags = tags
+	f.Search = strings.TrimSpace(f.Search)
+	for key := range f.CustomFields {
+		if !IsValidCustomFieldKey(key) {
+			return fmt.Errorf("invalid custom field key: %q", key)
+		}
+	}
+	return nil
+}
+
+// Merge narrows the filter with another one; its search and custom field values take precedence
+func (f ClientFilter) Merge(other ClientFilter) ClientFilter {
+	merged := ClientFilter{
+		Search:       f.Search,
+		Tags:         append(append([]string{}, f.Tags...), other.Tags...),
+		CustomFields: make(map[string]string, len(f.CustomFields)+len(other.CustomFields)),
+	}
+	if other.Search != "" {
+		merged.Search = other.Search
+	}
+	for key, value := range f.CustomFields {
+		merged.CustomFields[key] = value
+	}
+	for key, value := range other.CustomFields {
+		merged.CustomFields[key] = value
+	}
+	merged.Tags, _ = NormalizeTags(merged.Tags)
+	return merged
+}
+
+// Value implements driver.Valuer
+func (f ClientFilter) Value() (driver.Value, error) {
+	encoded, err := json.Marshal(f)
+	return string(encoded), err
+}
+
+// Scan implements sql.Scanner
+func (f *ClientFilter) Scan(src interface{}) error {
+	return scanJSON(src, f)
+}
+
+// ClientSegment is a saved, named client filter reused by listings, reports and bulk operations
+type ClientSegment struct {
+	ID          uint         `json:"id" gorm:"primaryKey"`
+	Name        string       `json:"name" gorm:"uniqueIndex;not null"`
+	Description string       `json:"description,omitempty"`
+	Filter      ClientFilter `json:"filter" gorm:"type:jsonb;not null"`
+	CreatedAt   time.Time    `json:"created_at"`
+	UpdatedAt   time.Time    `json:"updated_at"`
+}
+
+type CreateSegmentRequest struct {
+	Name        string       `json:"name" binding:"required,min=2,max=100"`
+	Description string       `json:"description" binding:"max=255"`
+	Filter      ClientFilter `json:"filter"`
+}
+
+type UpdateSegmentRequest struct {
+	Name        string        `json:"name" binding:"omitempty,min=2,max=100"`
+	Description string        `json:"description" binding:"max=255"`
+	Filter      *ClientFilter `json:"filter"`
+}
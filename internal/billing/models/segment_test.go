@@ -0,0 +1,58 @@
+package models
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalizeTags(t *testing.T) {
+	tags, err := NormalizeTags([]string{" Retail", "gold", "retail", "", "Enterprise"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"enterprise", "gold", "retail"}, tags)
+
+	_, err = NormalizeTags([]string{"this-tag-is-definitely-much-longer-than-fifty-characters"})
+	assert.Error(t, err)
+}
+
+func TestClientTag_JSON(t *testing.T) {
+	encoded, err := json.Marshal([]ClientTag{{ID: 1, ClientID: 2, Name: "retail"}})
+	require.NoError(t, err)
+	assert.JSONEq(t, `["retail"]`, string(encoded))
+
+	var tags []ClientTag
+	require.NoError(t, json.Unmarshal(encoded, &tags))
+	assert.Equal(t, "retail", tags[0].Name)
+}
+
+func TestClientFilter(t *testing.T) {
+	t.Run("normalize", func(t *testing.T) {
+		filter := ClientFilter{Search: " acme ", Tags: []string{"Gold"}, CustomFields: map[string]string{"industry": "retail"}}
+		require.NoError(t, filter.Normalize())
+		assert.Equal(t, "acme", filter.Search)
+		assert.Equal(t, []string{"gold"}, filter.Tags)
+
+		invalid := ClientFilter{CustomFields: map[string]string{"industry'; --": "x"}}
+		assert.Error(t, invalid.Normalize())
+	})
+
+	t.Run("merge", func(t *testing.T) {
+		segment := ClientFilter{Search: "acme", Tags: []string{"gold"}, CustomFields: map[string]string{"industry": "retail", "tier": "1"}}
+		query := ClientFilter{Tags: []string{"eu", "gold"}, CustomFields: map[string]string{"tier": "2"}}
+
+		merged := segment.Merge(query)
+		assert.Equal(t, "acme", merged.Search)
+		assert.Equal(t, []string{"eu", "gold"}, merged.Tags)
+		assert.Equal(t, map[string]string{"industry": "retail", "tier": "2"}, merged.CustomFields)
+
+		// The segment itself is left untouched
+		assert.Equal(t, map[string]string{"industry": "retail", "tier": "1"}, segment.CustomFields)
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		assert.True(t, ClientFilter{}.IsEmpty())
+		assert.False(t, ClientFilter{Tags: []string{"gold"}}.IsEmpty())
+	})
+}
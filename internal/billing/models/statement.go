@@ -0,0 +1,87 @@
+package models
+
+import (
+	"sort"
+	"time"
+)
+
+// StatementLine is one invoice on a client statement
+type StatementLine struct {
+	InvoiceID   uint          `json:"invoice_id"`
+	Number      string        `json:"number"`
+	IssueDate   time.Time     `json:"issue_date"`
+	DueDate     time.Time     `json:"due_date"`
+	Status      InvoiceStatus `json:"status"`
+	Total       float64       `json:"total"`
+	Outstanding float64       `json:"outstanding"`
+	Overdue     bool          `json:"overdue"`
+}
+
+// ClientStatement lists a client's invoices with the amounts invoiced, paid and still due.
+// Payments are not recorded individually yet, so an invoice is either fully paid
+// (status paid) or fully outstanding (status sent or overdue).
+type ClientStatement struct {
+	ClientID       uint            `json:"client_id"`
+	ClientName     string          `json:"client_name"`
+	Currency       string          `json:"currency"`
+	From           *time.Time      `json:"from,omitempty"`
+	To             *time.Time      `json:"to,omitempty"`
+	GeneratedAt    time.Time       `json:"generated_at"`
+	Lines          []StatementLine `json:"lines"`
+	TotalInvoiced  float64         `json:"total_invoiced"`
+	TotalPaid      float64         `json:"total_paid"`
+	Balance        float64         `json:"balance"`
+	OverdueBalance float64         `json:"overdue_balance"`
+}
+
+// BuildClientStatement builds a statement from the client's invoices. Drafts are not shown
+// to clients and cancelled invoices are listed without counting towards the totals.
+func BuildClientStatement(client Client, invoices []Invoice, currency string, now time.Time) ClientStatement {
+	statement := ClientStatement{
+		ClientID:    client.ID,
+		ClientName:  client.Name,
+		Currency:    currency,
+		GeneratedAt: now,
+		Lines:       []StatementLine{},
+	}
+
+	sorted := append([]Invoice(nil), invoices...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].IssueDate.Before(sorted[j].IssueDate)
+	})
+
+	for _, invoice := range sorted {
+		if invoice.Status == InvoiceStatusDraft {
+			continue
+		}
+		line := StatementLine{
+			InvoiceID: invoice.ID,
+			Number:    invoice.Number,
+			IssueDate: invoice.IssueDate,
+			DueDate:   invoice.DueDate,
+			Status:    invoice.Status,
+			Total:     invoice.Total,
+		}
+
+		switch invoice.Status {
+		case InvoiceStatusPaid:
+			statement.TotalInvoiced += invoice.Total
+			statement.TotalPaid += invoice.Total
+		case InvoiceStatusSent, InvoiceStatusOverdue:
+			statement.TotalInvoiced += invoice.Total
+			line.Outstanding = invoice.Total
+			line.Overdue = invoice.Status == InvoiceStatusOverdue || now.After(invoice.DueDate)
+			statement.Balance += invoice.Total
+			if line.Overdue {
+				statement.OverdueBalance += invoice.Total
+			}
+		}
+		statement.Lines = append(statement.Lines, line)
+	}
+
+	statement.TotalInvoiced = roundMoney(statement.TotalInvoiced)
+	statement.TotalPaid = roundMoney(statement.TotalPaid)
+	statement.Balance = roundMoney(statement.Balance)
+	statement.OverdueBalance = roundMoney(statement.OverdueBalance)
+	return statement
+}
@@ -0,0 +1,34 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildClientStatement(t *testing.T) {
+	now := time.Date(2024, 4, 15, 0, 0, 0, 0, time.UTC)
+	day := func(month time.Month, d int) time.Time { return time.Date(2024, month, d, 0, 0, 0, 0, time.UTC) }
+	client := Client{ID: 3, Name: "Acme SA"}
+	invoices := []Invoice{
+		{ID: 4, Number: "INV-4", Status: InvoiceStatusSent, Total: 200, IssueDate: day(4, 1), DueDate: day(5, 1)},
+		{ID: 1, Number: "INV-1", Status: InvoiceStatusPaid, Total: 100, IssueDate: day(1, 1), DueDate: day(2, 1)},
+		{ID: 2, Number: "INV-2", Status: InvoiceStatusSent, Total: 50.5, IssueDate: day(2, 1), DueDate: day(3, 1)},
+		{ID: 3, Number: "INV-3", Status: InvoiceStatusCancelled, Total: 75, IssueDate: day(3, 1), DueDate: day(4, 1)},
+		{ID: 5, Number: "INV-5", Status: InvoiceStatusDraft, Total: 999, IssueDate: day(4, 2), DueDate: day(5, 2)},
+	}
+
+	statement := BuildClientStatement(client, invoices, "EUR", now)
+
+	require.Len(t, statement.Lines, 4, "drafts are not shown")
+	assert.Equal(t, "INV-1", statement.Lines[0].Number, "lines are in issue date order")
+	assert.Equal(t, 350.5, statement.TotalInvoiced)
+	assert.Equal(t, 100.0, statement.TotalPaid)
+	assert.Equal(t, 250.5, statement.Balance)
+	assert.Equal(t, 50.5, statement.OverdueBalance)
+	assert.True(t, statement.Lines[1].Overdue)
+	assert.Zero(t, statement.Lines[2].Outstanding, "cancelled invoices are not due")
+	assert.Equal(t, "EUR", statement.Currency)
+}
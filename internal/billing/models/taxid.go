@@ -0,0 +1,364 @@
+package models
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+type TaxIDScheme string
+
+const (
+	TaxIDSchemeEUVAT TaxIDScheme = "eu_vat" // EU VAT number, prefixed with the member state code
+	TaxIDSchemeGBVAT TaxIDScheme = "gb_vat" // UK (GB) and Northern Ireland (XI) VAT number
+	TaxIDSchemeCHUID TaxIDScheme = "ch_uid" // Swiss enterprise identification number (CHE-...)
+	TaxIDSchemeNOVAT TaxIDScheme = "no_vat" // Norwegian organisation number registered for VAT
+	TaxIDSchemeUSEIN TaxIDScheme = "us_ein" // US employer identification number
+	TaxIDSchemeAUABN TaxIDScheme = "au_abn" // Australian business number
+)
+
+// IsValidTaxIDScheme checks if the tax ID scheme is supported
+func IsValidTaxIDScheme(scheme TaxIDScheme) bool {
+	switch scheme {
+	case TaxIDSchemeEUVAT, TaxIDSchemeGBVAT, TaxIDSchemeCHUID, TaxIDSchemeNOVAT, TaxIDSchemeUSEIN, TaxIDSchemeAUABN:
+		return true
+	default:
+		return false
+	}
+}
+
+// euVATFormats holds the number format (without country prefix) of each EU member state.
+// Greece uses the "EL" prefix for VAT purposes.
+var euVATFormats = map[string]*regexp.Regexp{
+	"AT": regexp.MustCompile(`^U\d{8}$`),
+	"BE": regexp.MustCompile(`^[01]\d{9}$`),
+	"BG": regexp.MustCompile(`^\d{9,10}$`),
+	"CY": regexp.MustCompile(`^\d{8}[A-Z]$`),
+	"CZ": regexp.MustCompile(`^\d{8,10}$`),
+	"DE": regexp.MustCompile(`^\d{9}$`),
+	"DK": regexp.MustCompile(`^\d{8}$`),
+	"EE": regexp.MustCompile(`^\d{9}$`),
+	"EL": regexp.MustCompile(`^\d{9}$`),
+	"ES": regexp.MustCompile(`^[A-Z0-9]\d{7}[A-Z0-9]$`),
+	"FI": regexp.MustCompile(`^\d{8}$`),
+	"FR": regexp.MustCompile(`^[A-HJ-NP-Z0-9]{2}\d{9}$`),
+	"HR": regexp.MustCompile(`^\d{11}$`),
+	"HU": regexp.MustCompile(`^\d{8}$`),
+	"IE": regexp.MustCompile(`^\d[A-Z0-9+*]\d{5}[A-Z]{1,2}$`),
+	"IT": regexp.MustCompile(`^\d{11}$`),
+	"LT": regexp.MustCompile(`^(\d{9}|\d{12})$`),
+	"LU": regexp.MustCompile(`^\d{8}$`),
+	"LV": regexp.MustCompile(`^\d{11}$`),
+	"MT": regexp.MustCompile(`^\d{8}$`),
+	"NL": regexp.MustCompile(`^\d{9}B\d{2}$`),
+	"PL": regexp.MustCompile(`^\d{10}$`),
+	"PT": regexp.MustCompile(`^\d{9}$`),
+	"RO": regexp.MustCompile(`^\d{2,10}$`),
+	"SE": regexp.MustCompile(`^\d{10}01$`),
+	"SI": regexp.MustCompile(`^\d{8}$`),
+	"SK": regexp.MustCompile(`^\d{10}$`),
+}
+
+// euVATChecksums holds check digit algorithms for the member states where they are published
+var euVATChecksums = map[string]func(string) bool{
+	"AT": checkATVAT,
+	"BE": checkBEVAT,
+	"DE": checkDEVAT,
+	"DK": checkDKVAT,
+	"FI": checkFIVAT,
+	"FR": checkFRVAT,
+	"IT": func(n string) bool { return luhnValid(n) },
+	"LU": checkLUVAT,
+	"NL": checkNLVAT,
+	"PL": checkPLVAT,
+	"PT": checkPTVAT,
+	"SE": func(n string) bool { return luhnValid(n[:10]) },
+}
+
+var (
+	gbVATFormat = regexp.MustCompile(`^(\d{9}|\d{12}|GD\d{3}|HA\d{3})$`)
+	chUIDFormat = regexp.MustCompile(`^\d{9}(MWST|TVA|IVA)?$`)
+	noVATFormat = regexp.MustCompile(`^\d{9}(MVA)?$`)
+	usEINFormat = regexp.MustCompile(`^\d{9}$`)
+	auABNFormat = regexp.MustCompile(`^\d{11}$`)
+)
+
+// NormalizeTaxID upper-cases the identifier and strips spaces, dots, dashes and slashes
+func NormalizeTaxID(taxID string) string {
+	return strings.Map(func(r rune) rune {
+		switch r {
+		case ' ', '.', '-', '/', '\t':
+			return -1
+		}
+		return r
+	}, strings.ToUpper(strings.TrimSpace(taxID)))
+}
+
+// DetectTaxIDScheme infers the scheme from the identifier prefix. Schemes without a
+// prefix (US EIN, AU ABN) cannot be detected and must be given explicitly.
+func DetectTaxIDScheme(taxID string) TaxIDScheme {
+	taxID = NormalizeTaxID(taxID)
+	switch {
+	case strings.HasPrefix(taxID, "CHE"):
+		return TaxIDSchemeCHUID
+	case strings.HasPrefix(taxID, "GB"), strings.HasPrefix(taxID, "XI"):
+		return TaxIDSchemeGBVAT
+	case strings.HasPrefix(taxID, "NO"):
+		return TaxIDSchemeNOVAT
+	case len(taxID) > 2 && euVATFormats[taxID[:2]] != nil:
+		return TaxIDSchemeEUVAT
+	}
+	return ""
+}
+
+// ValidateTaxID checks the format and, where the algorithm is published, the check digits
+// of a tax identifier. It returns the normalized identifier. This is an offline check only:
+// it cannot tell whether the number is actually registered.
+func ValidateTaxID(scheme TaxIDScheme, taxID string) (string, error) {
+	taxID = NormalizeTaxID(taxID)
+	if taxID == "" {
+		return "", fmt.Errorf("tax ID is required")
+	}
+	if scheme == "" {
+		scheme = DetectTaxIDScheme(taxID)
+		if scheme == "" {
+			return "", fmt.Errorf("cannot determine tax ID scheme for %q, specify tax_id_scheme", taxID)
+		}
+	}
+
+	switch scheme {
+	case TaxIDSchemeEUVAT:
+		return taxID, validateEUVAT(taxID)
+	case TaxIDSchemeGBVAT:
+		return taxID, validateGBVAT(taxID)
+	case TaxIDSchemeCHUID:
+		return taxID, validateCHUID(taxID)
+	case TaxIDSchemeNOVAT:
+		return taxID, validateNOVAT(taxID)
+	case TaxIDSchemeUSEIN:
+		if !usEINFormat.MatchString(taxID) || strings.HasPrefix(taxID, "00") {
+			return taxID, fmt.Errorf("invalid US EIN: must be 9 digits (XX-XXXXXXX)")
+		}
+		return taxID, nil
+	case TaxIDSchemeAUABN:
+		if !auABNFormat.MatchString(taxID) || !checkAUABN(taxID) {
+			return taxID, fmt.Errorf("invalid Australian ABN")
+		}
+		return taxID, nil
+	}
+	return taxID, fmt.Errorf("unsupported tax ID scheme: %s", scheme)
+}
+
+func validateEUVAT(taxID string) error {
+	if len(taxID) < 4 {
+		return fmt.Errorf("invalid EU VAT number %q", taxID)
+	}
+	country, number := taxID[:2], taxID[2:]
+	format, ok := euVATFormats[country]
+	if !ok {
+		return fmt.Errorf("unknown EU VAT country prefix %q", country)
+	}
+	if !format.MatchString(number) {
+		return fmt.Errorf("invalid %s VAT number format", country)
+	}
+	if check, ok := euVATChecksums[country]; ok && !check(number) {
+		return fmt.Errorf("invalid %s VAT number check digits", country)
+	}
+	return nil
+}
+
+func validateGBVAT(taxID string) error {
+	number := strings.TrimPrefix(strings.TrimPrefix(taxID, "GB"), "XI")
+	if !gbVATFormat.MatchString(number) {
+		return fmt.Errorf("invalid UK VAT number format")
+	}
+	// Government departments (GD) and health authorities (HA) have no check digits
+	if number[0] >= '0' && number[0] <= '9' && !checkGBVAT(number[:9]) {
+		return fmt.Errorf("invalid UK VAT number check digits")
+	}
+	return nil
+}
+
+func validateCHUID(taxID string) error {
+	number := strings.TrimPrefix(taxID, "CHE")
+	if !chUIDFormat.MatchString(number) {
+		return fmt.Errorf("invalid Swiss UID format")
+	}
+	if !checkMod11(number[:9], []int{5, 4, 3, 2, 7, 6, 5, 4}) {
+		return fmt.Errorf("invalid Swiss UID check digit")
+	}
+	return nil
+}
+
+func validateNOVAT(taxID string) error {
+	number := strings.TrimPrefix(taxID, "NO")
+	if !noVATFormat.MatchString(number) {
+		return fmt.Errorf("invalid Norwegian VAT number format")
+	}
+	if !checkMod11(number[:9], []int{3, 2, 7, 6, 5, 4, 3, 2}) {
+		return fmt.Errorf("invalid Norwegian VAT number check digit")
+	}
+	return nil
+}
+
+// digitsOf converts a string of ASCII digits into ints
+func digitsOf(s string) []int {
+	digits := make([]int, len(s))
+	for i, r := range s {
+		digits[i] = int(r - '0')
+	}
+	return digits
+}
+
+// weightedSum multiplies the leading digits by the given weights
+func weightedSum(digits []int, weights []int) int {
+	sum := 0
+	for i, w := range weights {
+		sum += digits[i] * w
+	}
+	return sum
+}
+
+// checkMod11 validates a trailing check digit computed as 11 - (weighted sum mod 11)
+func checkMod11(number string, weights []int) bool {
+	d := digitsOf(number)
+	check := 11 - weightedSum(d, weights)%11
+	if check == 11 {
+		check = 0
+	}
+	return check != 10 && check == d[len(weights)]
+}
+
+func luhnValid(number string) bool {
+	sum := 0
+	for i, d := range digitsOf(number) {
+		if (len(number)-i)%2 == 0 {
+			d *= 2
+			if d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+	}
+	return sum%10 == 0
+}
+
+func checkATVAT(number string) bool {
+	d := digitsOf(number[1:]) // skip the leading U
+	sum := 0
+	for i := 0; i < 7; i++ {
+		x := d[i]
+		if i%2 == 1 {
+			x *= 2
+			x = x/10 + x%10
+		}
+		sum += x
+	}
+	return (10-(sum+4)%10)%10 == d[7]
+}
+
+func checkBEVAT(number string) bool {
+	base, _ := strconv.Atoi(number[:8])
+	check, _ := strconv.Atoi(number[8:])
+	return 97-base%97 == check
+}
+
+// checkDEVAT implements ISO 7064 MOD 11,10
+func checkDEVAT(number string) bool {
+	d := digitsOf(number)
+	product := 10
+	for i := 0; i < 8; i++ {
+		sum := (d[i] + product) % 10
+		if sum == 0 {
+			sum = 10
+		}
+		product = (2 * sum) % 11
+	}
+	check := 11 - product
+	if check == 10 {
+		check = 0
+	}
+	return check == d[8]
+}
+
+func checkDKVAT(number string) bool {
+	return weightedSum(digitsOf(number), []int{2, 7, 6, 5, 4, 3, 2, 1})%11 == 0
+}
+
+func checkFIVAT(number string) bool {
+	d := digitsOf(number)
+	remainder := weightedSum(d, []int{7, 9, 10, 5, 8, 4, 2}) % 11
+	if remainder == 1 {
+		return false
+	}
+	check := 0
+	if remainder != 0 {
+		check = 11 - remainder
+	}
+	return check == d[7]
+}
+
+func checkFRVAT(number string) bool {
+	key, err := strconv.Atoi(number[:2])
+	if err != nil {
+		// Alphanumeric keys (new-style numbers) have no published algorithm
+		return true
+	}
+	siren, _ := strconv.Atoi(number[2:])
+	return key == (12+3*(siren%97))%97
+}
+
+func checkLUVAT(number string) bool {
+	base, _ := strconv.Atoi(number[:6])
+	check, _ := strconv.Atoi(number[6:])
+	return base%89 == check
+}
+
+// checkNLVAT accepts both the classic mod 11 numbers and the mod 97 numbers issued to sole traders since 2020
+func checkNLVAT(number string) bool {
+	d := digitsOf(number[:9])
+	if (weightedSum(d, []int{9, 8, 7, 6, 5, 4, 3, 2})-d[8])%11 == 0 {
+		return true
+	}
+	// ISO 7064 MOD 97-10 over "NL" + number with letters mapped to 10..35
+	var numeric strings.Builder
+	for _, r := range "NL" + number {
+		if r >= 'A' && r <= 'Z' {
+			numeric.WriteString(strconv.Itoa(int(r-'A') + 10))
+		} else {
+			numeric.WriteRune(r)
+		}
+	}
+	remainder := 0
+	for _, d := range digitsOf(numeric.String()) {
+		remainder = (remainder*10 + d) % 97
+	}
+	return remainder == 1
+}
+
+func checkPLVAT(number string) bool {
+	d := digitsOf(number)
+	return weightedSum(d, []int{6, 5, 7, 2, 3, 4, 5, 6, 7})%11 == d[9]
+}
+
+func checkPTVAT(number string) bool {
+	d := digitsOf(number)
+	check := 11 - weightedSum(d, []int{9, 8, 7, 6, 5, 4, 3, 2})%11
+	if check > 9 {
+		check = 0
+	}
+	return check == d[8]
+}
+
+func checkGBVAT(number string) bool {
+	d := digitsOf(number)
+	sum := weightedSum(d, []int{8, 7, 6, 5, 4, 3, 2}) + d[7]*10 + d[8]
+	return sum%97 == 0 || (sum+55)%97 == 0
+}
+
+func checkAUABN(number string) bool {
+	d := digitsOf(number)
+	d[0]--
+	return weightedSum(d, []int{10, 1, 3, 5, 7, 9, 11, 13, 15, 17, 19})%89 == 0
+}
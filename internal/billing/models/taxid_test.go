@@ -0,0 +1,76 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateTaxID(t *testing.T) {
+	valid := []struct {
+		scheme     TaxIDScheme
+		taxID      string
+		normalized string
+	}{
+		{"", "ATU13585627", "ATU13585627"},
+		{"", "BE 0403.019.261", "BE0403019261"},
+		{"", "DE136695976", "DE136695976"},
+		{"", "DK13585628", "DK13585628"},
+		{"", "FI20774740", "FI20774740"},
+		{"", "FR40303265045", "FR40303265045"},
+		{"", "IT00743110157", "IT00743110157"},
+		{"", "LU15027442", "LU15027442"},
+		{"", "NL004495445B01", "NL004495445B01"},
+		{"", "PL8567346215", "PL8567346215"},
+		{"", "PT501964843", "PT501964843"},
+		{"", "SE123456789701", "SE123456789701"},
+		{"", "EL094259216", "EL094259216"}, // Format only
+		{"", "GB980780684", "GB980780684"},
+		{"", "CHE-107.787.577 MWST", "CHE107787577MWST"},
+		{"", "NO 995 525 828 MVA", "NO995525828MVA"},
+		{TaxIDSchemeUSEIN, "12-3456789", "123456789"},
+		{TaxIDSchemeAUABN, "83 914 571 673", "83914571673"},
+	}
+	for _, tc := range valid {
+		t.Run("valid "+tc.taxID, func(t *testing.T) {
+			normalized, err := ValidateTaxID(tc.scheme, tc.taxID)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.normalized, normalized)
+		})
+	}
+
+	invalid := []struct {
+		scheme TaxIDScheme
+		taxID  string
+	}{
+		{"", ""},
+		{"", "ATU13585626"},   // Wrong check digit
+		{"", "BE0403019262"},  // Wrong check digit
+		{"", "DE136695977"},   // Wrong check digit
+		{"", "FR41303265045"}, // Wrong key
+		{"", "IT00743110158"}, // Luhn failure
+		{"", "NL004495445B0"}, // Wrong format
+		{"", "PL8567346216"},  // Wrong check digit
+		{"", "GB980780685"},   // Wrong check digits
+		{"", "CHE107787578"},  // Wrong check digit
+		{"", "XX123456789"},   // Unknown prefix
+		{"", "123456789"},     // Scheme cannot be detected
+		{TaxIDSchemeEUVAT, "US123456789"},
+		{TaxIDSchemeUSEIN, "00-1234567"},
+		{TaxIDSchemeAUABN, "83914571674"},
+	}
+	for _, tc := range invalid {
+		t.Run("invalid "+tc.taxID, func(t *testing.T) {
+			_, err := ValidateTaxID(tc.scheme, tc.taxID)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestDetectTaxIDScheme(t *testing.T) {
+	assert.Equal(t, TaxIDSchemeEUVAT, DetectTaxIDScheme("be0403019261"))
+	assert.Equal(t, TaxIDSchemeGBVAT, DetectTaxIDScheme("XI980780684"))
+	assert.Equal(t, TaxIDSchemeCHUID, DetectTaxIDScheme("CHE-107.787.577"))
+	assert.Equal(t, TaxIDSchemeNOVAT, DetectTaxIDScheme("NO995525828MVA"))
+	assert.Equal(t, TaxIDScheme(""), DetectTaxIDScheme("123456789"))
+}
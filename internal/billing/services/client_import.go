@@ -0,0 +1,457 @@
+package services
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"gaetanjaminon/GoTuto/internal/billing/models"
+)
+
+type ClientImportFormat string
+
+const (
+	ClientImportFormatCSV   ClientImportFormat = "csv"
+	ClientImportFormatVCard ClientImportFormat = "vcard"
+)
+
+// MaxClientImportRows caps the number of records accepted in a single import
+const MaxClientImportRows = 5000
+
+type ClientImportStatus string
+
+const (
+	ClientImportCreated  ClientImportStatus = "created"
+	ClientImportUpdated  ClientImportStatus = "updated"
+	ClientImportRejected ClientImportStatus = "rejected"
+)
+
+// ClientImportRow is one record read from an import file
+type ClientImportRow struct {
+	Row     int                        `json:"row"` // CSV line number or vCard position, starting at 1
+	Request models.CreateClientRequest `json:"request"`
+	Errors  []string                   `json:"errors,omitempty"` // Problems found while parsing the record
+}
+
+// ClientImportResult is the outcome of importing one record
+type ClientImportResult struct {
+	Row      int                `json:"row"`
+	Status   ClientImportStatus `json:"status"`
+	ClientID uint               `json:"client_id,omitempty"`
+	Name     string             `json:"name"`
+	Email    string             `json:"email"`
+	Errors   []string           `json:"errors,omitempty"`
+}
+
+// ClientImportReport summarizes an import; with DryRun nothing was written
+type ClientImportReport struct {
+	DryRun   bool                 `json:"dry_run"`
+	Created  int                  `json:"created"`
+	Updated  int                  `json:"updated"`
+	Rejected int                  `json:"rejected"`
+	Results  []ClientImportResult `json:"results"`
+}
+
+// Add records a result and updates the counters
+func (r *ClientImportReport) Add(result ClientImportResult) {
+	switch result.Status {
+	case ClientImportCreated:
+		r.Created++
+	case ClientImportUpdated:
+		r.Updated++
+	default:
+		r.Rejected++
+	}
+	r.Results = append(r.Results, result)
+}
+
+// ClientImportFields lists the client fields a CSV column can be mapped to
+var ClientImportFields = []string{
+	"name", "email", "phone", "address", "tax_id", "tax_id_scheme", "payment_term_id",
+	"billing_street", "billing_street2", "billing_city", "billing_postal_code", "billing_region", "billing_country",
+	"shipping_street", "shipping_street2", "shipping_city", "shipping_postal_code", "shipping_region", "shipping_country",
+}
+
+// ParseClientCSV reads clients from a CSV file with a header row. The mapping maps client
+// fields (see ClientImportFields) to column headers; unmapped fields are read from a
+// column named like the field. Headers match case-insensitively and the delimiter
+// (comma or semicolon) is detected from the header line.
+func ParseClientCSV(r io.Reader, mapping map[string]string) ([]ClientImportRow, error) {
+	buffered := bufio.NewReader(r)
+	headerLine, err := buffered.Peek(4096)
+	if err != nil && !errors.Is(err, io.EOF) {
+		return nil, err
+	}
+
+	reader := csv.NewReader(buffered)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+	if line, _, _ := bytes.Cut(headerLine, []byte("\n")); bytes.Count(line, []byte(";")) > bytes.Count(line, []byte(",")) {
+		reader.Comma = ';'
+	}
+
+	header, err := reader.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, errors.New("CSV file is empty")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid CSV header: %w", err)
+	}
+
+	columns, err := mapClientColumns(header, mapping)
+	if err != nil {
+		return nil, err
+	}
+
+	var rows []ClientImportRow
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid CSV: %w", err)
+		}
+		if isBlankRecord(record) {
+			continue
+		}
+		if len(rows) == MaxClientImportRows {
+			return nil, fmt.Errorf("import is limited to %d records", MaxClientImportRows)
+		}
+
+		line, _ := reader.FieldPos(0)
+		row := ClientImportRow{Row: line}
+		for field, index := range columns {
+			if index >= len(record) {
+				continue
+			}
+			if err := setClientImportField(&row.Request, field, record[index]); err != nil {
+				row.Errors = append(row.Errors, err.Error())
+			}
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+// mapClientColumns resolves the column index of each client field
+func mapClientColumns(header []string, mapping map[string]string) (map[string]int, error) {
+	indexes := make(map[string]int, len(header))
+	for i, name := range header {
+		name = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))
+		if _, exists := indexes[name]; !exists {
+			indexes[name] = i
+		}
+	}
+
+	known := make(map[string]bool, len(ClientImportFields))
+	for _, field := range ClientImportFields {
+		known[field] = true
+	}
+
+	columns := make(map[string]int)
+	for field, column := range mapping {
+		if !known[field] {
+			return nil, fmt.Errorf("unknown client field in mapping: %q", field)
+		}
+		index, ok := indexes[strings.ToLower(strings.TrimSpace(column))]
+		if !ok {
+			return nil, fmt.Errorf("column %q mapped to %s not found in CSV header", column, field)
+		}
+		columns[field] = index
+	}
+	for _, field := range ClientImportFields {
+		if _, mapped := mapping[field]; mapped {
+			continue
+		}
+		if index, ok := indexes[field]; ok {
+			columns[field] = index
+		}
+	}
+
+	for _, required := range []string{"name", "email"} {
+		if _, ok := columns[required]; !ok {
+			return nil, fmt.Errorf("no column for required field %q", required)
+		}
+	}
+	return columns, nil
+}
+
+func isBlankRecord(record []string) bool {
+	for _, value := range record {
+		if strings.TrimSpace(value) != "" {
+			return false
+		}
+	}
+	return true
+}
+
+// setClientImportField assigns one CSV value to the matching request field
+func setClientImportField(req *models.CreateClientRequest, field, value string) error {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return nil
+	}
+
+	switch field {
+	case "name":
+		req.Name = value
+	case "email":
+		req.Email = value
+	case "phone":
+		req.Phone = value
+	case "address":
+		req.Address = value
+	case "tax_id":
+		req.TaxID = value
+	case "tax_id_scheme":
+		req.TaxIDScheme = models.TaxIDScheme(strings.ToLower(value))
+	case "payment_term_id":
+		id, err := strconv.ParseUint(value, 10, 32)
+		if err != nil {
+			return fmt.Errorf("payment_term_id: %q is not a valid ID", value)
+		}
+		termID := uint(id)
+		req.PaymentTermID = &termID
+	default:
+		addressType, part, _ := strings.Cut(field, "_")
+		address := &req.BillingAddress
+		if addressType == string(models.AddressTypeShipping) {
+			address = &req.ShippingAddress
+		}
+		if *address == nil {
+			*address = &models.AddressRequest{}
+		}
+		switch part {
+		case "street":
+			(*address).Street = value
+		case "street2":
+			(*address).Street2 = value
+		case "city":
+			(*address).City = value
+		case "postal_code":
+			(*address).PostalCode = value
+		case "region":
+			(*address).Region = value
+		case "country":
+			(*address).Country = strings.ToUpper(value)
+		}
+	}
+	return nil
+}
+
+// ParseClientVCards reads clients from a vCard (3.0 or 4.0) file. The organization (ORG)
+// becomes the client name, falling back to the formatted name (FN); when both are present
+// the person is added as the primary billing contact. The first work address (ADR), or
+// else the first address, becomes the billing address when its country is an ISO code
+// and the free-text address otherwise.
+func ParseClientVCards(r io.Reader) ([]ClientImportRow, error) {
+	lines, err := unfoldVCardLines(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var rows []ClientImportRow
+	var card *vCard
+	for _, line := range lines {
+		name, params, value := parseVCardLine(line)
+		switch {
+		case name == "BEGIN" && strings.EqualFold(value, "VCARD"):
+			card = &vCard{}
+		case card == nil:
+			continue
+		case name == "END" && strings.EqualFold(value, "VCARD"):
+			if len(rows) == MaxClientImportRows {
+				return nil, fmt.Errorf("import is limited to %d records", MaxClientImportRows)
+			}
+			rows = append(rows, card.toImportRow(len(rows)+1))
+			card = nil
+		default:
+			card.add(name, params, value)
+		}
+	}
+	if card != nil {
+		return nil, errors.New("vCard file ends inside a card (missing END:VCARD)")
+	}
+	if len(rows) == 0 {
+		return nil, errors.New("no vCard found")
+	}
+	return rows, nil
+}
+
+// vCard collects the properties of one card that map to a client
+type vCard struct {
+	formattedName string
+	name          []string
+	organization  string
+	email         string
+	phone         string
+	address       []string
+	addressIsWork bool
+}
+
+func (v *vCard) add(name string, params map[string]string, value string) {
+	switch name {
+	case "FN":
+		v.formattedName = unescapeVCard(value)
+	case "N":
+		v.name = splitVCardComponents(value)
+	case "ORG":
+		v.organization = splitVCardComponents(value)[0]
+	case "EMAIL":
+		if v.email == "" {
+			v.email = unescapeVCard(value)
+		}
+	case "TEL":
+		if v.phone == "" {
+			v.phone = strings.TrimPrefix(unescapeVCard(value), "tel:")
+		}
+	case "ADR":
+		isWork := strings.Contains(strings.ToLower(params["TYPE"]), "work")
+		if v.address == nil || (isWork && !v.addressIsWork) {
+			v.address = splitVCardComponents(value)
+			v.addressIsWork = isWork
+		}
+	}
+}
+
+func (v *vCard) toImportRow(position int) ClientImportRow {
+	row := ClientImportRow{Row: position}
+	req := &row.Request
+
+	person := strings.TrimSpace(v.formattedName)
+	if person == "" && len(v.name) > 1 {
+		// N is Family;Given;Additional;Prefix;Suffix
+		person = strings.TrimSpace(v.name[1] + " " + v.name[0])
+	}
+
+	req.Name = strings.TrimSpace(v.organization)
+	if req.Name == "" {
+		req.Name = person
+	} else if person != "" && v.email != "" {
+		req.Contacts = append(req.Contacts, models.ContactRequest{
+			Name:      person,
+			Email:     strings.TrimSpace(v.email),
+			Phone:     strings.TrimSpace(v.phone),
+			Role:      models.ContactRoleBilling,
+			IsPrimary: true,
+		})
+	}
+	req.Email = strings.TrimSpace(v.email)
+	req.Phone = strings.TrimSpace(v.phone)
+
+	if len(v.address) > 0 {
+		// ADR is PO box;Extended;Street;Locality;Region;Postal code;Country
+		parts := make([]string, 7)
+		copy(parts, v.address)
+		for i := range parts {
+			parts[i] = strings.TrimSpace(parts[i])
+		}
+		country := strings.ToUpper(parts[6])
+		if parts[2] != "" && models.IsValidCountryCode(country) {
+			req.BillingAddress = &models.AddressRequest{
+				Street:     parts[2],
+				Street2:    strings.TrimSpace(parts[1] + " " + parts[0]),
+				City:       parts[3],
+				PostalCode: parts[5],
+				Region:     parts[4],
+				Country:    country,
+			}
+		} else {
+			var text []string
+			for _, part := range []string{parts[0], parts[1], parts[2], strings.TrimSpace(parts[5] + " " + parts[3]), parts[4], parts[6]} {
+				if part != "" {
+					text = append(text, part)
+				}
+			}
+			req.Address = strings.Join(text, ", ")
+		}
+	}
+
+	if req.Name == "" {
+		row.Errors = append(row.Errors, "card has no FN, N or ORG")
+	}
+	if req.Email == "" {
+		row.Errors = append(row.Errors, "card has no EMAIL")
+	}
+	return row
+}
+
+// unfoldVCardLines splits the input into logical lines, joining folded continuation lines
+func unfoldVCardLines(r io.Reader) ([]string, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+
+	var lines []string
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if (strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")) && len(lines) > 0 {
+			lines[len(lines)-1] += line[1:]
+			continue
+		}
+		if line != "" {
+			lines = append(lines, strings.TrimPrefix(line, "\ufeff"))
+		}
+	}
+	return lines, scanner.Err()
+}
+
+// parseVCardLine splits "group.NAME;PARAM=x:value" into its upper-cased name, parameters and value
+func parseVCardLine(line string) (string, map[string]string, string) {
+	head, value, _ := strings.Cut(line, ":")
+	segments := strings.Split(head, ";")
+
+	name := strings.ToUpper(segments[0])
+	if dot := strings.LastIndex(name, "."); dot >= 0 {
+		name = name[dot+1:]
+	}
+
+	params := make(map[string]string)
+	for _, segment := range segments[1:] {
+		key, paramValue, found := strings.Cut(segment, "=")
+		if !found {
+			// vCard 2.1 style bare type, e.g. TEL;WORK
+			key, paramValue = "TYPE", segment
+		}
+		key = strings.ToUpper(key)
+		if params[key] != "" {
+			paramValue = params[key] + "," + paramValue
+		}
+		params[key] = strings.Trim(paramValue, `"`)
+	}
+	return name, params, value
+}
+
+// splitVCardComponents splits a structured value on unescaped semicolons
+func splitVCardComponents(value string) []string {
+	var components []string
+	var current strings.Builder
+	escaped := false
+	for _, r := range value {
+		switch {
+		case escaped:
+			current.WriteRune('\\')
+			current.WriteRune(r)
+			escaped = false
+		case r == '\\':
+			escaped = true
+		case r == ';':
+			components = append(components, unescapeVCard(current.String()))
+			current.Reset()
+		default:
+			current.WriteRune(r)
+		}
+	}
+	return append(components, unescapeVCard(current.String()))
+}
+
+var vCardUnescaper = strings.NewReplacer(`\n`, "\n", `\N`, "\n", `\,`, ",", `\;`, ";", `\\`, `\`)
+
+func unescapeVCard(value string) string {
+	return vCardUnescaper.Replace(value)
+}
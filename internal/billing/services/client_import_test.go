@@ -0,0 +1,135 @@
+package services
+
+import (
+	"strings"
+	"testing"
+
+	"gaetanjaminon/GoTuto/internal/billing/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseClientCSV(t *testing.T) {
+	t.Run("columns named like fields", func(t *testing.T) {
+		input := "name,email,phone,billing_street,billing_city,billing_postal_code,billing_country\n" +
+			"Acme Corp,billing@acme.test,+32 2 555 12 34,Rue Neuve 1,Brussels,1000,be\n" +
+			"\n" +
+			"Globex,info@globex.test,,,,,\n"
+
+		rows, err := ParseClientCSV(strings.NewReader(input), nil)
+		require.NoError(t, err)
+		require.Len(t, rows, 2)
+
+		assert.Equal(t, 2, rows[0].Row)
+		assert.Equal(t, "Acme Corp", rows[0].Request.Name)
+		assert.Equal(t, "billing@acme.test", rows[0].Request.Email)
+		require.NotNil(t, rows[0].Request.BillingAddress)
+		assert.Equal(t, "BE", rows[0].Request.BillingAddress.Country)
+		assert.Nil(t, rows[0].Request.ShippingAddress)
+
+		assert.Equal(t, 4, rows[1].Row)
+		assert.Nil(t, rows[1].Request.BillingAddress)
+	})
+
+	t.Run("column mapping and semicolon delimiter", func(t *testing.T) {
+		input := "\ufeffCompany;E-mail;Payment Terms\nAcme Corp;billing@acme.test;3\nGlobex;info@globex.test;net30\n"
+
+		rows, err := ParseClientCSV(strings.NewReader(input), map[string]string{
+			"name":            "company",
+			"email":           "E-MAIL",
+			"payment_term_id": "Payment Terms",
+		})
+		require.NoError(t, err)
+		require.Len(t, rows, 2)
+
+		assert.Equal(t, "Acme Corp", rows[0].Request.Name)
+		require.NotNil(t, rows[0].Request.PaymentTermID)
+		assert.Equal(t, uint(3), *rows[0].Request.PaymentTermID)
+		assert.Empty(t, rows[0].Errors)
+		assert.NotEmpty(t, rows[1].Errors)
+	})
+
+	t.Run("invalid mappings", func(t *testing.T) {
+		_, err := ParseClientCSV(strings.NewReader("name,email\n"), map[string]string{"nickname": "name"})
+		assert.Error(t, err)
+
+		_, err = ParseClientCSV(strings.NewReader("name,email\n"), map[string]string{"phone": "Mobile"})
+		assert.Error(t, err)
+
+		_, err = ParseClientCSV(strings.NewReader("company,phone\n"), nil)
+		assert.Error(t, err)
+
+		_, err = ParseClientCSV(strings.NewReader(""), nil)
+		assert.Error(t, err)
+	})
+}
+
+func TestParseClientVCards(t *testing.T) {
+	input := strings.Join([]string{
+		"BEGIN:VCARD",
+		"VERSION:3.0",
+		"FN:Jane Doe",
+		"N:Doe;Jane;;;",
+		"ORG:Acme\\, Inc.;Sales",
+		"EMAIL;TYPE=work:jane@acme.test",
+		"TEL;TYPE=work,voice:+32 2 555 12 34",
+		"ADR;TYPE=home:;;Home Street 2;Ghent;;9000;BE",
+		"item1.ADR;TYPE=work:;Floor 3;Rue de la Loi 16;Brus",
+		" sels;;1000;BE",
+		"END:VCARD",
+		"BEGIN:VCARD",
+		"VERSION:4.0",
+		"N:Smith;John;;;",
+		"EMAIL:john@smith.test",
+		"ADR:;;1 Main Street;Springfield;IL;62701;United States",
+		"END:VCARD",
+		"BEGIN:VCARD",
+		"FN:No Email",
+		"END:VCARD",
+	}, "\r\n")
+
+	rows, err := ParseClientVCards(strings.NewReader(input))
+	require.NoError(t, err)
+	require.Len(t, rows, 3)
+
+	acme := rows[0].Request
+	assert.Equal(t, "Acme, Inc.", acme.Name)
+	assert.Equal(t, "jane@acme.test", acme.Email)
+	assert.Equal(t, "+32 2 555 12 34", acme.Phone)
+	require.NotNil(t, acme.BillingAddress)
+	assert.Equal(t, "Rue de la Loi 16", acme.BillingAddress.Street)
+	assert.Equal(t, "Brussels", acme.BillingAddress.City)
+	assert.Equal(t, "Floor 3", acme.BillingAddress.Street2)
+	require.Len(t, acme.Contacts, 1)
+	assert.Equal(t, "Jane Doe", acme.Contacts[0].Name)
+	assert.Equal(t, models.ContactRoleBilling, acme.Contacts[0].Role)
+	assert.Empty(t, rows[0].Errors)
+
+	smith := rows[1].Request
+	assert.Equal(t, "John Smith", smith.Name)
+	assert.Nil(t, smith.BillingAddress)
+	assert.Equal(t, "1 Main Street, 62701 Springfield, IL, United States", smith.Address)
+	assert.Empty(t, smith.Contacts)
+
+	assert.Equal(t, 3, rows[2].Row)
+	assert.NotEmpty(t, rows[2].Errors)
+
+	_, err = ParseClientVCards(strings.NewReader("BEGIN:VCARD\nFN:Open\n"))
+	assert.Error(t, err)
+
+	_, err = ParseClientVCards(strings.NewReader("not a vcard"))
+	assert.Error(t, err)
+}
+
+func TestClientImportReport_Add(t *testing.T) {
+	var report ClientImportReport
+	report.Add(ClientImportResult{Status: ClientImportCreated})
+	report.Add(ClientImportResult{Status: ClientImportUpdated})
+	report.Add(ClientImportResult{Status: ClientImportRejected})
+	report.Add(ClientImportResult{Status: ClientImportRejected})
+
+	assert.Equal(t, 1, report.Created)
+	assert.Equal(t, 1, report.Updated)
+	assert.Equal(t, 2, report.Rejected)
+	assert.Len(t, report.Results, 4)
+}
@@ -0,0 +1,188 @@
+package services
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+
+	"gaetanjaminon/GoTuto/internal/billing/models"
+)
+
+// DefaultNameSimilarityThreshold is the minimum name similarity (0..1) reported as a likely duplicate
+const DefaultNameSimilarityThreshold = 0.85
+
+// DuplicateMatch is a pair of clients that likely represent the same customer
+type DuplicateMatch struct {
+	ClientID    uint     `json:"client_id"`
+	DuplicateID uint     `json:"duplicate_id"`
+	Score       float64  `json:"score"`
+	Reasons     []string `json:"reasons"`
+}
+
+// legalSuffixes are company-form words ignored when comparing names
+var legalSuffixes = map[string]bool{
+	"inc": true, "incorporated": true, "corp": true, "corporation": true, "co": true, "company": true,
+	"llc": true, "ltd": true, "limited": true, "plc": true, "llp": true,
+	"sa": true, "sas": true, "sarl": true, "srl": true, "sprl": true, "bv": true, "nv": true,
+	"gmbh": true, "ag": true, "kg": true, "spa": true, "oy": true, "ab": true, "as": true,
+}
+
+// NormalizeEmail lower-cases the address and drops "+tag" sub-addressing from the local part
+func NormalizeEmail(email string) string {
+	email = strings.ToLower(strings.TrimSpace(email))
+	at := strings.LastIndex(email, "@")
+	if at < 0 {
+		return email
+	}
+	local, domain := email[:at], email[at+1:]
+	if plus := strings.Index(local, "+"); plus > 0 {
+		local = local[:plus]
+	}
+	return local + "@" + domain
+}
+
+// NormalizePhone keeps only digits, turning a leading "00" international prefix into "+"
+func NormalizePhone(phone string) string {
+	phone = strings.TrimSpace(phone)
+	international := strings.HasPrefix(phone, "+")
+
+	var digits strings.Builder
+	for _, r := range phone {
+		if r >= '0' && r <= '9' {
+			digits.WriteRune(r)
+		}
+	}
+	normalized := digits.String()
+	if strings.HasPrefix(normalized, "00") {
+		normalized = normalized[2:]
+		international = true
+	}
+	if international && normalized != "" {
+		return "+" + normalized
+	}
+	return normalized
+}
+
+// NormalizeName lower-cases the name, strips punctuation and legal-form suffixes
+func NormalizeName(name string) string {
+	cleaned := strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return unicode.ToLower(r)
+		}
+		return ' '
+	}, name)
+
+	var words []string
+	for _, word := range strings.Fields(cleaned) {
+		if !legalSuffixes[word] {
+			words = append(words, word)
+		}
+	}
+	return strings.Join(words, " ")
+}
+
+// NameSimilarity returns a 0..1 similarity of two client names based on edit distance
+func NameSimilarity(a, b string) float64 {
+	a, b = NormalizeName(a), NormalizeName(b)
+	if a == "" || b == "" {
+		return 0
+	}
+	if a == b {
+		return 1
+	}
+
+	ra, rb := []rune(a), []rune(b)
+	longest := len(ra)
+	if len(rb) > longest {
+		longest = len(rb)
+	}
+	return 1 - float64(levenshtein(ra, rb))/float64(longest)
+}
+
+// FindDuplicates compares clients pairwise and returns likely duplicates, best matches first.
+// Clients sharing a normalized email or phone number always match; otherwise names must be
+// at least threshold similar.
+func FindDuplicates(clients []models.Client, threshold float64) []DuplicateMatch {
+	if threshold <= 0 {
+		threshold = DefaultNameSimilarityThreshold
+	}
+
+	type candidate struct {
+		id    uint
+		name  string
+		email string
+		phone string
+	}
+	candidates := make([]candidate, len(clients))
+	for i, client := range clients {
+		candidates[i] = candidate{
+			id:    client.ID,
+			name:  client.Name,
+			email: NormalizeEmail(client.Email),
+			phone: NormalizePhone(client.Phone),
+		}
+	}
+
+	var matches []DuplicateMatch
+	for i := 0; i < len(candidates); i++ {
+		for j := i + 1; j < len(candidates); j++ {
+			a, b := candidates[i], candidates[j]
+			var score float64
+			var reasons []string
+
+			if a.email != "" && a.email == b.email {
+				score = 1
+				reasons = append(reasons, "email")
+			}
+			// Short numbers (extensions, placeholders) are too ambiguous to compare
+			if len(a.phone) >= 7 && a.phone == b.phone {
+				if score < 0.95 {
+					score = 0.95
+				}
+				reasons = append(reasons, "phone")
+			}
+			if similarity := NameSimilarity(a.name, b.name); similarity >= threshold {
+				if similarity > score {
+					score = similarity
+				}
+				reasons = append(reasons, "name")
+			}
+
+			if len(reasons) > 0 {
+				matches = append(matches, DuplicateMatch{
+					ClientID:    a.id,
+					DuplicateID: b.id,
+					Score:       float64(int(score*1000)) / 1000,
+					Reasons:     reasons,
+				})
+			}
+		}
+	}
+
+	sort.SliceStable(matches, func(i, j int) bool {
+		return matches[i].Score > matches[j].Score
+	})
+	return matches
+}
+
+// levenshtein computes the edit distance between two rune slices
+func levenshtein(a, b []rune) int {
+	previous := make([]int, len(b)+1)
+	current := make([]int, len(b)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+
+	for i := 1; i <= len(a); i++ {
+		current[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous, current = current, previous
+	}
+	return previous[len(b)]
+}
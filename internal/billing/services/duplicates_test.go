@@ -0,0 +1,53 @@
+package services
+
+import (
+	"testing"
+
+	"gaetanjaminon/GoTuto/internal/billing/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalizers(t *testing.T) {
+	assert.Equal(t, "john@example.com", NormalizeEmail("  John+Billing@Example.COM "))
+	assert.Equal(t, "+3225551234", NormalizePhone("0032 (2) 555-12-34"))
+	assert.Equal(t, "+3225551234", NormalizePhone("+32 2 555 12 34"))
+	assert.Equal(t, "025551234", NormalizePhone("02/555.12.34"))
+	assert.Equal(t, "acme widgets", NormalizeName("ACME Widgets, Inc."))
+}
+
+func TestNameSimilarity(t *testing.T) {
+	assert.Equal(t, 1.0, NameSimilarity("Acme Corp", "ACME corporation"))
+	assert.Greater(t, NameSimilarity("Acme Widgets", "Acme Widgetz"), DefaultNameSimilarityThreshold)
+	assert.Less(t, NameSimilarity("Acme Widgets", "Globex"), 0.5)
+	assert.Equal(t, 0.0, NameSimilarity("", "Globex"))
+}
+
+func TestFindDuplicates(t *testing.T) {
+	clients := []models.Client{
+		{ID: 1, Name: "Acme Widgets Ltd", Email: "billing@acme.test", Phone: "+32 2 555 12 34"},
+		{ID: 2, Name: "Globex", Email: "Billing+2024@acme.test"},
+		{ID: 3, Name: "Initech", Email: "info@initech.test", Phone: "0032 2 555 12 34"},
+		{ID: 4, Name: "ACME Widgets", Email: "hello@acme-widgets.test"},
+		{ID: 5, Name: "Umbrella", Email: "contact@umbrella.test", Phone: "123"},
+		{ID: 6, Name: "Hooli", Email: "contact@hooli.test", Phone: "123"},
+	}
+
+	matches := FindDuplicates(clients, 0)
+	require.Len(t, matches, 3)
+
+	byPair := map[[2]uint]DuplicateMatch{}
+	for _, match := range matches {
+		byPair[[2]uint{match.ClientID, match.DuplicateID}] = match
+	}
+
+	assert.Equal(t, []string{"email"}, byPair[[2]uint{1, 2}].Reasons)
+	assert.Equal(t, []string{"phone"}, byPair[[2]uint{1, 3}].Reasons)
+	assert.Equal(t, []string{"name"}, byPair[[2]uint{1, 4}].Reasons)
+	assert.Equal(t, 1.0, byPair[[2]uint{1, 4}].Score)
+
+	// Best matches come first
+	for i := 1; i < len(matches); i++ {
+		assert.GreaterOrEqual(t, matches[i-1].Score, matches[i].Score)
+	}
+}
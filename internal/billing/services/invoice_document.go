@@ -0,0 +1,108 @@
+package services
+
+import (
+	"strings"
+
+	"gaetanjaminon/GoTuto/internal/billing/models"
+)
+
+// InvoiceParty is the seller or the buyer printed on an invoice document
+type InvoiceParty struct {
+	Name       string
+	Email      string
+	TaxID      string
+	Street     string
+	City       string
+	PostalCode string
+	Country    string // ISO 3166-1 alpha-2
+	Address    string // Free-text address, used when no structured address is known
+}
+
+// AddressLines returns the postal address as printable lines
+func (p InvoiceParty) AddressLines() []string {
+	var lines []string
+	if p.Street != "" {
+		lines = append(lines, p.Street)
+	}
+	if cityLine := strings.TrimSpace(p.PostalCode + " " + p.City); cityLine != "" {
+		lines = append(lines, cityLine)
+	}
+	if p.Country != "" {
+		lines = append(lines, p.Country)
+	}
+	if len(lines) == 0 && p.Address != "" {
+		lines = append(lines, p.Address)
+	}
+	return lines
+}
+
+// InvoiceDocument holds everything needed to render an invoice as PDF or UBL
+type InvoiceDocument struct {
+	Invoice  models.Invoice
+	Issuer   InvoiceParty
+	Customer InvoiceParty
+	Currency string
+}
+
+// NewInvoiceDocument prepares an invoice for rendering. The invoice's Client, with its
+// Addresses, should be loaded; the billing address is preferred over the legacy address.
+func NewInvoiceDocument(invoice models.Invoice, issuer InvoiceParty, currency string) InvoiceDocument {
+	client := invoice.Client
+	customer := InvoiceParty{
+		Name:    client.Name,
+		Email:   client.Email,
+		TaxID:   client.TaxID,
+		Address: client.Address,
+	}
+	if address := client.AddressOfType(models.AddressTypeBilling); address != nil {
+		customer.Street = strings.TrimSpace(address.Street + " " + address.Street2)
+		customer.City = address.City
+		customer.PostalCode = address.PostalCode
+		customer.Country = address.Country
+	}
+
+	return InvoiceDocument{
+		Invoice:  invoice,
+		Issuer:   issuer,
+		Customer: customer,
+		Currency: currency,
+	}
+}
+
+// Lines returns the invoice lines; an invoice without lines is rendered as a single
+// line for its amount
+func (d InvoiceDocument) Lines() []models.InvoiceLine {
+	if len(d.Invoice.Lines) > 0 {
+		return d.Invoice.Lines
+	}
+	description := d.Invoice.Description
+	if description == "" {
+		description = "Invoice " + d.Invoice.Number
+	}
+	return []models.InvoiceLine{{
+		Description: description,
+		Quantity:    1,
+		UnitPrice:   d.Invoice.Amount,
+		Total:       d.Invoice.Amount,
+	}}
+}
+
+// LineDiscountTotal returns the sum of the line-level discounts
+func (d InvoiceDocument) LineDiscountTotal() float64 {
+	var total float64
+	for _, line := range d.Invoice.Lines {
+		total += line.DiscountAmount
+	}
+	return total
+}
+
+// FileName returns the base name used for downloads of this invoice
+func (d InvoiceDocument) FileName() string {
+	name := strings.Map(func(r rune) rune {
+		if r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '-' || r == '_' {
+			return r
+		}
+		return '_'
+	}, d.Invoice.Number)
+	return "invoice-" + name
+}
@@ -0,0 +1,125 @@
+package services
+
+import (
+	"bytes"
+	"encoding/xml"
+	"regexp"
+	"strconv"
+	"testing"
+	"time"
+
+	"gaetanjaminon/GoTuto/internal/billing/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testInvoiceDocument() InvoiceDocument {
+	invoice := models.Invoice{
+		ID:        1,
+		Number:    "INV-2024/001",
+		IssueDate: time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC),
+		DueDate:   time.Date(2024, 3, 31, 0, 0, 0, 0, time.UTC),
+		Discount:  models.Discount{Type: models.DiscountTypePercentage, Value: 10},
+		Lines: []models.InvoiceLine{
+			{Description: "Consulting (March)", Quantity: 10, UnitPrice: 100},
+			{Description: "Café & croissants", Quantity: 2, UnitPrice: 50, Discount: models.Discount{Type: models.DiscountTypeFixed, Value: 20}},
+		},
+		Client: models.Client{
+			Name:  "Acme SA",
+			Email: "billing@acme.test",
+			TaxID: "BE0403019261",
+			Addresses: []models.ClientAddress{
+				{Type: models.AddressTypeBilling, Street: "Rue de la Loi 1", City: "Brussels", PostalCode: "1000", Country: "BE"},
+			},
+		},
+	}
+	invoice.CalculateTotals()
+
+	issuer := InvoiceParty{Name: "GoTuto Billing", Country: "FR", TaxID: "FR40303265045"}
+	return NewInvoiceDocument(invoice, issuer, "EUR")
+}
+
+func TestNewInvoiceDocument(t *testing.T) {
+	doc := testInvoiceDocument()
+	assert.Equal(t, "Acme SA", doc.Customer.Name)
+	assert.Equal(t, []string{"Rue de la Loi 1", "1000 Brussels", "BE"}, doc.Customer.AddressLines())
+	assert.Equal(t, "invoice-INV-2024_001", doc.FileName())
+	assert.Equal(t, 20.0, doc.LineDiscountTotal())
+
+	t.Run("invoice without lines", func(t *testing.T) {
+		doc := NewInvoiceDocument(models.Invoice{Number: "INV-2", Amount: 250}, InvoiceParty{}, "EUR")
+		lines := doc.Lines()
+		require.Len(t, lines, 1)
+		assert.Equal(t, "Invoice INV-2", lines[0].Description)
+		assert.Equal(t, 250.0, lines[0].Total)
+	})
+}
+
+func TestRenderInvoiceUBL(t *testing.T) {
+	doc := testInvoiceDocument()
+
+	body, err := RenderInvoiceUBL(doc)
+	require.NoError(t, err)
+
+	var parsed struct {
+		ID        string `xml:"ID"`
+		IssueDate string `xml:"IssueDate"`
+		Currency  string `xml:"DocumentCurrencyCode"`
+		Total     struct {
+			LineExtension string `xml:"LineExtensionAmount"`
+			Allowance     string `xml:"AllowanceTotalAmount"`
+			Payable       string `xml:"PayableAmount"`
+		} `xml:"LegalMonetaryTotal"`
+		Lines []struct {
+			Amount string `xml:"LineExtensionAmount"`
+		} `xml:"InvoiceLine"`
+	}
+	require.NoError(t, xml.Unmarshal(body, &parsed))
+
+	assert.Equal(t, "INV-2024/001", parsed.ID)
+	assert.Equal(t, "2024-03-01", parsed.IssueDate)
+	assert.Equal(t, "EUR", parsed.Currency)
+	require.Len(t, parsed.Lines, 2)
+	assert.Equal(t, "1000.00", parsed.Lines[0].Amount)
+	assert.Equal(t, "80.00", parsed.Lines[1].Amount)
+	assert.Equal(t, "1080.00", parsed.Total.LineExtension)
+	assert.Equal(t, "108.00", parsed.Total.Allowance)
+	assert.Equal(t, "972.00", parsed.Total.Payable)
+	assert.Contains(t, string(body), `<cbc:CompanyID>BE0403019261</cbc:CompanyID>`)
+}
+
+func TestRenderInvoicePDF(t *testing.T) {
+	doc := testInvoiceDocument()
+
+	body, err := RenderInvoicePDF(doc)
+	require.NoError(t, err)
+
+	assert.True(t, bytes.HasPrefix(body, []byte("%PDF-1.4\n")))
+	assert.True(t, bytes.HasSuffix(body, []byte("%%EOF\n")))
+	assert.Contains(t, string(body), "(Number: INV-2024/001)")
+	assert.Contains(t, string(body), `(Consulting \(March\))`)
+	assert.Contains(t, string(body), `(Caf\351 & croissants)`)
+
+	// startxref points at the cross-reference table and every entry at its object
+	startxref := regexp.MustCompile(`startxref\n(\d+)\n`).FindSubmatch(body)
+	require.NotNil(t, startxref)
+	offset, _ := strconv.Atoi(string(startxref[1]))
+	require.True(t, bytes.HasPrefix(body[offset:], []byte("xref\n")))
+	entries := regexp.MustCompile(`(\d{10}) 00000 n `).FindAllSubmatch(body[offset:], -1)
+	require.NotEmpty(t, entries)
+	for i, entry := range entries {
+		objectOffset, _ := strconv.Atoi(string(entry[1]))
+		assert.True(t, bytes.HasPrefix(body[objectOffset:], []byte(strconv.Itoa(i+1)+" 0 obj")), "object %d", i+1)
+	}
+
+	t.Run("long invoices span several pages", func(t *testing.T) {
+		doc := testInvoiceDocument()
+		for i := 0; i < 80; i++ {
+			doc.Invoice.Lines = append(doc.Invoice.Lines, models.InvoiceLine{Description: "Item", Quantity: 1, UnitPrice: 1, Total: 1})
+		}
+		body, err := RenderInvoicePDF(doc)
+		require.NoError(t, err)
+		assert.Contains(t, string(body), "/Count 3")
+		assert.Contains(t, string(body), "(Page 3 of 3)")
+	})
+}
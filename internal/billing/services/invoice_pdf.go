@@ -0,0 +1,336 @@
+package services
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// A4 page geometry in PDF points
+const (
+	pdfPageWidth    = 595.0
+	pdfPageHeight   = 842.0
+	pdfMargin       = 50.0
+	pdfLineHeight   = 14.0
+	pdfBottomMargin = 80.0
+	pdfDateLayout   = "2006-01-02"
+
+	pdfDescriptionWrap = 48 // Characters per description line in the line table
+)
+
+// Columns of the invoice line table, as right edges except for the description
+const (
+	pdfColDescription = pdfMargin
+	pdfColQuantity    = 340.0
+	pdfColUnitPrice   = 420.0
+	pdfColDiscount    = 480.0
+	pdfColTotal       = pdfPageWidth - pdfMargin
+)
+
+// RenderInvoicePDF renders the invoice as a self-contained PDF document. It uses the
+// standard Helvetica fonts, so no font files are embedded; characters outside
+// Windows-1252 are printed as '?'.
+func RenderInvoicePDF(doc InvoiceDocument) ([]byte, error) {
+	invoice := doc.Invoice
+	w := newPDFWriter()
+
+	// Header: issuer on the left, invoice identification on the right
+	y := pdfPageHeight - pdfMargin
+	w.text(pdfMargin, y, 14, true, doc.Issuer.Name)
+	w.rightText(pdfColTotal, y, 18, true, "INVOICE")
+	issuerLines := append(doc.Issuer.AddressLines(), nonEmpty(doc.Issuer.Email, taxIDLine(doc.Issuer.TaxID))...)
+	infoLines := []string{
+		"Number: " + invoice.Number,
+		"Issue date: " + invoice.IssueDate.Format(pdfDateLayout),
+	}
+	if !invoice.DueDate.IsZero() {
+		infoLines = append(infoLines, "Due date: "+invoice.DueDate.Format(pdfDateLayout))
+	}
+	for i := 0; i < len(issuerLines) || i < len(infoLines); i++ {
+		y -= pdfLineHeight
+		if i < len(issuerLines) {
+			w.text(pdfMargin, y, 10, false, issuerLines[i])
+		}
+		if i < len(infoLines) {
+			w.rightText(pdfColTotal, y, 10, false, infoLines[i])
+		}
+	}
+
+	// Customer
+	y -= 2 * pdfLineHeight
+	w.text(pdfMargin, y, 10, true, "Bill to")
+	y -= pdfLineHeight
+	w.text(pdfMargin, y, 11, false, doc.Customer.Name)
+	for _, line := range append(doc.Customer.AddressLines(), nonEmpty(doc.Customer.Email, taxIDLine(doc.Customer.TaxID))...) {
+		y -= pdfLineHeight
+		w.text(pdfMargin, y, 10, false, line)
+	}
+	if invoice.Description != "" {
+		y -= 2 * pdfLineHeight
+		for _, line := range wrapText(invoice.Description, 90) {
+			w.text(pdfMargin, y, 10, false, line)
+			y -= pdfLineHeight
+		}
+	}
+
+	// Line table
+	y -= 2 * pdfLineHeight
+	y = w.tableHeader(y)
+	for _, line := range doc.Lines() {
+		description := wrapText(line.Description, pdfDescriptionWrap)
+		if y-float64(len(description)-1)*pdfLineHeight < pdfBottomMargin {
+			w.newPage()
+			y = w.tableHeader(pdfPageHeight - pdfMargin)
+		}
+		w.rightText(pdfColQuantity, y, 10, false, formatDecimal(line.Quantity))
+		w.rightText(pdfColUnitPrice, y, 10, false, formatMoney(line.UnitPrice))
+		if line.DiscountAmount > 0 {
+			w.rightText(pdfColDiscount, y, 10, false, "-"+formatMoney(line.DiscountAmount))
+		}
+		w.rightText(pdfColTotal, y, 10, false, formatMoney(line.Total))
+		for _, text := range description {
+			w.text(pdfColDescription, y, 10, false, text)
+			y -= pdfLineHeight
+		}
+	}
+
+	// Totals
+	totals := [][2]string{{"Subtotal", formatMoney(invoice.Amount)}}
+	if invoice.DiscountAmount > 0 {
+		label := "Discount"
+		if invoice.DiscountCode != "" {
+			label += " (" + invoice.DiscountCode + ")"
+		}
+		totals = append(totals, [2]string{label, "-" + formatMoney(invoice.DiscountAmount)})
+	}
+	totals = append(totals, [2]string{"Total " + doc.Currency, formatMoney(invoice.Total)})
+	if y-float64(len(totals)+4)*pdfLineHeight < pdfBottomMargin {
+		w.newPage()
+		y = pdfPageHeight - pdfMargin
+	}
+	y -= pdfLineHeight / 2
+	w.line(pdfColQuantity-60, y+pdfLineHeight-4, pdfColTotal, y+pdfLineHeight-4)
+	for i, total := range totals {
+		bold := i == len(totals)-1
+		w.text(pdfColQuantity-60, y, 10, bold, total[0])
+		w.rightText(pdfColTotal, y, 10, bold, total[1])
+		y -= pdfLineHeight
+	}
+
+	// Payment terms
+	if invoice.PaymentTermCode != "" {
+		y -= pdfLineHeight
+		w.text(pdfMargin, y, 9, false, "Payment terms: "+invoice.PaymentTermCode)
+	}
+	if invoice.EarlyPaymentDate != nil && invoice.EarlyPaymentDiscount > 0 {
+		y -= pdfLineHeight
+		w.text(pdfMargin, y, 9, false, fmt.Sprintf("Early payment discount: %s %s if paid by %s",
+			formatMoney(invoice.EarlyPaymentDiscount), doc.Currency, invoice.EarlyPaymentDate.Format(pdfDateLayout)))
+	}
+
+	return w.bytes(), nil
+}
+
+// tableHeader prints the line table header at y and returns the y of the first row
+func (w *pdfWriter) tableHeader(y float64) float64 {
+	w.text(pdfColDescription, y, 10, true, "Description")
+	w.rightText(pdfColQuantity, y, 10, true, "Qty")
+	w.rightText(pdfColUnitPrice, y, 10, true, "Unit price")
+	w.rightText(pdfColDiscount, y, 10, true, "Discount")
+	w.rightText(pdfColTotal, y, 10, true, "Total")
+	w.line(pdfMargin, y-4, pdfColTotal, y-4)
+	return y - pdfLineHeight - 4
+}
+
+// pdfWriter accumulates page content streams and serializes them as a PDF 1.4 file
+type pdfWriter struct {
+	pages []*bytes.Buffer
+}
+
+func newPDFWriter() *pdfWriter {
+	w := &pdfWriter{}
+	w.newPage()
+	return w
+}
+
+func (w *pdfWriter) newPage() {
+	w.pages = append(w.pages, &bytes.Buffer{})
+}
+
+func (w *pdfWriter) current() *bytes.Buffer {
+	return w.pages[len(w.pages)-1]
+}
+
+func (w *pdfWriter) text(x, y, size float64, bold bool, text string) {
+	if text == "" {
+		return
+	}
+	font := "F1"
+	if bold {
+		font = "F2"
+	}
+	fmt.Fprintf(w.current(), "BT /%s %s Tf %s %s Td (%s) Tj ET\n",
+		font, pdfNumber(size), pdfNumber(x), pdfNumber(y), pdfEscape(text))
+}
+
+// rightText prints text so that it ends at x
+func (w *pdfWriter) rightText(x, y, size float64, bold bool, text string) {
+	w.text(x-textWidth(text, size), y, size, bold, text)
+}
+
+func (w *pdfWriter) line(x1, y1, x2, y2 float64) {
+	fmt.Fprintf(w.current(), "0.5 w %s %s m %s %s l S\n",
+		pdfNumber(x1), pdfNumber(y1), pdfNumber(x2), pdfNumber(y2))
+}
+
+// bytes serializes the document: catalog, page tree, the two fonts, then a page
+// object and content stream per page, followed by the cross-reference table
+func (w *pdfWriter) bytes() []byte {
+	var out bytes.Buffer
+	var offsets []int
+	object := func(body string) {
+		offsets = append(offsets, out.Len())
+		fmt.Fprintf(&out, "%d 0 obj\n%s\nendobj\n", len(offsets), body)
+	}
+
+	out.WriteString("%PDF-1.4\n%\xe2\xe3\xcf\xd3\n")
+
+	const firstPageObject = 5
+	kids := make([]string, len(w.pages))
+	for i := range w.pages {
+		kids[i] = fmt.Sprintf("%d 0 R", firstPageObject+2*i)
+	}
+	object("<< /Type /Catalog /Pages 2 0 R >>")
+	object(fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(w.pages)))
+	object("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica /Encoding /WinAnsiEncoding >>")
+	object("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica-Bold /Encoding /WinAnsiEncoding >>")
+
+	for i, page := range w.pages {
+		content := page.Bytes()
+		if len(w.pages) > 1 {
+			footer := fmt.Sprintf("Page %d of %d", i+1, len(w.pages))
+			content = append(append([]byte(nil), content...), fmt.Sprintf("BT /F1 8 Tf %s %s Td (%s) Tj ET\n",
+				pdfNumber(pdfPageWidth-pdfMargin-textWidth(footer, 8)), pdfNumber(pdfMargin/2), footer)...)
+		}
+		object(fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %s %s] /Resources << /Font << /F1 3 0 R /F2 4 0 R >> >> /Contents %d 0 R >>",
+			pdfNumber(pdfPageWidth), pdfNumber(pdfPageHeight), len(offsets)+2))
+		object(fmt.Sprintf("<< /Length %d >>\nstream\n%sendstream", len(content), content))
+	}
+
+	xref := out.Len()
+	fmt.Fprintf(&out, "xref\n0 %d\n0000000000 65535 f \n", len(offsets)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(&out, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&out, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(offsets)+1, xref)
+	return out.Bytes()
+}
+
+// winAnsiSpecials maps the characters Windows-1252 places in 0x80-0x9F
+var winAnsiSpecials = map[rune]byte{
+	'€': 0x80, '‚': 0x82, 'ƒ': 0x83, '„': 0x84, '…': 0x85, '†': 0x86, '‡': 0x87, 'ˆ': 0x88,
+	'‰': 0x89, 'Š': 0x8A, '‹': 0x8B, 'Œ': 0x8C, 'Ž': 0x8E, '‘': 0x91, '’': 0x92, '“': 0x93,
+	'”': 0x94, '•': 0x95, '–': 0x96, '—': 0x97, '˜': 0x98, '™': 0x99, 'š': 0x9A, '›': 0x9B,
+	'œ': 0x9C, 'ž': 0x9E, 'Ÿ': 0x9F,
+}
+
+// pdfEscape encodes text as a WinAnsi PDF string literal body
+func pdfEscape(text string) string {
+	var out strings.Builder
+	for _, r := range text {
+		switch {
+		case r == '(' || r == ')' || r == '\\':
+			out.WriteByte('\\')
+			out.WriteRune(r)
+		case r == '\t' || r == '\n' || r == '\r':
+			out.WriteByte(' ')
+		case r >= 0x20 && r < 0x7F:
+			out.WriteRune(r)
+		case r >= 0xA0 && r <= 0xFF:
+			fmt.Fprintf(&out, "\\%03o", r)
+		default:
+			if b, ok := winAnsiSpecials[r]; ok {
+				fmt.Fprintf(&out, "\\%03o", b)
+			} else {
+				out.WriteByte('?')
+			}
+		}
+	}
+	return out.String()
+}
+
+// textWidth approximates the Helvetica width of text, which is enough to right-align
+// amounts and short labels
+func textWidth(text string, size float64) float64 {
+	var units float64
+	for _, r := range text {
+		switch {
+		case r == ' ' || r == '.' || r == ',' || r == ':' || r == 'i' || r == 'l' || r == 'I':
+			units += 278
+		case r == '-' || r == '(' || r == ')' || r == 'r' || r == 't' || r == 'f':
+			units += 333
+		case r == 'm' || r == 'M' || r == 'W' || r == 'w':
+			units += 833
+		case r >= 'A' && r <= 'Z':
+			units += 667
+		default:
+			units += 556
+		}
+	}
+	return units * size / 1000
+}
+
+func pdfNumber(value float64) string {
+	return strconv.FormatFloat(roundCents(value), 'f', -1, 64)
+}
+
+func formatMoney(amount float64) string {
+	return strconv.FormatFloat(roundCents(amount), 'f', 2, 64)
+}
+
+func taxIDLine(taxID string) string {
+	if taxID == "" {
+		return ""
+	}
+	return "Tax ID: " + taxID
+}
+
+func nonEmpty(values ...string) []string {
+	var out []string
+	for _, value := range values {
+		if value != "" {
+			out = append(out, value)
+		}
+	}
+	return out
+}
+
+// wrapText splits text into lines of at most width characters, breaking on spaces
+func wrapText(text string, width int) []string {
+	var lines []string
+	var current []rune
+	for _, word := range strings.Fields(text) {
+		runes := []rune(word)
+		for len(runes) > width {
+			if len(current) > 0 {
+				lines = append(lines, string(current))
+				current = nil
+			}
+			lines = append(lines, string(runes[:width]))
+			runes = runes[width:]
+		}
+		if len(current) > 0 && len(current)+1+len(runes) > width {
+			lines = append(lines, string(current))
+			current = nil
+		}
+		if len(current) > 0 {
+			current = append(current, ' ')
+		}
+		current = append(current, runes...)
+	}
+	if len(current) > 0 || len(lines) == 0 {
+		lines = append(lines, string(current))
+	}
+	return lines
+}
@@ -0,0 +1,242 @@
+package services
+
+import (
+	"encoding/xml"
+	"math"
+	"strconv"
+
+	"gaetanjaminon/GoTuto/internal/billing/models"
+)
+
+// UBL 2.1 namespaces and the EN 16931 customization the rendered invoices declare
+const (
+	ublInvoiceNamespace   = "urn:oasis:names:specification:ubl:schema:xsd:Invoice-2"
+	ublCACNamespace       = "urn:oasis:names:specification:ubl:schema:xsd:CommonAggregateComponents-2"
+	ublCBCNamespace       = "urn:oasis:names:specification:ubl:schema:xsd:CommonBasicComponents-2"
+	ublCustomizationID    = "urn:cen.eu:en16931:2017"
+	ublInvoiceTypeCode    = "380" // Commercial invoice
+	ublUnitCode           = "C62" // Unit (piece)
+	ublDateLayout         = "2006-01-02"
+	ublDiscountReasonCode = "95" // Discount
+)
+
+type ublInvoice struct {
+	XMLName              xml.Name            `xml:"Invoice"`
+	Namespace            string              `xml:"xmlns,attr"`
+	CACNamespace         string              `xml:"xmlns:cac,attr"`
+	CBCNamespace         string              `xml:"xmlns:cbc,attr"`
+	UBLVersionID         string              `xml:"cbc:UBLVersionID"`
+	CustomizationID      string              `xml:"cbc:CustomizationID"`
+	ID                   string              `xml:"cbc:ID"`
+	IssueDate            string              `xml:"cbc:IssueDate"`
+	DueDate              string              `xml:"cbc:DueDate,omitempty"`
+	InvoiceTypeCode      string              `xml:"cbc:InvoiceTypeCode"`
+	Note                 string              `xml:"cbc:Note,omitempty"`
+	DocumentCurrencyCode string              `xml:"cbc:DocumentCurrencyCode"`
+	Supplier             ublPartyWrapper     `xml:"cac:AccountingSupplierParty"`
+	Customer             ublPartyWrapper     `xml:"cac:AccountingCustomerParty"`
+	PaymentTerms         *ublPaymentTerms    `xml:"cac:PaymentTerms,omitempty"`
+	AllowanceCharge      *ublAllowanceCharge `xml:"cac:AllowanceCharge,omitempty"`
+	LegalMonetaryTotal   ublMonetaryTotal    `xml:"cac:LegalMonetaryTotal"`
+	Lines                []ublInvoiceLine    `xml:"cac:InvoiceLine"`
+}
+
+type ublPartyWrapper struct {
+	Party ublParty `xml:"cac:Party"`
+}
+
+type ublParty struct {
+	Name           *ublPartyName      `xml:"cac:PartyName,omitempty"`
+	PostalAddress  ublPostalAddress   `xml:"cac:PostalAddress"`
+	PartyTaxScheme *ublPartyTaxScheme `xml:"cac:PartyTaxScheme,omitempty"`
+	LegalEntity    ublLegalEntity     `xml:"cac:PartyLegalEntity"`
+	Contact        *ublContact        `xml:"cac:Contact,omitempty"`
+}
+
+type ublPartyName struct {
+	Name string `xml:"cbc:Name"`
+}
+
+type ublPostalAddress struct {
+	StreetName string      `xml:"cbc:StreetName,omitempty"`
+	CityName   string      `xml:"cbc:CityName,omitempty"`
+	PostalZone string      `xml:"cbc:PostalZone,omitempty"`
+	Country    *ublCountry `xml:"cac:Country,omitempty"`
+}
+
+type ublCountry struct {
+	IdentificationCode string `xml:"cbc:IdentificationCode"`
+}
+
+type ublPartyTaxScheme struct {
+	CompanyID string       `xml:"cbc:CompanyID"`
+	TaxScheme ublTaxScheme `xml:"cac:TaxScheme"`
+}
+
+type ublTaxScheme struct {
+	ID string `xml:"cbc:ID"`
+}
+
+type ublLegalEntity struct {
+	RegistrationName string `xml:"cbc:RegistrationName"`
+}
+
+type ublContact struct {
+	ElectronicMail string `xml:"cbc:ElectronicMail"`
+}
+
+type ublPaymentTerms struct {
+	Note string `xml:"cbc:Note"`
+}
+
+type ublAllowanceCharge struct {
+	ChargeIndicator  bool       `xml:"cbc:ChargeIndicator"`
+	ReasonCode       string     `xml:"cbc:AllowanceChargeReasonCode"`
+	Reason           string     `xml:"cbc:AllowanceChargeReason"`
+	MultiplierFactor string     `xml:"cbc:MultiplierFactorNumeric,omitempty"`
+	Amount           ublAmount  `xml:"cbc:Amount"`
+	BaseAmount       *ublAmount `xml:"cbc:BaseAmount,omitempty"`
+}
+
+type ublMonetaryTotal struct {
+	LineExtensionAmount  ublAmount  `xml:"cbc:LineExtensionAmount"`
+	AllowanceTotalAmount *ublAmount `xml:"cbc:AllowanceTotalAmount,omitempty"`
+	PayableAmount        ublAmount  `xml:"cbc:PayableAmount"`
+}
+
+type ublInvoiceLine struct {
+	ID                  string      `xml:"cbc:ID"`
+	InvoicedQuantity    ublQuantity `xml:"cbc:InvoicedQuantity"`
+	LineExtensionAmount ublAmount   `xml:"cbc:LineExtensionAmount"`
+	Item                ublItem     `xml:"cac:Item"`
+	Price               ublPrice    `xml:"cac:Price"`
+}
+
+type ublItem struct {
+	Name string `xml:"cbc:Name"`
+}
+
+type ublPrice struct {
+	PriceAmount ublAmount `xml:"cbc:PriceAmount"`
+}
+
+type ublAmount struct {
+	CurrencyID string `xml:"currencyID,attr"`
+	Value      string `xml:",chardata"`
+}
+
+type ublQuantity struct {
+	UnitCode string `xml:"unitCode,attr"`
+	Value    string `xml:",chardata"`
+}
+
+// RenderInvoiceUBL renders the invoice as a UBL 2.1 XML document. The billing models have
+// no tax rates yet, so no TaxTotal is emitted and amounts are exchanged as-is.
+func RenderInvoiceUBL(doc InvoiceDocument) ([]byte, error) {
+	invoice := doc.Invoice
+
+	out := ublInvoice{
+		Namespace:            ublInvoiceNamespace,
+		CACNamespace:         ublCACNamespace,
+		CBCNamespace:         ublCBCNamespace,
+		UBLVersionID:         "2.1",
+		CustomizationID:      ublCustomizationID,
+		ID:                   invoice.Number,
+		IssueDate:            invoice.IssueDate.Format(ublDateLayout),
+		InvoiceTypeCode:      ublInvoiceTypeCode,
+		Note:                 invoice.Description,
+		DocumentCurrencyCode: doc.Currency,
+		Supplier:             ublPartyWrapper{Party: newUBLParty(doc.Issuer)},
+		Customer:             ublPartyWrapper{Party: newUBLParty(doc.Customer)},
+	}
+	if !invoice.DueDate.IsZero() {
+		out.DueDate = invoice.DueDate.Format(ublDateLayout)
+	}
+	if invoice.PaymentTermCode != "" {
+		out.PaymentTerms = &ublPaymentTerms{Note: invoice.PaymentTermCode}
+	}
+
+	var lineExtension float64
+	for i, line := range doc.Lines() {
+		lineExtension += line.Total
+		out.Lines = append(out.Lines, ublInvoiceLine{
+			ID:                  strconv.Itoa(i + 1),
+			InvoicedQuantity:    ublQuantity{UnitCode: ublUnitCode, Value: formatDecimal(line.Quantity)},
+			LineExtensionAmount: doc.amount(line.Total),
+			Item:                ublItem{Name: line.Description},
+			Price:               ublPrice{PriceAmount: doc.amount(line.UnitPrice)},
+		})
+	}
+
+	// Line discounts are already netted in the line amounts; the rest is the invoice-level allowance
+	invoiceDiscount := roundCents(invoice.DiscountAmount - doc.LineDiscountTotal())
+	out.LegalMonetaryTotal = ublMonetaryTotal{
+		LineExtensionAmount: doc.amount(lineExtension),
+		PayableAmount:       doc.amount(invoice.Total),
+	}
+	if invoiceDiscount > 0 {
+		allowance := &ublAllowanceCharge{
+			ChargeIndicator: false,
+			ReasonCode:      ublDiscountReasonCode,
+			Reason:          "Discount",
+			Amount:          doc.amount(invoiceDiscount),
+		}
+		if invoice.DiscountCode != "" {
+			allowance.Reason = "Discount " + invoice.DiscountCode
+		}
+		if invoice.Discount.Type == models.DiscountTypePercentage {
+			base := doc.amount(lineExtension)
+			allowance.MultiplierFactor = formatDecimal(invoice.Discount.Value)
+			allowance.BaseAmount = &base
+		}
+		total := doc.amount(invoiceDiscount)
+		out.AllowanceCharge = allowance
+		out.LegalMonetaryTotal.AllowanceTotalAmount = &total
+	}
+
+	body, err := xml.MarshalIndent(out, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(xml.Header), body...), nil
+}
+
+func newUBLParty(party InvoiceParty) ublParty {
+	out := ublParty{
+		PostalAddress: ublPostalAddress{
+			StreetName: party.Street,
+			CityName:   party.City,
+			PostalZone: party.PostalCode,
+		},
+		LegalEntity: ublLegalEntity{RegistrationName: party.Name},
+	}
+	if party.Name != "" {
+		out.Name = &ublPartyName{Name: party.Name}
+	}
+	if out.PostalAddress.StreetName == "" {
+		out.PostalAddress.StreetName = party.Address
+	}
+	if party.Country != "" {
+		out.PostalAddress.Country = &ublCountry{IdentificationCode: party.Country}
+	}
+	if party.TaxID != "" {
+		out.PartyTaxScheme = &ublPartyTaxScheme{CompanyID: party.TaxID, TaxScheme: ublTaxScheme{ID: "VAT"}}
+	}
+	if party.Email != "" {
+		out.Contact = &ublContact{ElectronicMail: party.Email}
+	}
+	return out
+}
+
+func (d InvoiceDocument) amount(value float64) ublAmount {
+	return ublAmount{CurrencyID: d.Currency, Value: strconv.FormatFloat(roundCents(value), 'f', 2, 64)}
+}
+
+// formatDecimal formats a quantity or rate without trailing zeros
+func formatDecimal(value float64) string {
+	return strconv.FormatFloat(value, 'f', -1, 64)
+}
+
+func roundCents(amount float64) float64 {
+	return math.Round(amount*100) / 100
+}
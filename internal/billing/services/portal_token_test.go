@@ -0,0 +1,54 @@
+package services
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPortalTokenSigner(t *testing.T) {
+	signer := NewPortalTokenSigner("test-secret-with-at-least-32-characters")
+	now := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	claims := PortalClaims{TokenID: 7, ClientID: 42, ExpiresAt: now.Add(time.Hour)}
+
+	token, err := signer.Sign(claims)
+	require.NoError(t, err)
+
+	t.Run("valid token", func(t *testing.T) {
+		verified, err := signer.Verify(token, now)
+		require.NoError(t, err)
+		assert.Equal(t, uint(7), verified.TokenID)
+		assert.Equal(t, uint(42), verified.ClientID)
+		assert.True(t, verified.ExpiresAt.Equal(claims.ExpiresAt))
+	})
+
+	t.Run("expired token", func(t *testing.T) {
+		_, err := signer.Verify(token, now.Add(time.Hour))
+		assert.ErrorIs(t, err, ErrPortalTokenExpired)
+	})
+
+	t.Run("other secret", func(t *testing.T) {
+		_, err := NewPortalTokenSigner("another-secret-with-at-least-32-chars").Verify(token, now)
+		assert.ErrorIs(t, err, ErrPortalTokenSignature)
+	})
+
+	t.Run("tampered claims", func(t *testing.T) {
+		forged, err := NewPortalTokenSigner("attacker").Sign(PortalClaims{TokenID: 7, ClientID: 43, ExpiresAt: claims.ExpiresAt})
+		require.NoError(t, err)
+		payload, _, _ := strings.Cut(forged, ".")
+		_, signature, _ := strings.Cut(token, ".")
+
+		_, err = signer.Verify(payload+"."+signature, now)
+		assert.ErrorIs(t, err, ErrPortalTokenSignature)
+	})
+
+	t.Run("malformed", func(t *testing.T) {
+		for _, value := range []string{"", "abc", ".", "abc.!!!"} {
+			_, err := signer.Verify(value, now)
+			assert.ErrorIs(t, err, ErrPortalTokenMalformed, value)
+		}
+	})
+}
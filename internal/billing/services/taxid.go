@@ -0,0 +1,63 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"gaetanjaminon/GoTuto/internal/billing/models"
+)
+
+// TaxIDVerification is the outcome of verifying a client's tax identifier
+type TaxIDVerification struct {
+	TaxID     string             `json:"tax_id"`
+	Scheme    models.TaxIDScheme `json:"scheme"`
+	Valid     bool               `json:"valid"`
+	Reason    string             `json:"reason,omitempty"`
+	Name      string             `json:"name,omitempty"`    // Registered name, when the source provides it
+	Address   string             `json:"address,omitempty"` // Registered address, when the source provides it
+	Source    string             `json:"source"`
+	Registry  bool               `json:"registry"` // Confirmed by an official registry, not only by format and check digits
+	CheckedAt time.Time          `json:"checked_at"`
+}
+
+// TaxIDVerifier checks whether a tax identifier is valid. Implementations may query
+// an external registry (such as the EU VIES service); an error means the check could
+// not be performed, while an invalid number is reported through Valid and Reason.
+type TaxIDVerifier interface {
+	Verify(ctx context.Context, scheme models.TaxIDScheme, taxID string) (*TaxIDVerification, error)
+}
+
+// OfflineTaxIDVerifier validates format and check digits without any network access.
+// Its results are not registry checks, so they never mark a tax ID as verified.
+type OfflineTaxIDVerifier struct {
+	now func() time.Time
+}
+
+// NewOfflineTaxIDVerifier creates a verifier backed by the offline checksum rules
+func NewOfflineTaxIDVerifier() *OfflineTaxIDVerifier {
+	return &OfflineTaxIDVerifier{now: time.Now}
+}
+
+// Verify implements TaxIDVerifier
+func (v *OfflineTaxIDVerifier) Verify(ctx context.Context, scheme models.TaxIDScheme, taxID string) (*TaxIDVerification, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	if scheme == "" {
+		scheme = models.DetectTaxIDScheme(taxID)
+	}
+	normalized, err := models.ValidateTaxID(scheme, taxID)
+
+	result := &TaxIDVerification{
+		TaxID:     normalized,
+		Scheme:    scheme,
+		Valid:     err == nil,
+		Source:    "offline",
+		CheckedAt: v.now(),
+	}
+	if err != nil {
+		result.Reason = err.Error()
+	}
+	return result, nil
+}
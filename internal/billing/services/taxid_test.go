@@ -0,0 +1,39 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"gaetanjaminon/GoTuto/internal/billing/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOfflineTaxIDVerifier_Verify(t *testing.T) {
+	verifier := NewOfflineTaxIDVerifier()
+
+	t.Run("valid number", func(t *testing.T) {
+		result, err := verifier.Verify(context.Background(), "", "be 0403.019.261")
+		require.NoError(t, err)
+		assert.True(t, result.Valid)
+		assert.Equal(t, "BE0403019261", result.TaxID)
+		assert.Equal(t, models.TaxIDSchemeEUVAT, result.Scheme)
+		assert.Equal(t, "offline", result.Source)
+		assert.False(t, result.Registry, "format checks do not verify registration")
+		assert.False(t, result.CheckedAt.IsZero())
+	})
+
+	t.Run("invalid number is a result, not an error", func(t *testing.T) {
+		result, err := verifier.Verify(context.Background(), models.TaxIDSchemeEUVAT, "DE136695977")
+		require.NoError(t, err)
+		assert.False(t, result.Valid)
+		assert.NotEmpty(t, result.Reason)
+	})
+
+	t.Run("cancelled context", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		_, err := verifier.Verify(ctx, "", "DE136695976")
+		assert.Error(t, err)
+	})
+}
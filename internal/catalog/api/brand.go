@@ -0,0 +1,203 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+
+	"gaetanjaminon/GoTuto/internal/catalog/database"
+	"gaetanjaminon/GoTuto/internal/catalog/models"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// GetBrands retrieves all brands with optional pagination and filters
+func GetBrands(c *gin.Context) {
+	var brands []models.Brand
+
+	// Optional pagination
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 20
+	}
+	offset := (page - 1) * limit
+
+	// Optional filters
+	search := c.Query("search")
+	isActive := c.Query("is_active")
+
+	query := database.DB.Model(&models.Brand{})
+	if search != "" {
+		query = query.Where("name ILIKE ? OR description ILIKE ?", "%"+search+"%", "%"+search+"%")
+	}
+	if isActive != "" {
+		query = query.Where("is_active = ?", isActive == "true")
+	}
+	query = query.Session(&gorm.Session{}) // Shared by the count and the page query
+
+	// Get total count for pagination
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve brands"})
+		return
+	}
+
+	if err := query.Order("name ASC").Limit(limit).Offset(offset).Find(&brands).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve brands"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"brands": brands,
+		"pagination": gin.H{
+			"page":  page,
+			"limit": limit,
+			"total": total,
+		},
+	})
+}
+
+// GetBrand retrieves a single brand by ID with the number of its products
+func GetBrand(c *gin.Context) {
+	id := c.Param("id")
+	var brand models.Brand
+
+	if err := database.DB.First(&brand, id).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Brand not found"})
+		return
+	}
+
+	var productCount int64
+	database.DB.Model(&models.Product{}).Where("brand_id = ?", brand.ID).Count(&productCount)
+
+	c.JSON(http.StatusOK, gin.H{
+		"brand":         brand,
+		"product_count": productCount,
+	})
+}
+
+// CreateBrand creates a new brand
+func CreateBrand(c *gin.Context) {
+	var req models.CreateBrandRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	brand := models.Brand{
+		Name:        req.Name,
+		Description: req.Description,
+		Website:     req.Website,
+		IsActive:    true,
+	}
+	if req.IsActive != nil {
+		brand.IsActive = *req.IsActive
+	}
+
+	// Validate brand
+	if err := brand.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Check if name already exists (brand names are unique, soft-deleted ones included)
+	var existing models.Brand
+	if err := database.DB.Unscoped().Where("LOWER(name) = LOWER(?)", brand.Name).First(&existing).Error; err == nil {
+		c.JSON(http.StatusConflict, gin.H{"error": "Brand with this name already exists"})
+		return
+	}
+
+	if err := database.DB.Create(&brand).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create brand"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, brand)
+}
+
+// UpdateBrand updates an existing brand
+func UpdateBrand(c *gin.Context) {
+	id := c.Param("id")
+	var brand models.Brand
+
+	if err := database.DB.First(&brand, id).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Brand not found"})
+		return
+	}
+
+	var req models.UpdateBrandRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Update only provided fields
+	if req.Name != "" {
+		brand.Name = req.Name
+	}
+	if req.Description != "" {
+		brand.Description = req.Description
+	}
+	if req.Website != "" {
+		brand.Website = req.Website
+	}
+	if req.IsActive != nil {
+		brand.IsActive = *req.IsActive
+	}
+
+	// Validate brand
+	if err := brand.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if req.Name != "" {
+		var existing models.Brand
+		if err := database.DB.Unscoped().Where("LOWER(name) = LOWER(?) AND id <> ?", req.Name, brand.ID).First(&existing).Error; err == nil {
+			c.JSON(http.StatusConflict, gin.H{"error": "Brand with this name already exists"})
+			return
+		}
+	}
+
+	if err := database.DB.Save(&brand).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update brand"})
+		return
+	}
+
+	c.JSON(http.StatusOK, brand)
+}
+
+// DeleteBrand soft deletes a brand that no product uses anymore
+func DeleteBrand(c *gin.Context) {
+	id := c.Param("id")
+	var brand models.Brand
+
+	if err := database.DB.First(&brand, id).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Brand not found"})
+		return
+	}
+
+	// Check if brand has products
+	var productCount int64
+	database.DB.Model(&models.Product{}).Where("brand_id = ?", brand.ID).Count(&productCount)
+
+	if productCount > 0 {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":         "Cannot delete brand with existing products",
+			"product_count": productCount,
+		})
+		return
+	}
+
+	if err := database.DB.Delete(&brand).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete brand"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Brand deleted successfully"})
+}
@@ -1,6 +1,8 @@
 package api
 
 import (
+	"errors"
+	"fmt"
 	"net/http"
 	"strconv"
 	
@@ -8,6 +10,7 @@ import (
 	"gaetanjaminon/GoTuto/internal/catalog/models"
 	
 	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
 )
 
 // GetCategories retrieves all categories with optional pagination and filters
@@ -109,8 +112,15 @@ func CreateCategory(c *gin.Context) {
 		}
 	}
 	
+	// Category codes identify categories in generated SKUs
+	if req.Code != "" && categoryCodeTaken(req.Code, 0) {
+		c.JSON(http.StatusConflict, gin.H{"error": "Category code is already used"})
+		return
+	}
+	
 	category := models.Category{
 		Name:        req.Name,
+		Code:        req.Code,
 		Description: req.Description,
 		ParentID:    req.ParentID,
 	}
@@ -123,7 +133,23 @@ func CreateCategory(c *gin.Context) {
 		category.SortOrder = *req.SortOrder
 	}
 	
-	if err := database.DB.Create(&category).Error; err != nil {
+	// The parent is checked against the maximum hierarchy depth under lock
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		if req.ParentID != nil {
+			if err := checkCategoryPlacement(tx, 0, req.ParentID); err != nil {
+				return err
+			}
+		}
+		if err := tx.Create(&category).Error; err != nil {
+			return err
+		}
+		return updateCategoryPath(tx, &category)
+	})
+	if errors.Is(err, errInvalidCategoryPlacement) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create category"})
 		return
 	}
@@ -175,6 +201,13 @@ func UpdateCategory(c *gin.Context) {
 	if req.Name != "" {
 		category.Name = req.Name
 	}
+	if req.Code != nil {
+		if *req.Code != "" && categoryCodeTaken(*req.Code, category.ID) {
+			c.JSON(http.StatusConflict, gin.H{"error": "Category code is already used"})
+			return
+		}
+		category.Code = *req.Code
+	}
 	if req.Description != "" {
 		category.Description = req.Description
 	}
@@ -188,7 +221,27 @@ func UpdateCategory(c *gin.Context) {
 		category.SortOrder = *req.SortOrder
 	}
 	
-	if err := database.DB.Save(&category).Error; err != nil {
+	// A new parent is checked for cycles and depth under lock, then moves the
+	// materialized paths of the whole subtree
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		if req.ParentID != nil {
+			if err := checkCategoryPlacement(tx, category.ID, req.ParentID); err != nil {
+				return err
+			}
+		}
+		if err := tx.Save(&category).Error; err != nil {
+			return err
+		}
+		if req.ParentID != nil {
+			return updateCategoryPath(tx, &category)
+		}
+		return nil
+	})
+	if errors.Is(err, errInvalidCategoryPlacement) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update category"})
 		return
 	}
@@ -223,7 +276,7 @@ func DeleteCategory(c *gin.Context) {
 	
 	// Check if category has products
 	var productCount int64
-	database.DB.Model(&models.Product{}).Where("category_id = ?", id).Count(&productCount)
+	whereInCategory(database.DB.Model(&models.Product{}), category.ID).Count(&productCount)
 	
 	if productCount > 0 {
 		c.JSON(http.StatusBadRequest, gin.H{
@@ -241,7 +294,8 @@ func DeleteCategory(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"message": "Category deleted successfully"})
 }
 
-// MoveCategory moves a category to a different parent
+// MoveCategory moves a category, with its subtree, to a different parent. Moves under
+// one of its own descendants and beyond the maximum depth are rejected.
 func MoveCategory(c *gin.Context) {
 	id := c.Param("id")
 	var category models.Category
@@ -273,10 +327,37 @@ func MoveCategory(c *gin.Context) {
 		}
 	}
 	
-	// Update parent
+	// Update parent, placing the category among its new siblings when asked
 	category.ParentID = req.NewParentID
 	
-	if err := database.DB.Save(&category).Error; err != nil {
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := checkCategoryPlacement(tx, category.ID, req.NewParentID); err != nil {
+			return err
+		}
+		if err := tx.Save(&category).Error; err != nil {
+			return err
+		}
+		if err := updateCategoryPath(tx, &category); err != nil {
+			return err
+		}
+		if req.BeforeID == nil && req.AfterID == nil {
+			return nil
+		}
+		siblings, err := siblingIDs(tx, req.NewParentID)
+		if err != nil {
+			return err
+		}
+		order, err := models.PositionAmongSiblings(siblings, category.ID, req.BeforeID, req.AfterID)
+		if err != nil {
+			return fmt.Errorf("%w: %v", errInvalidCategoryOrder, err)
+		}
+		return rewriteSortOrder(tx, order)
+	})
+	if errors.Is(err, errInvalidCategoryOrder) || errors.Is(err, errInvalidCategoryPlacement) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to move category"})
 		return
 	}
@@ -287,9 +368,11 @@ func MoveCategory(c *gin.Context) {
 	c.JSON(http.StatusOK, category)
 }
 
-// GetCategoryProducts retrieves all products for a specific category
+// GetCategoryProducts retrieves all products listed in a specific category
+// (?primary_only=true restricts to products whose primary category it is,
+// ?include_descendants=true also lists products of its subcategories)
 func GetCategoryProducts(c *gin.Context) {
-	categoryID := c.Param("category_id")
+	categoryID := c.Param("id")
 	
 	// Verify category exists
 	var category models.Category
@@ -303,8 +386,14 @@ func GetCategoryProducts(c *gin.Context) {
 	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
 	offset := (page - 1) * limit
 	
+	primaryOnly := c.Query("primary_only") == "true"
+	includeDescendants := c.Query("include_descendants") == "true"
+	inCategory := func(query *gorm.DB) *gorm.DB {
+		return whereInCategoryTree(query, category.ID, includeDescendants, primaryOnly)
+	}
+	
 	var products []models.Product
-	query := database.DB.Where("category_id = ?", categoryID).Limit(limit).Offset(offset)
+	query := inCategory(preloadProductRelations(database.DB)).Limit(limit).Offset(offset)
 	
 	if err := query.Find(&products).Error; err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve products"})
@@ -313,7 +402,7 @@ func GetCategoryProducts(c *gin.Context) {
 	
 	// Get total count
 	var total int64
-	database.DB.Model(&models.Product{}).Where("category_id = ?", categoryID).Count(&total)
+	inCategory(database.DB.Model(&models.Product{})).Count(&total)
 	
 	c.JSON(http.StatusOK, gin.H{
 		"category": category,
@@ -324,4 +413,11 @@ func GetCategoryProducts(c *gin.Context) {
 			"total": total,
 		},
 	})
-}
\ No newline at end of file
+}
+
+// categoryCodeTaken reports whether another category already uses the code
+func categoryCodeTaken(code string, exceptID uint) bool {
+	var count int64
+	database.DB.Model(&models.Category{}).Where("code = ? AND id <> ?", code, exceptID).Count(&count)
+	return count > 0
+}
@@ -0,0 +1,292 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+
+	"gaetanjaminon/GoTuto/internal/catalog/database"
+	"gaetanjaminon/GoTuto/internal/catalog/models"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// GetCategoryAttributes retrieves the attribute schema of a category. With
+// ?inherited=true the attributes inherited from its ancestors are included.
+func GetCategoryAttributes(c *gin.Context) {
+	var category models.Category
+	if err := database.DB.First(&category, c.Param("id")).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Category not found"})
+		return
+	}
+
+	var schema models.AttributeSchema
+	var err error
+	if c.Query("inherited") == "true" {
+		schema, err = attributeSchema(database.DB, &category.ID)
+	} else {
+		err = database.DB.Where("category_id = ?", category.ID).Order("sort_order ASC, name ASC").Find(&schema).Error
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve attributes"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"category_id": category.ID,
+		"attributes":  schema,
+	})
+}
+
+// CreateCategoryAttribute adds an attribute to the schema of a category
+func CreateCategoryAttribute(c *gin.Context) {
+	var category models.Category
+	if err := database.DB.First(&category, c.Param("id")).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Category not found"})
+		return
+	}
+
+	var req models.CategoryAttributeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	attribute := models.CategoryAttribute{CategoryID: category.ID}
+	applyCategoryAttributeRequest(&attribute, req)
+
+	// Validate attribute
+	if err := attribute.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Check if the category already defines this attribute
+	var count int64
+	database.DB.Model(&models.CategoryAttribute{}).Where("category_id = ? AND name = ?", category.ID, attribute.Name).Count(&count)
+	if count > 0 {
+		c.JSON(http.StatusConflict, gin.H{"error": "Category already has an attribute with this name"})
+		return
+	}
+
+	if err := database.DB.Create(&attribute).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create attribute"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, attribute)
+}
+
+// UpdateCategoryAttribute replaces the definition of a category attribute. Existing
+// product values are checked against the new definition the next time they are written.
+func UpdateCategoryAttribute(c *gin.Context) {
+	var attribute models.CategoryAttribute
+	if err := database.DB.Where("category_id = ?", c.Param("id")).First(&attribute, c.Param("attribute_id")).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Attribute not found"})
+		return
+	}
+
+	var req models.CategoryAttributeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	applyCategoryAttributeRequest(&attribute, req)
+
+	// Validate attribute
+	if err := attribute.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Check if another attribute of the category has this name
+	var count int64
+	database.DB.Model(&models.CategoryAttribute{}).
+		Where("category_id = ? AND name = ? AND id <> ?", attribute.CategoryID, attribute.Name, attribute.ID).Count(&count)
+	if count > 0 {
+		c.JSON(http.StatusConflict, gin.H{"error": "Category already has an attribute with this name"})
+		return
+	}
+
+	if err := database.DB.Save(&attribute).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update attribute"})
+		return
+	}
+
+	c.JSON(http.StatusOK, attribute)
+}
+
+// DeleteCategoryAttribute removes an attribute from the schema of a category. Values
+// already set on products are kept.
+func DeleteCategoryAttribute(c *gin.Context) {
+	var attribute models.CategoryAttribute
+	if err := database.DB.Where("category_id = ?", c.Param("id")).First(&attribute, c.Param("attribute_id")).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Attribute not found"})
+		return
+	}
+
+	if err := database.DB.Delete(&attribute).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete attribute"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Attribute deleted successfully"})
+}
+
+// applyCategoryAttributeRequest copies the request fields onto the attribute
+func applyCategoryAttributeRequest(attribute *models.CategoryAttribute, req models.CategoryAttributeRequest) {
+	attribute.Name = req.Name
+	attribute.Label = req.Label
+	attribute.Type = req.Type
+	attribute.Unit = req.Unit
+	attribute.AllowedValues = req.AllowedValues
+	attribute.Level = req.Level
+	if attribute.Level == "" {
+		attribute.Level = models.AttributeLevelProduct
+	}
+	attribute.IsRequired = req.IsRequired
+	attribute.IsFilterable = req.IsFilterable
+	attribute.SortOrder = req.SortOrder
+}
+
+// attributeSchema returns the attribute schema of a category, inherited attributes
+// included. Products without a primary category (nil) have no schema.
+func attributeSchema(tx *gorm.DB, categoryID *uint) (models.AttributeSchema, error) {
+	if categoryID == nil {
+		return nil, nil
+	}
+
+	var category models.Category
+	if err := tx.Select("id", "path").First(&category, *categoryID).Error; err != nil {
+		return nil, err
+	}
+	categoryIDs := models.CategoryPathIDs(category.Path)
+	if len(categoryIDs) == 0 {
+		categoryIDs = []uint{category.ID}
+	}
+
+	var attributes []models.CategoryAttribute
+	if err := tx.Where("category_id IN ?", categoryIDs).Find(&attributes).Error; err != nil {
+		return nil, err
+	}
+
+	return models.BuildAttributeSchema(attributes, categoryIDs), nil
+}
+
+// primaryCategoryID returns the primary category of a product, or nil
+func primaryCategoryID(productID uint) *uint {
+	var primary models.ProductCategory
+	if err := database.DB.Where("product_id = ? AND is_primary", productID).First(&primary).Error; err != nil {
+		return nil
+	}
+	return &primary.CategoryID
+}
+
+// checkAttributes validates product or variant attributes against the attribute schema
+// of a category and returns them in canonical form. On failure it writes the error
+// response and returns false.
+func checkAttributes(c *gin.Context, categoryID *uint, attributes models.VariantAttributes, level models.AttributeLevel) (models.VariantAttributes, bool) {
+	schema, err := attributeSchema(database.DB, categoryID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load the category attribute schema"})
+		return nil, false
+	}
+
+	validated, err := schema.ValidateAttributes(attributes, level)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return nil, false
+	}
+	return validated, true
+}
+
+// checkVariantAttributes validates the attributes of all variants of a product against the
+// attribute schema of a category about to become its primary category, and returns the
+// variants with their attributes in canonical form. On failure it writes the error
+// response and returns false.
+func checkVariantAttributes(c *gin.Context, productID uint, categoryID *uint) ([]models.ProductVariant, bool) {
+	schema, err := attributeSchema(database.DB, categoryID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load the category attribute schema"})
+		return nil, false
+	}
+
+	var variants []models.ProductVariant
+	if err := database.DB.Where("product_id = ?", productID).Find(&variants).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve variants"})
+		return nil, false
+	}
+
+	for i := range variants {
+		validated, err := schema.ValidateAttributes(variants[i].Attributes, models.AttributeLevelVariant)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":      "Variant " + variants[i].SKU + ": " + err.Error(),
+				"variant_id": variants[i].ID,
+			})
+			return nil, false
+		}
+		variants[i].Attributes = validated
+	}
+	return variants, true
+}
+
+// saveVariantAttributes stores the attributes of variants checked by checkVariantAttributes
+func saveVariantAttributes(tx *gorm.DB, variants []models.ProductVariant) error {
+	for i := range variants {
+		if err := tx.Model(&variants[i]).Update("attributes", variants[i].Attributes).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// whereProductAttributes keeps the products having, for each filtered attribute, one of
+// the given values, either on the product or on one of its active variants
+func whereProductAttributes(query *gorm.DB, filters map[string][]string) *gorm.DB {
+	names := make([]string, 0, len(filters))
+	for name := range filters {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		query = query.Where(`(products.attributes->>? IN ? OR EXISTS (
+			SELECT 1 FROM product_variants v
+			WHERE v.product_id = products.id AND v.deleted_at IS NULL AND v.is_active
+			AND v.attributes->>? IN ?))`, name, filters[name], name, filters[name])
+	}
+	return query
+}
+
+var errInvalidAttributeFilter = errors.New("invalid attribute filter")
+
+// filterableAttributes checks that the filtered attributes are marked filterable in a
+// category schema and returns the filter values in the canonical form of their type.
+// A value valid for any filterable definition of the name is accepted, in the canonical
+// form of each definition accepting it.
+func filterableAttributes(filters map[string][]string) (map[string][]string, error) {
+	normalized := make(map[string][]string, len(filters))
+	for name, values := range filters {
+		var definitions []models.CategoryAttribute
+		err := database.DB.Where("name = ? AND is_filterable", name).Order("id ASC").Find(&definitions).Error
+		if err != nil {
+			return nil, err
+		}
+		if len(definitions) == 0 {
+			return nil, fmt.Errorf("%w: %s is not filterable", errInvalidAttributeFilter, name)
+		}
+
+		for _, value := range values {
+			canonical, err := models.NormalizeFilterValue(definitions, value)
+			if err != nil {
+				return nil, fmt.Errorf("%w: %v", errInvalidAttributeFilter, err)
+			}
+			normalized[name] = append(normalized[name], canonical...)
+		}
+	}
+	return normalized, nil
+}
@@ -0,0 +1,84 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"gaetanjaminon/GoTuto/internal/catalog/database"
+	"gaetanjaminon/GoTuto/internal/catalog/models"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ReorderCategoryChildren rewrites the sort order of all children of a category at once,
+// following the order of the given child IDs
+func ReorderCategoryChildren(c *gin.Context) {
+	var category models.Category
+	if err := database.DB.First(&category, c.Param("id")).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Category not found"})
+		return
+	}
+
+	var req models.ReorderChildrenRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		currentIDs, err := siblingIDs(tx, &category.ID)
+		if err != nil {
+			return err
+		}
+		if err := req.Validate(currentIDs); err != nil {
+			return fmt.Errorf("%w: %v", errInvalidCategoryOrder, err)
+		}
+		return rewriteSortOrder(tx, req.ChildIDs)
+	})
+	if errors.Is(err, errInvalidCategoryOrder) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to reorder categories"})
+		return
+	}
+
+	var children []models.Category
+	database.DB.Where("parent_id = ?", category.ID).Order("sort_order ASC, name ASC").Find(&children)
+
+	c.JSON(http.StatusOK, gin.H{
+		"category_id": category.ID,
+		"children":    children,
+	})
+}
+
+var errInvalidCategoryOrder = errors.New("invalid category order")
+
+// siblingIDs returns the IDs of the categories under a parent (nil for root categories)
+// in their display order, locking them for the rest of the transaction
+func siblingIDs(tx *gorm.DB, parentID *uint) ([]uint, error) {
+	query := tx.Model(&models.Category{}).Clauses(clause.Locking{Strength: "UPDATE"})
+	if parentID == nil {
+		query = query.Where("parent_id IS NULL")
+	} else {
+		query = query.Where("parent_id = ?", *parentID)
+	}
+
+	var ids []uint
+	err := query.Order("sort_order ASC, name ASC, id ASC").Pluck("id", &ids).Error
+	return ids, err
+}
+
+// rewriteSortOrder numbers the categories 0, 1, 2... in the given order
+func rewriteSortOrder(tx *gorm.DB, ids []uint) error {
+	for position, id := range ids {
+		if err := tx.Model(&models.Category{}).Where("id = ?", id).Update("sort_order", position).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,258 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"gaetanjaminon/GoTuto/internal/catalog/database"
+	"gaetanjaminon/GoTuto/internal/catalog/models"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// GetCategoryTree retrieves the nested category hierarchy in a single recursive query.
+// Optional filters: ?root_id= (subtree of a category), ?max_depth= (0 returns only the
+// roots) and ?active_only=true (inactive categories are left out with their subtree).
+func GetCategoryTree(c *gin.Context) {
+	maxDepth := models.MaxCategoryTreeDepth
+	if value := c.Query("max_depth"); value != "" {
+		depth, err := strconv.Atoi(value)
+		if err != nil || depth < 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "max_depth must be a non-negative integer"})
+			return
+		}
+		if depth < maxDepth {
+			maxDepth = depth
+		}
+	}
+	activeOnly := c.Query("active_only") == "true"
+
+	var args []interface{}
+	rootCondition := "parent_id IS NULL"
+	if rootID := c.Query("root_id"); rootID != "" {
+		var root models.Category
+		if err := database.DB.First(&root, rootID).Error; err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Category not found"})
+			return
+		}
+		rootCondition = "id = ?"
+		args = append(args, root.ID)
+	}
+
+	rootActive, childActive := "", ""
+	if activeOnly {
+		rootActive, childActive = " AND is_active", " AND c.is_active"
+	}
+
+	sql := `WITH RECURSIVE tree AS (
+		SELECT id, name, description, parent_id, is_active, sort_order, 0 AS depth
+		FROM categories
+		WHERE deleted_at IS NULL AND ` + rootCondition + rootActive + `
+		UNION ALL
+		SELECT c.id, c.name, c.description, c.parent_id, c.is_active, c.sort_order, tree.depth + 1
+		FROM categories c
+		JOIN tree ON c.parent_id = tree.id
+		WHERE c.deleted_at IS NULL AND tree.depth < ?` + childActive + `
+	)
+	SELECT * FROM tree ORDER BY depth ASC, sort_order ASC, name ASC`
+	args = append(args, maxDepth)
+
+	var rows []models.CategoryTreeNode
+	if err := database.DB.Raw(sql, args...).Scan(&rows).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve category tree"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"categories": models.BuildCategoryTree(rows),
+		"total":      len(rows),
+	})
+}
+
+// GetCategoryPath retrieves the ancestors of a category, from the root down to the
+// category itself, e.g. for breadcrumbs
+func GetCategoryPath(c *gin.Context) {
+	var category models.Category
+	if err := database.DB.First(&category, c.Param("id")).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Category not found"})
+		return
+	}
+
+	path, err := categoryPath(database.DB, category.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve category path"})
+		return
+	}
+	// The category may have been deleted since it was loaded
+	if len(path) == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Category not found"})
+		return
+	}
+
+	// The full path is built from the parent links, which are then cut so the
+	// response lists every category once
+	fullPath := models.LinkCategoryPath(path).GetFullPath()
+	for i := range path {
+		path[i].Parent = nil
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"category_id": category.ID,
+		"path":        path,
+		"full_path":   fullPath,
+		"depth":       len(path) - 1,
+	})
+}
+
+// categoryPath loads a category and its ancestors ordered from the root to the category.
+// The walk stops at the first repeated category if the parents form a cycle.
+func categoryPath(db *gorm.DB, categoryID uint) ([]models.Category, error) {
+	var path []models.Category
+	err := db.Raw(`WITH RECURSIVE ancestors AS (
+		SELECT categories.*, 0 AS level, ARRAY[id] AS visited
+		FROM categories
+		WHERE id = ? AND deleted_at IS NULL
+		UNION ALL
+		SELECT c.*, ancestors.level + 1, ancestors.visited || c.id
+		FROM categories c
+		JOIN ancestors ON c.id = ancestors.parent_id
+		WHERE c.deleted_at IS NULL AND ancestors.level < ? AND NOT c.id = ANY(ancestors.visited)
+	)
+	SELECT id, name, description, parent_id, is_active, sort_order, path, depth, created_at, updated_at, deleted_at
+	FROM ancestors ORDER BY level DESC`, categoryID, models.MaxCategoryTreeDepth).Scan(&path).Error
+	return path, err
+}
+
+// categoryPlacement loads what is needed to validate placing a category (0 for a new
+// one) under a parent: the parent depth and the descendants of the category
+func categoryPlacement(tx *gorm.DB, categoryID uint, parentID *uint) (models.CategoryPlacement, error) {
+	placement := models.CategoryPlacement{CategoryID: categoryID, ParentID: parentID}
+
+	if parentID != nil {
+		path, err := categoryPath(tx, *parentID)
+		if err != nil {
+			return placement, err
+		}
+		placement.ParentDepth = len(path) - 1
+	}
+
+	if categoryID == 0 {
+		return placement, nil
+	}
+
+	var descendants []struct {
+		ID    uint
+		Depth int
+	}
+	err := tx.Raw(`WITH RECURSIVE descendants AS (
+		SELECT id, 1 AS depth, ARRAY[parent_id, id] AS visited
+		FROM categories
+		WHERE parent_id = ? AND deleted_at IS NULL
+		UNION ALL
+		SELECT c.id, descendants.depth + 1, descendants.visited || c.id
+		FROM categories c
+		JOIN descendants ON c.parent_id = descendants.id
+		WHERE c.deleted_at IS NULL AND NOT c.id = ANY(descendants.visited)
+	)
+	SELECT id, depth FROM descendants`, categoryID).Scan(&descendants).Error
+	if err != nil {
+		return placement, err
+	}
+
+	for _, descendant := range descendants {
+		placement.Descendants = append(placement.Descendants, descendant.ID)
+		if descendant.Depth > placement.SubtreeHeight {
+			placement.SubtreeHeight = descendant.Depth
+		}
+	}
+	return placement, nil
+}
+
+var errInvalidCategoryPlacement = errors.New("invalid category placement")
+
+// checkCategoryPlacement validates placing a category under a parent against the
+// configured hierarchy rules. It first locks the category and the ancestors of the
+// parent until the end of the transaction, so concurrent moves cannot combine into a
+// cycle or a hierarchy deeper than allowed between the check and the update.
+func checkCategoryPlacement(tx *gorm.DB, categoryID uint, parentID *uint) error {
+	var ids []uint
+	if categoryID != 0 {
+		ids = append(ids, categoryID)
+	}
+	if parentID != nil {
+		path, err := categoryPath(tx, *parentID)
+		if err != nil {
+			return err
+		}
+		for _, ancestor := range path {
+			ids = append(ids, ancestor.ID)
+		}
+	}
+	if len(ids) > 0 {
+		var locked []uint
+		err := tx.Model(&models.Category{}).Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id IN ?", ids).Order("id").Pluck("id", &locked).Error
+		if err != nil {
+			return err
+		}
+	}
+
+	placement, err := categoryPlacement(tx, categoryID, parentID)
+	if err != nil {
+		return err
+	}
+	if err := placement.Validate(categorySettings.MaxDepth); err != nil {
+		return fmt.Errorf("%w: %v", errInvalidCategoryPlacement, err)
+	}
+	return nil
+}
+
+// updateCategoryPath sets the materialized path and depth of a category after it was
+// created or moved, rewriting the paths of its whole subtree in the same statement.
+func updateCategoryPath(tx *gorm.DB, category *models.Category) error {
+	parentPath := ""
+	if category.ParentID != nil {
+		var parent models.Category
+		if err := tx.Select("id", "path").First(&parent, *category.ParentID).Error; err != nil {
+			return err
+		}
+		parentPath = parent.Path
+	}
+
+	oldPath, oldDepth := category.Path, category.Depth
+	category.Path = models.BuildCategoryPath(parentPath, category.ID)
+	category.Depth = models.CategoryPathDepth(category.Path)
+
+	if oldPath == "" {
+		return tx.Model(category).UpdateColumns(map[string]interface{}{"path": category.Path, "depth": category.Depth}).Error
+	}
+	if oldPath == category.Path {
+		return nil
+	}
+
+	// oldPath is not empty here, so only the subtree matches the prefix
+	return tx.Exec("UPDATE categories SET path = ? || SUBSTRING(path FROM ?), depth = depth + ? WHERE starts_with(path, ?)",
+		category.Path, len(oldPath)+1, category.Depth-oldDepth, oldPath).Error
+}
+
+// whereInCategoryTree restricts a product query to products linked to the category or,
+// with includeDescendants, to any category of its subtree. primaryOnly only considers the
+// primary category of each product.
+func whereInCategoryTree(query *gorm.DB, categoryID interface{}, includeDescendants, primaryOnly bool) *gorm.DB {
+	if !includeDescendants && !primaryOnly {
+		return whereInCategory(query, categoryID)
+	}
+
+	condition := "product_categories.category_id = ?"
+	if includeDescendants {
+		condition = "product_categories.category_id IN (SELECT sub.id FROM categories sub WHERE sub.deleted_at IS NULL AND starts_with(sub.path, (SELECT root.path FROM categories root WHERE root.id = ? AND root.path <> '')))"
+	}
+	if primaryOnly {
+		condition += " AND product_categories.is_primary"
+	}
+	return query.Where("EXISTS (SELECT 1 FROM product_categories WHERE product_categories.product_id = products.id AND "+condition+")", categoryID)
+}
@@ -0,0 +1,43 @@
+package api
+
+import (
+	"errors"
+	"strings"
+
+	"gaetanjaminon/GoTuto/internal/catalog/config"
+	"gaetanjaminon/GoTuto/internal/catalog/models"
+)
+
+// categorySettings holds the category hierarchy rules, set at startup by Configure
+var categorySettings config.CategoryConfig
+
+// skuSettings holds the pattern and prefix of generated SKUs, set at startup by Configure
+var skuSettings struct {
+	pattern string
+	prefix  string
+}
+
+// Configure sets the configuration the handlers depend on. It must be called before the
+// router starts serving requests, and fails when the category hierarchy asks for circular
+// references or the SKU settings cannot produce SKUs.
+func Configure(cfg *config.CatalogConfig) error {
+	if cfg.Category.AllowCircularRefs {
+		return errors.New("circular category references are not supported")
+	}
+
+	pattern := cfg.Product.SKUPattern
+	if pattern == "" {
+		pattern = models.DefaultSKUPattern
+	}
+	prefix := strings.ToUpper(strings.TrimSpace(cfg.Product.SKUPrefix))
+	if prefix == "" {
+		prefix = models.DefaultSKUPrefix
+	}
+	if err := models.ValidateSKUSettings(pattern, prefix); err != nil {
+		return err
+	}
+
+	categorySettings = cfg.Category
+	skuSettings.pattern, skuSettings.prefix = pattern, prefix
+	return nil
+}
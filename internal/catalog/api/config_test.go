@@ -0,0 +1,27 @@
+package api
+
+import (
+	"testing"
+
+	"gaetanjaminon/GoTuto/internal/catalog/config"
+	"gaetanjaminon/GoTuto/internal/catalog/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConfigure(t *testing.T) {
+	cfg := &config.CatalogConfig{}
+	cfg.Category.MaxDepth = 5
+	cfg.Product.SKUPrefix = " sku "
+	require.NoError(t, Configure(cfg))
+	assert.Equal(t, 5, categorySettings.MaxDepth)
+	assert.Equal(t, "SKU", skuSettings.prefix)
+	assert.Equal(t, models.DefaultSKUPattern, skuSettings.pattern)
+
+	cfg.Category.AllowCircularRefs = true
+	assert.EqualError(t, Configure(cfg), "circular category references are not supported")
+
+	cfg.Category.AllowCircularRefs = false
+	cfg.Product.SKUPattern = "{prefix}-{seq:40}"
+	assert.Error(t, Configure(cfg), "sequence too wide")
+}
@@ -0,0 +1,343 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"gaetanjaminon/GoTuto/internal/catalog/config"
+	"gaetanjaminon/GoTuto/internal/catalog/database"
+	"gaetanjaminon/GoTuto/internal/catalog/models"
+	"gaetanjaminon/GoTuto/internal/catalog/services"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// GetStockLevels retrieves stock levels (?product_id=, ?variant_id=, ?warehouse_id=,
+// ?low_stock=true). Filtered to a product or variant, the response also gives the units
+// available across its active warehouses.
+func GetStockLevels(c *gin.Context) {
+	var levels []models.StockLevel
+
+	query := database.DB.Preload("Warehouse")
+	if productID := c.Query("product_id"); productID != "" {
+		query = query.Where("product_id = ?", productID)
+	}
+	if variantID := c.Query("variant_id"); variantID != "" {
+		query = query.Where("variant_id = ?", variantID)
+	}
+	if warehouseID := c.Query("warehouse_id"); warehouseID != "" {
+		query = query.Where("warehouse_id = ?", warehouseID)
+	}
+	if c.Query("low_stock") == "true" {
+		query = query.Where("low_stock_threshold > 0 AND on_hand - reserved <= low_stock_threshold")
+	}
+
+	if err := query.Order("product_id ASC, variant_id ASC NULLS FIRST, warehouse_id ASC").Find(&levels).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve stock levels"})
+		return
+	}
+
+	total := 0
+	for i := range levels {
+		levels[i].WithAvailable()
+		if warehouse := levels[i].Warehouse; warehouse != nil && warehouse.IsActive {
+			total += levels[i].Available
+		}
+	}
+
+	response := gin.H{"stock_levels": levels}
+	// A total across unrelated products would mean nothing
+	if c.Query("product_id") != "" || c.Query("variant_id") != "" {
+		response["total_available"] = total
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+// UpdateStockLevel updates the low-stock threshold of a stock level
+func UpdateStockLevel(c *gin.Context) {
+	var level models.StockLevel
+
+	if err := database.DB.First(&level, c.Param("id")).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Stock level not found"})
+		return
+	}
+
+	var req models.UpdateStockLevelRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// On-hand and reserved units only change through movements and reservations
+	if err := database.DB.Model(&level).Update("low_stock_threshold", *req.LowStockThreshold).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update stock level"})
+		return
+	}
+
+	c.JSON(http.StatusOK, level.WithAvailable())
+}
+
+// GetStockMovements retrieves stock movements, most recent first, with optional pagination
+// and filters (?stock_level_id=, ?product_id=, ?warehouse_id=, ?type=)
+func GetStockMovements(c *gin.Context) {
+	var movements []models.StockMovement
+
+	// Optional pagination
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	offset := (page - 1) * limit
+
+	query := database.DB.Model(&models.StockMovement{})
+	if stockLevelID := c.Query("stock_level_id"); stockLevelID != "" {
+		query = query.Where("stock_level_id = ?", stockLevelID)
+	}
+	if productID := c.Query("product_id"); productID != "" {
+		query = query.Where("stock_level_id IN (?)", database.DB.Model(&models.StockLevel{}).Select("id").Where("product_id = ?", productID))
+	}
+	if warehouseID := c.Query("warehouse_id"); warehouseID != "" {
+		query = query.Where("stock_level_id IN (?)", database.DB.Model(&models.StockLevel{}).Select("id").Where("warehouse_id = ?", warehouseID))
+	}
+	if movementType := c.Query("type"); movementType != "" {
+		query = query.Where("type = ?", movementType)
+	}
+	query = query.Session(&gorm.Session{}) // Shared by the count and the page query
+
+	// Get total count for pagination
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve stock movements"})
+		return
+	}
+
+	if err := query.Order("created_at DESC, id DESC").Limit(limit).Offset(offset).Find(&movements).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve stock movements"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"movements": movements,
+		"pagination": gin.H{
+			"page":  page,
+			"limit": limit,
+			"total": total,
+		},
+	})
+}
+
+// CreateStockMovement records a receipt, sale, adjustment or return and updates the stock
+func CreateStockMovement(c *gin.Context) {
+	var req models.CreateStockMovementRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	quantity, err := models.SignedStockQuantity(req.Type, req.Quantity)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	product, ok := findStockItem(c, req.StockItemRequest)
+	if !ok {
+		return
+	}
+
+	if req.Type == models.StockMovementSale && !product.CanBeSold() {
+		c.JSON(http.StatusConflict, gin.H{"error": models.ErrProductNotSellable.Error()})
+		return
+	}
+
+	movement := models.StockMovement{
+		Type:      req.Type,
+		Quantity:  quantity,
+		Reference: req.Reference,
+		Note:      req.Note,
+	}
+	var level models.StockLevel
+	err = database.DB.Transaction(func(tx *gorm.DB) error {
+		var err error
+		if level, err = services.LockStockLevel(tx, req.WarehouseID, req.ProductID, req.VariantID); err != nil {
+			return err
+		}
+		return services.ApplyStockMovement(tx, &level, &movement)
+	})
+	if errors.Is(err, models.ErrInsufficientStock) {
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error(), "available": level.AvailableQuantity()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to record stock movement"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"movement":    movement,
+		"stock_level": level.WithAvailable(),
+	})
+}
+
+// GetReservation retrieves a single stock reservation
+func GetReservation(c *gin.Context) {
+	var reservation models.StockReservation
+
+	if err := database.DB.Preload("StockLevel").First(&reservation, c.Param("id")).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Reservation not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, reservation)
+}
+
+// CreateReservation holds available units of a product or variant in a warehouse for a
+// pending order. The reservation is released automatically when it expires.
+func CreateReservation(inventoryCfg config.InventoryConfig) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req models.CreateReservationRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		product, ok := findStockItem(c, req.StockItemRequest)
+		if !ok {
+			return
+		}
+
+		if !product.CanBeSold() {
+			c.JSON(http.StatusConflict, gin.H{"error": models.ErrProductNotSellable.Error()})
+			return
+		}
+
+		ttl := inventoryCfg.ReservationTTL
+		if req.TTLMinutes > 0 {
+			ttl = time.Duration(req.TTLMinutes) * time.Minute
+		}
+		if ttl <= 0 {
+			ttl = 15 * time.Minute
+		}
+
+		reservation := models.StockReservation{
+			Quantity:  req.Quantity,
+			Reference: req.Reference,
+			ExpiresAt: time.Now().Add(ttl),
+		}
+		var level models.StockLevel
+		err := database.DB.Transaction(func(tx *gorm.DB) error {
+			var err error
+			if level, err = services.LockStockLevel(tx, req.WarehouseID, req.ProductID, req.VariantID); err != nil {
+				return err
+			}
+			return services.ReserveStock(tx, &level, &reservation)
+		})
+		if errors.Is(err, models.ErrInsufficientStock) {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error(), "available": level.AvailableQuantity()})
+			return
+		}
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to reserve stock"})
+			return
+		}
+
+		reservation.StockLevel = level.WithAvailable()
+		c.JSON(http.StatusCreated, reservation)
+	}
+}
+
+// ReleaseReservation cancels an active reservation, making its units available again
+func ReleaseReservation(c *gin.Context) {
+	var reservation models.StockReservation
+
+	if err := database.DB.First(&reservation, c.Param("id")).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Reservation not found"})
+		return
+	}
+
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		return services.ReleaseReservation(tx, &reservation, models.ReservationReleased)
+	})
+	if errors.Is(err, models.ErrReservationNotActive) {
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error(), "status": reservation.Status})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to release reservation"})
+		return
+	}
+
+	c.JSON(http.StatusOK, reservation)
+}
+
+// FulfillReservation turns an active reservation into a sale of the reserved units
+func FulfillReservation(c *gin.Context) {
+	var reservation models.StockReservation
+
+	if err := database.DB.First(&reservation, c.Param("id")).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Reservation not found"})
+		return
+	}
+
+	var req struct {
+		Reference string `json:"reference"` // Defaults to the reservation reference
+	}
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	var movement models.StockMovement
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		var err error
+		movement, err = services.FulfillReservation(tx, &reservation, req.Reference)
+		return err
+	})
+	if errors.Is(err, models.ErrReservationNotActive) {
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error(), "status": reservation.Status, "expires_at": reservation.ExpiresAt})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fulfill reservation"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"reservation": reservation,
+		"movement":    movement,
+	})
+}
+
+// findStockItem checks that the warehouse of a stock request is active and that the
+// variant, if any, belongs to the product. It writes the error response on failure.
+func findStockItem(c *gin.Context, req models.StockItemRequest) (models.Product, bool) {
+	var warehouse models.Warehouse
+	if err := database.DB.First(&warehouse, req.WarehouseID).Error; err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid warehouse ID"})
+		return models.Product{}, false
+	}
+	if !warehouse.IsActive {
+		c.JSON(http.StatusConflict, gin.H{"error": "Warehouse is not active"})
+		return models.Product{}, false
+	}
+
+	var product models.Product
+	if err := database.DB.First(&product, req.ProductID).Error; err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid product ID"})
+		return product, false
+	}
+
+	if req.VariantID != nil {
+		var count int64
+		database.DB.Model(&models.ProductVariant{}).Where("id = ? AND product_id = ?", *req.VariantID, product.ID).Count(&count)
+		if count == 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid variant ID"})
+			return product, false
+		}
+	}
+
+	return product, true
+}
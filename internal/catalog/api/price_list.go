@@ -0,0 +1,355 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"gaetanjaminon/GoTuto/internal/catalog/database"
+	"gaetanjaminon/GoTuto/internal/catalog/models"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// GetPriceLists retrieves all price lists with optional pagination and filters
+func GetPriceLists(c *gin.Context) {
+	var priceLists []models.PriceList
+
+	// Optional pagination
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	offset := (page - 1) * limit
+
+	// Optional filters
+	isActive := c.Query("is_active")
+	currency := c.Query("currency")
+
+	query := database.DB.Model(&models.PriceList{})
+	if isActive != "" {
+		query = query.Where("is_active = ?", isActive == "true")
+	}
+	if currency != "" {
+		query = query.Where("currency = ?", strings.ToUpper(currency))
+	}
+	query = query.Session(&gorm.Session{}) // Shared by the count and the page query
+
+	// Get total count for pagination
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve price lists"})
+		return
+	}
+
+	if err := query.Order("priority DESC, code ASC").Limit(limit).Offset(offset).Find(&priceLists).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve price lists"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"price_lists": priceLists,
+		"pagination": gin.H{
+			"page":  page,
+			"limit": limit,
+			"total": total,
+		},
+	})
+}
+
+// GetPriceList retrieves a single price list with its prices
+func GetPriceList(c *gin.Context) {
+	var priceList models.PriceList
+
+	err := database.DB.Preload("Items", func(tx *gorm.DB) *gorm.DB {
+		return tx.Order("product_id ASC, variant_id ASC NULLS FIRST, min_quantity ASC")
+	}).First(&priceList, c.Param("id")).Error
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Price list not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, priceList)
+}
+
+// CreatePriceList creates a new price list
+func CreatePriceList(c *gin.Context) {
+	var req models.CreatePriceListRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	priceList := models.PriceList{
+		Code:        req.Code,
+		Name:        req.Name,
+		Description: req.Description,
+		Currency:    strings.ToUpper(req.Currency),
+		Priority:    req.Priority,
+		ValidFrom:   req.ValidFrom,
+		ValidTo:     req.ValidTo,
+		IsActive:    true,
+	}
+	if priceList.Currency == "" {
+		priceList.Currency = "USD"
+	}
+	if req.IsActive != nil {
+		priceList.IsActive = *req.IsActive
+	}
+
+	// Validate price list
+	if err := priceList.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Check if code already exists (soft-deleted price lists keep their code)
+	var count int64
+	database.DB.Unscoped().Model(&models.PriceList{}).Where("code = ?", priceList.Code).Count(&count)
+	if count > 0 {
+		c.JSON(http.StatusConflict, gin.H{"error": "Price list with this code already exists"})
+		return
+	}
+
+	if err := database.DB.Create(&priceList).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create price list"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, priceList)
+}
+
+// UpdatePriceList updates an existing price list
+func UpdatePriceList(c *gin.Context) {
+	var priceList models.PriceList
+
+	if err := database.DB.First(&priceList, c.Param("id")).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Price list not found"})
+		return
+	}
+
+	var req models.UpdatePriceListRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Update only provided fields
+	if req.Name != "" {
+		priceList.Name = req.Name
+	}
+	if req.Description != "" {
+		priceList.Description = req.Description
+	}
+	if req.Currency != "" {
+		priceList.Currency = strings.ToUpper(req.Currency)
+	}
+	if req.Priority != nil {
+		priceList.Priority = *req.Priority
+	}
+	if req.ValidFrom != nil {
+		priceList.ValidFrom = req.ValidFrom
+	}
+	if req.ValidTo != nil {
+		priceList.ValidTo = req.ValidTo
+	}
+	if req.IsActive != nil {
+		priceList.IsActive = *req.IsActive
+	}
+
+	// Validate price list
+	if err := priceList.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := database.DB.Save(&priceList).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update price list"})
+		return
+	}
+
+	c.JSON(http.StatusOK, priceList)
+}
+
+// DeletePriceList soft deletes a price list
+func DeletePriceList(c *gin.Context) {
+	var priceList models.PriceList
+
+	if err := database.DB.First(&priceList, c.Param("id")).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Price list not found"})
+		return
+	}
+
+	if err := database.DB.Delete(&priceList).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete price list"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Price list deleted successfully"})
+}
+
+// SetPriceListItem sets the price of a product or variant in a price list for a quantity
+// tier, replacing the existing price of that tier
+func SetPriceListItem(c *gin.Context) {
+	var priceList models.PriceList
+	if err := database.DB.First(&priceList, c.Param("id")).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Price list not found"})
+		return
+	}
+
+	var req models.PriceListItemRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	var product models.Product
+	if err := database.DB.First(&product, req.ProductID).Error; err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Product not found"})
+		return
+	}
+	if req.VariantID != nil {
+		var variant models.ProductVariant
+		if err := database.DB.Where("product_id = ?", product.ID).First(&variant, *req.VariantID).Error; err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Variant not found for this product"})
+			return
+		}
+	}
+
+	item := models.PriceListItem{
+		PriceListID: priceList.ID,
+		ProductID:   product.ID,
+		VariantID:   req.VariantID,
+		MinQuantity: req.MinQuantity,
+		Price:       *req.Price,
+	}
+	if item.MinQuantity == 0 {
+		item.MinQuantity = 1
+	}
+
+	// Validate item
+	if err := item.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Replace the price of the same tier if there is one
+	var existing models.PriceListItem
+	query := database.DB.Where("price_list_id = ? AND product_id = ? AND min_quantity = ?", item.PriceListID, item.ProductID, item.MinQuantity)
+	if item.VariantID == nil {
+		query = query.Where("variant_id IS NULL")
+	} else {
+		query = query.Where("variant_id = ?", *item.VariantID)
+	}
+
+	status := http.StatusCreated
+	if err := query.First(&existing).Error; err == nil {
+		item.ID = existing.ID
+		item.CreatedAt = existing.CreatedAt
+		status = http.StatusOK
+	}
+
+	if err := database.DB.Save(&item).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to set price"})
+		return
+	}
+
+	c.JSON(status, item)
+}
+
+// DeletePriceListItem removes a price from a price list
+func DeletePriceListItem(c *gin.Context) {
+	var item models.PriceListItem
+	if err := database.DB.Where("price_list_id = ?", c.Param("id")).First(&item, c.Param("item_id")).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Price not found"})
+		return
+	}
+
+	if err := database.DB.Delete(&item).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete price"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Price deleted successfully"})
+}
+
+// ResolveProductPrice returns the effective price of a product for a quantity
+// (?quantity=, default 1), optionally for a variant (?variant_id=) and price lists
+// (?price_list=WHOLESALE,PROMO) at a given time (?at=RFC 3339, default now)
+func ResolveProductPrice(c *gin.Context) {
+	var product models.Product
+	if err := database.DB.First(&product, c.Param("id")).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Product not found"})
+		return
+	}
+
+	if !product.CanBeSold() {
+		c.JSON(http.StatusConflict, gin.H{"error": models.ErrProductNotSellable.Error(), "status": product.Status})
+		return
+	}
+
+	quantity, err := strconv.Atoi(c.DefaultQuery("quantity", "1"))
+	if err != nil || quantity < 1 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "quantity must be a positive integer"})
+		return
+	}
+
+	at := time.Now()
+	if value := c.Query("at"); value != "" {
+		if at, err = time.Parse(time.RFC3339, value); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "at must be an RFC 3339 time"})
+			return
+		}
+	}
+
+	var variant *models.ProductVariant
+	if variantID := c.Query("variant_id"); variantID != "" {
+		variant = &models.ProductVariant{}
+		if err := database.DB.Where("product_id = ?", product.ID).First(variant, variantID).Error; err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Variant not found"})
+			return
+		}
+		if !variant.IsActive {
+			c.JSON(http.StatusConflict, gin.H{"error": "Variant is not active"})
+			return
+		}
+	}
+
+	var priceLists []models.PriceList
+	if codes := splitPriceListCodes(c.Query("price_list")); len(codes) > 0 {
+		err := database.DB.Preload("Items", "product_id = ?", product.ID).Where("code IN ?", codes).Find(&priceLists).Error
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve price lists"})
+			return
+		}
+		if len(priceLists) != len(codes) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Price list not found"})
+			return
+		}
+	}
+
+	c.JSON(http.StatusOK, models.ResolvePrice(product, variant, quantity, priceLists, at))
+}
+
+// splitPriceListCodes parses a comma-separated list of price list codes
+func splitPriceListCodes(value string) []string {
+	var codes []string
+	for _, code := range strings.Split(value, ",") {
+		code = strings.ToUpper(strings.TrimSpace(code))
+		if code != "" && !containsString(codes, code) {
+			codes = append(codes, code)
+		}
+	}
+	return codes
+}
+
+// containsString reports whether value is one of values
+func containsString(values []string, value string) bool {
+	for _, candidate := range values {
+		if candidate == value {
+			return true
+		}
+	}
+	return false
+}
@@ -1,13 +1,20 @@
 package api
 
 import (
+	"errors"
+	"fmt"
 	"net/http"
 	"strconv"
+	"strings"
+	"time"
 	
 	"gaetanjaminon/GoTuto/internal/catalog/database"
 	"gaetanjaminon/GoTuto/internal/catalog/models"
+	"gaetanjaminon/GoTuto/internal/catalog/services"
+	"gaetanjaminon/GoTuto/internal/catalog/storage"
 	
 	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
 )
 
 // GetProducts retrieves all products with optional pagination and filters
@@ -20,23 +27,59 @@ func GetProducts(c *gin.Context) {
 	offset := (page - 1) * limit
 	
 	// Optional filters
-	search := c.Query("search")
+	search := strings.TrimSpace(c.Query("search"))
 	categoryID := c.Query("category_id")
+	includeDescendants := c.Query("include_descendants") == "true"
+	brandID := c.Query("brand_id")
+	status := c.Query("status")
 	isActive := c.Query("is_active")
 	
-	query := database.DB.Preload("Category").Limit(limit).Offset(offset)
+	query := preloadProductRelations(database.DB).Limit(limit).Offset(offset)
 	
+	// Full-text search, ranked by relevance with highlighted snippets
 	if search != "" {
-		query = query.Where("name ILIKE ? OR description ILIKE ? OR sku ILIKE ?", 
-			"%"+search+"%", "%"+search+"%", "%"+search+"%")
+		query = selectProductSearchRank(whereProductSearch(query, search), search)
 	}
 	
 	if categoryID != "" {
-		query = query.Where("category_id = ?", categoryID)
+		query = whereInCategoryTree(query, categoryID, includeDescendants, false)
 	}
 	
+	if brandID != "" {
+		query = query.Where("brand_id = ?", brandID)
+	}
+	
+	statuses, err := parseProductStatuses(status)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if len(statuses) > 0 {
+		query = query.Where("status IN ?", statuses)
+	}
+	
+	// is_active is kept for compatibility: active means status active
 	if isActive != "" {
-		query = query.Where("is_active = ?", isActive == "true")
+		query = whereProductActive(query, isActive == "true")
+	}
+	
+	// Filterable attributes match the product or one of its variants (attr[color]=red,blue)
+	attributes, err := models.ParseAttributeFilters(c.Request.URL.Query())
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if len(attributes) > 0 {
+		attributes, err = filterableAttributes(attributes)
+		if errors.Is(err, errInvalidAttributeFilter) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve products"})
+			return
+		}
+		query = whereProductAttributes(query, attributes)
 	}
 	
 	if err := query.Find(&products).Error; err != nil {
@@ -48,14 +91,22 @@ func GetProducts(c *gin.Context) {
 	var total int64
 	countQuery := database.DB.Model(&models.Product{})
 	if search != "" {
-		countQuery = countQuery.Where("name ILIKE ? OR description ILIKE ? OR sku ILIKE ?", 
-			"%"+search+"%", "%"+search+"%", "%"+search+"%")
+		countQuery = whereProductSearch(countQuery, search)
 	}
 	if categoryID != "" {
-		countQuery = countQuery.Where("category_id = ?", categoryID)
+		countQuery = whereInCategoryTree(countQuery, categoryID, includeDescendants, false)
+	}
+	if brandID != "" {
+		countQuery = countQuery.Where("brand_id = ?", brandID)
+	}
+	if len(statuses) > 0 {
+		countQuery = countQuery.Where("status IN ?", statuses)
 	}
 	if isActive != "" {
-		countQuery = countQuery.Where("is_active = ?", isActive == "true")
+		countQuery = whereProductActive(countQuery, isActive == "true")
+	}
+	if len(attributes) > 0 {
+		countQuery = whereProductAttributes(countQuery, attributes)
 	}
 	countQuery.Count(&total)
 	
@@ -74,15 +125,20 @@ func GetProduct(c *gin.Context) {
 	id := c.Param("id")
 	var product models.Product
 	
-	if err := database.DB.Preload("Category").First(&product, id).Error; err != nil {
+	if err := preloadProductRelations(database.DB).Preload("Variants").First(&product, id).Error; err != nil {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Product not found"})
 		return
 	}
 	
+	for i := range product.Variants {
+		product.Variants[i].WithEffectivePrice(product.Price)
+	}
+	
 	c.JSON(http.StatusOK, product)
 }
 
-// CreateProduct creates a new product
+// CreateProduct creates a new product. Products created without a SKU get one generated
+// from the configured SKU pattern.
 func CreateProduct(c *gin.Context) {
 	var req models.CreateProductRequest
 	
@@ -97,29 +153,80 @@ func CreateProduct(c *gin.Context) {
 		return
 	}
 	
-	// Check if SKU already exists
-	var existingProduct models.Product
-	if err := database.DB.Where("sku = ?", req.SKU).First(&existingProduct).Error; err == nil {
-		c.JSON(http.StatusConflict, gin.H{"error": "Product with this SKU already exists"})
+	// Check if SKU already exists (products and variants share SKUs)
+	if req.SKU != "" {
+		owner, err := skuOwner(req.SKU, 0, 0)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to check SKU"})
+			return
+		}
+		if owner != "" {
+			c.JSON(http.StatusConflict, gin.H{"error": "SKU is already used by a " + owner})
+			return
+		}
+	}
+	
+	// Barcodes identify one product or variant
+	if req.GTIN != "" {
+		owner, err := gtinOwner(req.GTIN, 0, 0)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to check GTIN"})
+			return
+		}
+		if owner != "" {
+			c.JSON(http.StatusConflict, gin.H{"error": "GTIN is already used by a " + owner})
+			return
+		}
+	}
+	
+	// Verify categories exist if provided; category_id is the primary one
+	categoryLinks, err := models.BuildProductCategories(0, req.CategoryIDs, req.CategoryID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err := checkCategoriesExist(categoryLinks); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 	
-	// Verify category exists if provided
-	if req.CategoryID != nil {
+	// Attributes must follow the schema of the primary category (listed first)
+	var categoryID *uint
+	if len(categoryLinks) > 0 {
+		categoryID = &categoryLinks[0].CategoryID
+	}
+	attributes, ok := checkAttributes(c, categoryID, req.Attributes, models.AttributeLevelProduct)
+	if !ok {
+		return
+	}
+	
+	// Generated SKUs include the code of the primary category
+	categoryCode := models.CategorySKUCode(nil)
+	if req.SKU == "" && categoryID != nil {
 		var category models.Category
-		if err := database.DB.First(&category, *req.CategoryID).Error; err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "Category not found"})
+		if err := database.DB.First(&category, *categoryID).Error; err == nil {
+			categoryCode = models.CategorySKUCode(&category)
+		}
+	}
+	
+	// Verify brand exists and is active if provided
+	if req.BrandID != nil {
+		if err := checkProductBrand(*req.BrandID); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 			return
 		}
 	}
 	
 	product := models.Product{
 		SKU:         req.SKU,
+		GTIN:        req.GTIN,
 		Name:        req.Name,
 		Description: req.Description,
 		Price:       req.Price,
 		Currency:    req.Currency,
-		CategoryID:  req.CategoryID,
+		BrandID:     req.BrandID,
+		Status:      req.Status,
+		Attributes:  attributes,
 	}
 	
 	// Set default currency if not provided
@@ -127,18 +234,43 @@ func CreateProduct(c *gin.Context) {
 		product.Currency = "USD"
 	}
 	
-	// Set is_active if provided
-	if req.IsActive != nil {
-		product.IsActive = *req.IsActive
+	// New products are active unless created as draft or inactive
+	if product.Status == "" {
+		product.Status = models.ProductStatusActive
 	}
 	
-	if err := database.DB.Create(&product).Error; err != nil {
+	err = database.DB.Transaction(func(tx *gorm.DB) error {
+		// The sequence number is allocated in the transaction, so a failed creation
+		// gives it back
+		if product.SKU == "" {
+			sku, err := services.GenerateSKU(tx, skuSettings.pattern, models.SKUParts{Prefix: skuSettings.prefix, CategoryCode: categoryCode})
+			if err != nil {
+				return err
+			}
+			product.SKU = sku
+		}
+		if err := tx.Create(&product).Error; err != nil {
+			return err
+		}
+		if _, err := services.RecordPriceChange(tx, product.ID, product.Price, product.Currency, product.CreatedAt, "Initial price"); err != nil {
+			return err
+		}
+		for i := range categoryLinks {
+			categoryLinks[i].ProductID = product.ID
+		}
+		return replaceProductCategories(tx, product.ID, categoryLinks)
+	})
+	if errors.Is(err, gorm.ErrDuplicatedKey) {
+		c.JSON(http.StatusConflict, gin.H{"error": productCodeTaken})
+		return
+	}
+	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create product"})
 		return
 	}
 	
-	// Load category data for response
-	database.DB.Preload("Category").First(&product, product.ID)
+	// Load category and brand data for response
+	preloadProductRelations(database.DB).First(&product, product.ID)
 	
 	c.JSON(http.StatusCreated, product)
 }
@@ -165,16 +297,100 @@ func UpdateProduct(c *gin.Context) {
 		return
 	}
 	
-	// Verify category exists if provided
-	if req.CategoryID != nil {
-		var category models.Category
-		if err := database.DB.First(&category, *req.CategoryID).Error; err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "Category not found"})
+	// Status changes must follow the product lifecycle
+	if req.Status != "" {
+		if err := product.TransitionTo(req.Status); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":               err.Error(),
+				"allowed_transitions": product.AllowedTransitions(),
+			})
+			return
+		}
+	}
+	
+	// Verify categories exist if provided. With category_ids the whole set is replaced,
+	// keeping the current primary category unless category_id names another one.
+	var categoryLinks []models.ProductCategory
+	if req.CategoryIDs != nil {
+		primaryID := req.CategoryID
+		if primaryID == nil {
+			var current models.ProductCategory
+			if err := database.DB.Where("product_id = ? AND is_primary", product.ID).First(&current).Error; err == nil {
+				for _, categoryID := range req.CategoryIDs {
+					if categoryID == current.CategoryID {
+						primaryID = &current.CategoryID
+					}
+				}
+			}
+		}
+		links, err := models.BuildProductCategories(product.ID, req.CategoryIDs, primaryID)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		categoryLinks = links
+	} else if req.CategoryID != nil {
+		categoryLinks = []models.ProductCategory{{ProductID: product.ID, CategoryID: *req.CategoryID, IsPrimary: true}}
+	}
+	if err := checkCategoriesExist(categoryLinks); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	
+	// Attributes are checked when replaced or when the primary category changes
+	categoryID := primaryCategoryID(product.ID)
+	primaryChanged := false
+	for _, link := range categoryLinks {
+		if link.IsPrimary && (categoryID == nil || *categoryID != link.CategoryID) {
+			categoryID = &link.CategoryID
+			primaryChanged = true
+		}
+	}
+	if req.Attributes != nil || primaryChanged {
+		attributes := product.Attributes
+		if req.Attributes != nil {
+			attributes = req.Attributes
+		}
+		validated, ok := checkAttributes(c, categoryID, attributes, models.AttributeLevelProduct)
+		if !ok {
+			return
+		}
+		product.Attributes = validated
+	}
+	
+	// Variant attributes must follow the schema of a new primary category too
+	var variants []models.ProductVariant
+	if primaryChanged {
+		checked, ok := checkVariantAttributes(c, product.ID, categoryID)
+		if !ok {
+			return
+		}
+		variants = checked
+	}
+	
+	// Barcodes identify one product or variant
+	if req.GTIN != nil && *req.GTIN != "" {
+		owner, err := gtinOwner(*req.GTIN, product.ID, 0)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to check GTIN"})
+			return
+		}
+		if owner != "" {
+			c.JSON(http.StatusConflict, gin.H{"error": "GTIN is already used by a " + owner})
+			return
+		}
+	}
+	
+	// Verify brand exists and is active if provided (0 removes the brand)
+	if req.BrandID != nil && *req.BrandID != 0 {
+		if err := checkProductBrand(*req.BrandID); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 			return
 		}
 	}
 	
 	// Update only provided fields
+	previousPrice, previousCurrency := product.Price, product.Currency
 	if req.Name != "" {
 		product.Name = req.Name
 	}
@@ -187,26 +403,71 @@ func UpdateProduct(c *gin.Context) {
 	if req.Currency != "" {
 		product.Currency = req.Currency
 	}
-	if req.CategoryID != nil {
-		product.CategoryID = req.CategoryID
+	if req.GTIN != nil {
+		product.GTIN = *req.GTIN
 	}
-	if req.IsActive != nil {
-		product.IsActive = *req.IsActive
+	if req.BrandID != nil {
+		if *req.BrandID == 0 {
+			product.BrandID = nil
+		} else {
+			product.BrandID = req.BrandID
+		}
+		product.Brand = nil
+	}
+	
+	// Variant price adjustments must not make a variant price negative
+	if product.Price != previousPrice {
+		if variantID, ok := negativeVariantPrice(product.ID, product.Price); ok {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":      "Price would make a variant price negative",
+				"variant_id": variantID,
+			})
+			return
+		}
 	}
 	
-	if err := database.DB.Save(&product).Error; err != nil {
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(&product).Error; err != nil {
+			return err
+		}
+		if err := saveVariantAttributes(tx, variants); err != nil {
+			return err
+		}
+		// Every price change is kept in the price history
+		if product.Price != previousPrice || product.Currency != previousCurrency {
+			if _, err := services.RecordPriceChange(tx, product.ID, product.Price, product.Currency, time.Now(), req.PriceChangeReason); err != nil {
+				return err
+			}
+		}
+		switch {
+		case req.CategoryIDs != nil:
+			return replaceProductCategories(tx, product.ID, categoryLinks)
+		case req.CategoryID != nil:
+			return setPrimaryCategory(tx, product.ID, *req.CategoryID)
+		}
+		return nil
+	})
+	if errors.Is(err, gorm.ErrDuplicatedKey) {
+		c.JSON(http.StatusConflict, gin.H{"error": productCodeTaken})
+		return
+	}
+	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update product"})
 		return
 	}
 	
-	// Load category data for response
-	database.DB.Preload("Category").First(&product, product.ID)
+	// Load category and brand data for response
+	preloadProductRelations(database.DB).First(&product, product.ID)
 	
 	c.JSON(http.StatusOK, product)
 }
 
-// DeleteProduct soft deletes a product
-func DeleteProduct(c *gin.Context) {
+// DeleteProduct soft deletes a product. Its images are deleted with it, files included.
+func DeleteProduct(store storage.Storage) gin.HandlerFunc {
+	return func(c *gin.Context) { deleteProduct(c, store) }
+}
+
+func deleteProduct(c *gin.Context, store storage.Storage) {
 	id := c.Param("id")
 	var product models.Product
 	
@@ -215,10 +476,68 @@ func DeleteProduct(c *gin.Context) {
 		return
 	}
 	
-	if err := database.DB.Delete(&product).Error; err != nil {
+	// Variants and images are deleted with their product
+	var images []models.ProductImage
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("product_id = ?", product.ID).Delete(&models.ProductVariant{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("product_id = ?", product.ID).Find(&images).Error; err != nil {
+			return err
+		}
+		if len(images) > 0 {
+			if err := tx.Delete(&images).Error; err != nil {
+				return err
+			}
+		}
+		return tx.Delete(&product).Error
+	})
+	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete product"})
 		return
 	}
 	
+	// The rows are gone, so leftover files are only logged
+	for _, image := range images {
+		deleteMedia(store, c, image)
+	}
+	
 	c.JSON(http.StatusOK, gin.H{"message": "Product deleted successfully"})
+}
+
+// parseProductStatuses parses a comma-separated status filter such as "active,draft"
+func parseProductStatuses(value string) ([]models.ProductStatus, error) {
+	var statuses []models.ProductStatus
+	for _, part := range strings.Split(value, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		status := models.ProductStatus(strings.ToLower(part))
+		if !models.IsValidProductStatus(status) {
+			return nil, fmt.Errorf("invalid product status: %s", part)
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses, nil
+}
+
+// whereProductActive restricts a product query to active or non-active products
+func whereProductActive(query *gorm.DB, active bool) *gorm.DB {
+	if active {
+		return query.Where("status = ?", models.ProductStatusActive)
+	}
+	return query.Where("status <> ?", models.ProductStatusActive)
+}
+
+// checkProductBrand verifies that a brand can be assigned to a product
+func checkProductBrand(brandID uint) error {
+	var brand models.Brand
+	if err := database.DB.First(&brand, brandID).Error; err != nil {
+		return fmt.Errorf("Brand not found")
+	}
+	if !brand.IsActive {
+		return fmt.Errorf("Brand is not active")
+	}
+	return nil
 }
\ No newline at end of file
@@ -0,0 +1,133 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+
+	"gaetanjaminon/GoTuto/internal/catalog/database"
+	"gaetanjaminon/GoTuto/internal/catalog/models"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// SetProductCategories replaces the categories of a product and sets its primary category
+func SetProductCategories(c *gin.Context) {
+	id := c.Param("id")
+	var product models.Product
+
+	if err := database.DB.First(&product, id).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Product not found"})
+		return
+	}
+
+	var req models.SetProductCategoriesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	links, err := models.BuildProductCategories(product.ID, req.CategoryIDs, req.PrimaryCategoryID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err := checkCategoriesExist(links); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// The product and variant attributes must follow the schema of the new primary category
+	var categoryID *uint
+	if len(links) > 0 {
+		categoryID = &links[0].CategoryID
+	}
+	attributes, ok := checkAttributes(c, categoryID, product.Attributes, models.AttributeLevelProduct)
+	if !ok {
+		return
+	}
+	variants, ok := checkVariantAttributes(c, product.ID, categoryID)
+	if !ok {
+		return
+	}
+
+	if err := database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&product).Update("attributes", attributes).Error; err != nil {
+			return err
+		}
+		if err := saveVariantAttributes(tx, variants); err != nil {
+			return err
+		}
+		return replaceProductCategories(tx, product.ID, links)
+	}); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update product categories"})
+		return
+	}
+
+	// Load updated data for response
+	preloadProductRelations(database.DB).First(&product, product.ID)
+
+	c.JSON(http.StatusOK, product)
+}
+
+// preloadProductRelations preloads the categories (primary first) and brand of products
+func preloadProductRelations(db *gorm.DB) *gorm.DB {
+	return db.
+		Preload("Categories", func(tx *gorm.DB) *gorm.DB {
+			return tx.Order("is_primary DESC, category_id ASC")
+		}).
+		Preload("Categories.Category").
+		Preload("Brand")
+}
+
+// whereInCategory restricts a product query to products linked to the category
+func whereInCategory(query *gorm.DB, categoryID interface{}) *gorm.DB {
+	return query.Where("EXISTS (SELECT 1 FROM product_categories WHERE product_categories.product_id = products.id AND product_categories.category_id = ?)", categoryID)
+}
+
+// checkCategoriesExist verifies that every linked category exists
+func checkCategoriesExist(links []models.ProductCategory) error {
+	if len(links) == 0 {
+		return nil
+	}
+
+	ids := make([]uint, len(links))
+	for i, link := range links {
+		ids[i] = link.CategoryID
+	}
+
+	var count int64
+	database.DB.Model(&models.Category{}).Where("id IN ?", ids).Count(&count)
+	if int(count) != len(ids) {
+		return fmt.Errorf("Category not found")
+	}
+	return nil
+}
+
+// replaceProductCategories replaces all category links of a product
+func replaceProductCategories(tx *gorm.DB, productID uint, links []models.ProductCategory) error {
+	if err := tx.Where("product_id = ?", productID).Delete(&models.ProductCategory{}).Error; err != nil {
+		return err
+	}
+	if len(links) == 0 {
+		return nil
+	}
+	return tx.Create(&links).Error
+}
+
+// setPrimaryCategory makes the category primary for the product, linking it if needed.
+// The other links of the product are demoted.
+func setPrimaryCategory(tx *gorm.DB, productID, categoryID uint) error {
+	if err := tx.Model(&models.ProductCategory{}).
+		Where("product_id = ? AND category_id <> ? AND is_primary", productID, categoryID).
+		Update("is_primary", false).Error; err != nil {
+		return err
+	}
+
+	result := tx.Model(&models.ProductCategory{}).Where("product_id = ? AND category_id = ?", productID, categoryID).Update("is_primary", true)
+	if result.Error != nil || result.RowsAffected > 0 {
+		return result.Error
+	}
+
+	return tx.Create(&models.ProductCategory{ProductID: productID, CategoryID: categoryID, IsPrimary: true}).Error
+}
@@ -0,0 +1,272 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"gaetanjaminon/GoTuto/internal/catalog/database"
+	"gaetanjaminon/GoTuto/internal/catalog/models"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// SearchProducts searches products for the storefront and returns facet buckets with
+// counts (brands, categories, price ranges, attributes) in the same response.
+// Only active products are searched unless ?status= says otherwise.
+func SearchProducts(c *gin.Context) {
+	filters, err := models.ParseProductSearchFilters(c.Request.URL.Query())
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Attribute filters are checked and normalized as in GetProducts
+	if len(filters.Attributes) > 0 {
+		filters.Attributes, err = filterableAttributes(filters.Attributes)
+		if errors.Is(err, errInvalidAttributeFilter) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to search products"})
+			return
+		}
+	}
+
+	statuses, err := parseProductStatuses(c.DefaultQuery("status", string(models.ProductStatusActive)))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Optional pagination
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	offset := (page - 1) * limit
+
+	search := productSearch{filters: filters, statuses: statuses}
+
+	query := search.apply(preloadProductRelations(database.DB), "").Limit(limit).Offset(offset)
+	if filters.Query != "" {
+		query = selectProductSearchRank(query, filters.Query)
+	} else {
+		query = query.Order("products.name ASC, products.id ASC")
+	}
+
+	var products []models.Product
+	if err := query.Find(&products).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to search products"})
+		return
+	}
+
+	var total int64
+	search.apply(database.DB.Model(&models.Product{}), "").Count(&total)
+
+	facets, err := search.facets()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to compute facets"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"products": products,
+		"facets":   facets,
+		"filters":  filters,
+		"pagination": gin.H{
+			"page":  page,
+			"limit": limit,
+			"total": total,
+		},
+	})
+}
+
+// productSearch applies storefront search filters to product queries
+type productSearch struct {
+	filters  models.ProductSearchFilters
+	statuses []models.ProductStatus
+}
+
+// apply restricts a product query to the search filters, leaving out the filter of the
+// facet named by except ("" applies them all)
+func (s productSearch) apply(query *gorm.DB, except string) *gorm.DB {
+	if len(s.statuses) > 0 {
+		query = query.Where("products.status IN ?", s.statuses)
+	}
+
+	if s.filters.Query != "" {
+		query = whereProductSearch(query, s.filters.Query)
+	}
+
+	if except != models.FacetBrand && len(s.filters.BrandIDs) > 0 {
+		query = query.Where("products.brand_id IN ?", s.filters.BrandIDs)
+	}
+
+	if except != models.FacetCategory && len(s.filters.CategoryIDs) > 0 {
+		query = whereInAnyCategory(query, s.filters.CategoryIDs, s.filters.IncludeDescendants)
+	}
+
+	if except != models.FacetPrice {
+		if s.filters.MinPrice != nil {
+			query = query.Where("products.price >= ?", *s.filters.MinPrice)
+		}
+		if s.filters.MaxPrice != nil {
+			query = query.Where("products.price < ?", *s.filters.MaxPrice)
+		}
+	}
+
+	// Each selected attribute matches the product or one of its active variants
+	if except != models.FacetAttribute {
+		query = whereProductAttributes(query, s.filters.Attributes)
+	}
+
+	return query
+}
+
+// attributesExcept returns the selected attributes without the named one
+func (s productSearch) attributesExcept(except string) map[string][]string {
+	attributes := make(map[string][]string, len(s.filters.Attributes))
+	for name, values := range s.filters.Attributes {
+		if name != except {
+			attributes[name] = values
+		}
+	}
+	return attributes
+}
+
+// facets counts the products of every facet bucket
+func (s productSearch) facets() (models.ProductFacets, error) {
+	facets := models.ProductFacets{
+		Brands:     []models.FacetBucket{},
+		Categories: []models.FacetBucket{},
+		Attributes: map[string][]models.FacetBucket{},
+	}
+
+	err := s.apply(database.DB.Model(&models.Product{}), models.FacetBrand).
+		Joins("JOIN brands ON brands.id = products.brand_id AND brands.deleted_at IS NULL").
+		Select("brands.id AS id, brands.name AS name, COUNT(*) AS count").
+		Group("brands.id, brands.name").
+		Order("count DESC, brands.name ASC").
+		Scan(&facets.Brands).Error
+	if err != nil {
+		return facets, err
+	}
+	for i := range facets.Brands {
+		facets.Brands[i].Selected = models.ContainsID(s.filters.BrandIDs, facets.Brands[i].ID)
+	}
+
+	err = s.apply(database.DB.Model(&models.Product{}), models.FacetCategory).
+		Joins("JOIN product_categories ON product_categories.product_id = products.id").
+		Joins("JOIN categories ON categories.id = product_categories.category_id AND categories.deleted_at IS NULL").
+		Select("categories.id AS id, categories.name AS name, COUNT(DISTINCT products.id) AS count").
+		Group("categories.id, categories.name").
+		Order("count DESC, categories.name ASC").
+		Scan(&facets.Categories).Error
+	if err != nil {
+		return facets, err
+	}
+	for i := range facets.Categories {
+		facets.Categories[i].Selected = models.ContainsID(s.filters.CategoryIDs, facets.Categories[i].ID)
+	}
+
+	if facets.PriceRanges, err = s.priceFacet(); err != nil {
+		return facets, err
+	}
+
+	// Attributes without a selection are counted together; each selected attribute is
+	// counted against the other selected attributes only
+	selected := s.filters.AttributeNames()
+	if err := s.attributeFacet(facets.Attributes, "", selected); err != nil {
+		return facets, err
+	}
+	for _, name := range selected {
+		if err := s.attributeFacet(facets.Attributes, name, nil); err != nil {
+			return facets, err
+		}
+	}
+
+	return facets, nil
+}
+
+// priceFacet counts the products of each price range bucket
+func (s productSearch) priceFacet() ([]models.FacetBucket, error) {
+	buckets := models.PriceRangeBuckets(s.filters)
+
+	var bucketSQL strings.Builder
+	bucketSQL.WriteString("CASE")
+	for i, bucket := range buckets {
+		if bucket.Max != nil {
+			bucketSQL.WriteString(" WHEN products.price < " + strconv.FormatFloat(*bucket.Max, 'f', -1, 64) + " THEN " + strconv.Itoa(i))
+		}
+	}
+	bucketSQL.WriteString(" ELSE " + strconv.Itoa(len(buckets)-1) + " END")
+
+	var rows []struct {
+		Bucket int
+		Count  int64
+	}
+	err := s.apply(database.DB.Model(&models.Product{}), models.FacetPrice).
+		Select(bucketSQL.String() + " AS bucket, COUNT(*) AS count").
+		Group("bucket").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	for _, row := range rows {
+		if row.Bucket >= 0 && row.Bucket < len(buckets) {
+			buckets[row.Bucket].Count = row.Count
+		}
+	}
+	return buckets, nil
+}
+
+// attributeFacet counts products by attribute value, from the product attributes and
+// those of its active variants, into facets. With a name, only that attribute is counted,
+// ignoring its own selection; otherwise all attributes except the skipped ones are counted.
+func (s productSearch) attributeFacet(facets map[string][]models.FacetBucket, name string, skip []string) error {
+	query := whereProductAttributes(s.apply(database.DB.Model(&models.Product{}), models.FacetAttribute), s.attributesExcept(name)).
+		Joins(`CROSS JOIN LATERAL (
+			SELECT key, value FROM jsonb_each_text(products.attributes)
+			UNION
+			SELECT variant_kv.key, variant_kv.value
+			FROM product_variants v CROSS JOIN LATERAL jsonb_each_text(v.attributes) AS variant_kv
+			WHERE v.product_id = products.id AND v.is_active AND v.deleted_at IS NULL
+		) AS kv`)
+	if name != "" {
+		query = query.Where("kv.key = ?", name)
+	} else if len(skip) > 0 {
+		query = query.Where("kv.key NOT IN ?", skip)
+	}
+
+	var rows []models.FacetBucket
+	err := query.
+		Select("kv.key AS name, kv.value AS value, COUNT(DISTINCT products.id) AS count").
+		Group("kv.key, kv.value").
+		Order("kv.key ASC, count DESC, kv.value ASC").
+		Scan(&rows).Error
+	if err != nil {
+		return err
+	}
+
+	for _, row := range rows {
+		attribute := row.Name
+		row.Name = ""
+		for _, value := range s.filters.Attributes[attribute] {
+			row.Selected = row.Selected || value == row.Value
+		}
+		facets[attribute] = append(facets[attribute], row)
+	}
+	return nil
+}
+
+// whereInAnyCategory restricts a product query to products linked to any of the
+// categories or, with includeDescendants, to any category of their subtrees
+func whereInAnyCategory(query *gorm.DB, categoryIDs []uint, includeDescendants bool) *gorm.DB {
+	if includeDescendants {
+		return query.Where("EXISTS (SELECT 1 FROM product_categories WHERE product_categories.product_id = products.id AND product_categories.category_id IN (SELECT sub.id FROM categories sub JOIN categories root ON root.path <> '' AND starts_with(sub.path, root.path) WHERE root.id IN ? AND sub.deleted_at IS NULL))", categoryIDs)
+	}
+	return query.Where("EXISTS (SELECT 1 FROM product_categories WHERE product_categories.product_id = products.id AND product_categories.category_id IN ?)", categoryIDs)
+}
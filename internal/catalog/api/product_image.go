@@ -0,0 +1,328 @@
+package api
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+
+	"gaetanjaminon/GoTuto/internal/catalog/config"
+	"gaetanjaminon/GoTuto/internal/catalog/database"
+	"gaetanjaminon/GoTuto/internal/catalog/models"
+	"gaetanjaminon/GoTuto/internal/catalog/services"
+	"gaetanjaminon/GoTuto/internal/catalog/storage"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Media defaults when not configured
+const (
+	defaultMaxUploadSize = 10 << 20 // 10 MB
+	defaultThumbnailSize = 256
+)
+
+// GetProductImages retrieves the images of a product in display order (?variant_id=)
+func GetProductImages(store storage.Storage) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var product models.Product
+		if err := database.DB.First(&product, c.Param("id")).Error; err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Product not found"})
+			return
+		}
+
+		query := database.DB.Where("product_id = ?", product.ID)
+		if variantID := c.Query("variant_id"); variantID != "" {
+			query = query.Where("variant_id = ?", variantID)
+		}
+
+		var images []models.ProductImage
+		if err := query.Order("sort_order ASC, id ASC").Find(&images).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve images"})
+			return
+		}
+
+		for i := range images {
+			images[i].WithURLs(store.URL)
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"product_id": product.ID,
+			"images":     images,
+		})
+	}
+}
+
+// UploadProductImage stores an image uploaded as the multipart "file" field, with its
+// thumbnail, and appends it to the product images. Optional form fields: variant_id and
+// alt_text.
+func UploadProductImage(mediaCfg config.MediaConfig, store storage.Storage) gin.HandlerFunc {
+	maxUploadSize := mediaCfg.MaxUploadSize
+	if maxUploadSize <= 0 {
+		maxUploadSize = defaultMaxUploadSize
+	}
+	thumbnailSize := mediaCfg.ThumbnailSize
+	if thumbnailSize <= 0 {
+		thumbnailSize = defaultThumbnailSize
+	}
+
+	return func(c *gin.Context) {
+		var product models.Product
+		if err := database.DB.First(&product, c.Param("id")).Error; err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Product not found"})
+			return
+		}
+
+		// Leave room for the multipart envelope and the other form fields
+		c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxUploadSize+1<<20)
+
+		fileHeader, err := c.FormFile("file")
+		if err != nil {
+			var maxBytesErr *http.MaxBytesError
+			if errors.As(err, &maxBytesErr) {
+				c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": fmt.Sprintf("Image cannot exceed %d bytes", maxUploadSize)})
+				return
+			}
+			c.JSON(http.StatusBadRequest, gin.H{"error": "An image file is required in the 'file' field"})
+			return
+		}
+		if fileHeader.Size > maxUploadSize {
+			c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": fmt.Sprintf("Image cannot exceed %d bytes", maxUploadSize)})
+			return
+		}
+
+		image := models.ProductImage{
+			ProductID: product.ID,
+			AltText:   c.PostForm("alt_text"),
+		}
+		if variantID := c.PostForm("variant_id"); variantID != "" {
+			id, err := strconv.ParseUint(variantID, 10, 64)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid variant ID"})
+				return
+			}
+			var count int64
+			database.DB.Model(&models.ProductVariant{}).Where("id = ? AND product_id = ?", id, product.ID).Count(&count)
+			if count == 0 {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid variant ID"})
+				return
+			}
+			variant := uint(id)
+			image.VariantID = &variant
+		}
+
+		// Validate image
+		if err := image.Validate(); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		file, err := fileHeader.Open()
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Failed to read uploaded file"})
+			return
+		}
+		data, err := io.ReadAll(io.LimitReader(file, maxUploadSize+1))
+		file.Close()
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Failed to read uploaded file"})
+			return
+		}
+		if int64(len(data)) > maxUploadSize {
+			c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": fmt.Sprintf("Image cannot exceed %d bytes", maxUploadSize)})
+			return
+		}
+
+		processed, err := services.ProcessImage(data, thumbnailSize)
+		if errors.Is(err, services.ErrUnsupportedMediaType) {
+			c.JSON(http.StatusUnsupportedMediaType, gin.H{"error": err.Error()})
+			return
+		}
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		name, err := randomMediaName()
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to store image"})
+			return
+		}
+		image.StorageKey = fmt.Sprintf("products/%d/%s%s", product.ID, name, processed.Extension)
+		image.ThumbnailKey = fmt.Sprintf("products/%d/%s_thumb%s", product.ID, name, processed.ThumbnailExtension)
+		image.ContentType = processed.ContentType
+		image.Size = int64(len(data))
+		image.Width, image.Height = processed.Width, processed.Height
+
+		ctx := c.Request.Context()
+		if err := store.Save(ctx, image.StorageKey, bytes.NewReader(data)); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to store image"})
+			return
+		}
+		if err := store.Save(ctx, image.ThumbnailKey, bytes.NewReader(processed.Thumbnail)); err != nil {
+			deleteMedia(store, c, image)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to store image"})
+			return
+		}
+
+		// New images go last; the product row lock serializes concurrent uploads
+		err = database.DB.Transaction(func(tx *gorm.DB) error {
+			if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&models.Product{}, product.ID).Error; err != nil {
+				return err
+			}
+			var next int
+			if err := tx.Model(&models.ProductImage{}).Where("product_id = ?", product.ID).
+				Select("COALESCE(MAX(sort_order) + 1, 0)").Scan(&next).Error; err != nil {
+				return err
+			}
+			image.SortOrder = next
+			return tx.Create(&image).Error
+		})
+		if err != nil {
+			deleteMedia(store, c, image)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save image"})
+			return
+		}
+
+		c.JSON(http.StatusCreated, image.WithURLs(store.URL))
+	}
+}
+
+// UpdateProductImage updates the alt text of a product image
+func UpdateProductImage(store storage.Storage) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var image models.ProductImage
+		if err := database.DB.Where("product_id = ?", c.Param("id")).First(&image, c.Param("image_id")).Error; err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Image not found"})
+			return
+		}
+
+		var req models.UpdateProductImageRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		// Update only provided fields
+		if req.AltText != nil {
+			image.AltText = *req.AltText
+		}
+
+		// Validate image
+		if err := image.Validate(); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		if err := database.DB.Save(&image).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update image"})
+			return
+		}
+
+		c.JSON(http.StatusOK, image.WithURLs(store.URL))
+	}
+}
+
+// ReorderProductImages rewrites the display order of all images of a product at once,
+// following the order of the given image IDs
+func ReorderProductImages(store storage.Storage) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var product models.Product
+		if err := database.DB.First(&product, c.Param("id")).Error; err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Product not found"})
+			return
+		}
+
+		var req models.ReorderImagesRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		err := database.DB.Transaction(func(tx *gorm.DB) error {
+			var currentIDs []uint
+			if err := tx.Model(&models.ProductImage{}).Clauses(clause.Locking{Strength: "UPDATE"}).
+				Where("product_id = ?", product.ID).Pluck("id", &currentIDs).Error; err != nil {
+				return err
+			}
+			if err := req.Validate(currentIDs); err != nil {
+				return fmt.Errorf("%w: %v", errInvalidImageOrder, err)
+			}
+			for position, id := range req.ImageIDs {
+				if err := tx.Model(&models.ProductImage{}).Where("id = ?", id).Update("sort_order", position).Error; err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+		if errors.Is(err, errInvalidImageOrder) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to reorder images"})
+			return
+		}
+
+		var images []models.ProductImage
+		database.DB.Where("product_id = ?", product.ID).Order("sort_order ASC, id ASC").Find(&images)
+		for i := range images {
+			images[i].WithURLs(store.URL)
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"product_id": product.ID,
+			"images":     images,
+		})
+	}
+}
+
+var errInvalidImageOrder = errors.New("invalid image order")
+
+// DeleteProductImage deletes a product image and its files
+func DeleteProductImage(store storage.Storage) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var image models.ProductImage
+		if err := database.DB.Where("product_id = ?", c.Param("id")).First(&image, c.Param("image_id")).Error; err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Image not found"})
+			return
+		}
+
+		if err := database.DB.Delete(&image).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete image"})
+			return
+		}
+
+		// The row is gone, so leftover files are only logged
+		deleteMedia(store, c, image)
+
+		c.JSON(http.StatusOK, gin.H{"message": "Image deleted successfully"})
+	}
+}
+
+// deleteMedia removes the files of an image from the storage, logging failures
+func deleteMedia(store storage.Storage, c *gin.Context, image models.ProductImage) {
+	for _, key := range []string{image.StorageKey, image.ThumbnailKey} {
+		if key == "" {
+			continue
+		}
+		if err := store.Delete(c.Request.Context(), key); err != nil {
+			log.Printf("Failed to delete media %s: %v", key, err)
+		}
+	}
+}
+
+// randomMediaName returns a random, unguessable file name for an uploaded file
+func randomMediaName() (string, error) {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf), nil
+}
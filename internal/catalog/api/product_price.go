@@ -0,0 +1,107 @@
+package api
+
+import (
+	"net/http"
+	"time"
+
+	"gaetanjaminon/GoTuto/internal/catalog/database"
+	"gaetanjaminon/GoTuto/internal/catalog/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetProductPrices retrieves the price history of a product, scheduled changes included,
+// most recent first (?status=applied|scheduled|cancelled)
+func GetProductPrices(c *gin.Context) {
+	var product models.Product
+	if err := database.DB.First(&product, c.Param("id")).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Product not found"})
+		return
+	}
+
+	query := database.DB.Where("product_id = ?", product.ID)
+	if status := c.Query("status"); status != "" {
+		if !models.IsValidPriceChangeStatus(models.PriceChangeStatus(status)) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "status must be applied, scheduled or cancelled"})
+			return
+		}
+		query = query.Where("status = ?", status)
+	}
+
+	var prices []models.ProductPrice
+	if err := query.Order("effective_from DESC, id DESC").Find(&prices).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve prices"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"product_id":    product.ID,
+		"current_price": product.Price,
+		"currency":      product.Currency,
+		"prices":        prices,
+	})
+}
+
+// ScheduleProductPrice schedules a future price change of a product
+func ScheduleProductPrice(c *gin.Context) {
+	var product models.Product
+	if err := database.DB.First(&product, c.Param("id")).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Product not found"})
+		return
+	}
+
+	if product.Status == models.ProductStatusDiscontinued {
+		c.JSON(http.StatusConflict, gin.H{"error": models.ErrProductNotSellable.Error()})
+		return
+	}
+
+	var req models.SchedulePriceChangeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Validate request
+	if err := req.Validate(time.Now(), product.Currency); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	change := models.ProductPrice{
+		ProductID:     product.ID,
+		Price:         *req.Price,
+		Currency:      product.Currency,
+		EffectiveFrom: req.EffectiveFrom,
+		Status:        models.PriceChangeScheduled,
+		Reason:        req.Reason,
+	}
+
+	if err := database.DB.Create(&change).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to schedule price change"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, change)
+}
+
+// CancelScheduledProductPrice cancels a price change that has not been applied yet
+func CancelScheduledProductPrice(c *gin.Context) {
+	var change models.ProductPrice
+	if err := database.DB.Where("product_id = ?", c.Param("id")).First(&change, c.Param("price_id")).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Price change not found"})
+		return
+	}
+
+	// The scheduler may be applying the change; only still scheduled rows are cancelled
+	result := database.DB.Model(&change).Where("status = ?", models.PriceChangeScheduled).Update("status", models.PriceChangeCancelled)
+	if result.Error != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to cancel price change"})
+		return
+	}
+	if result.RowsAffected == 0 {
+		c.JSON(http.StatusConflict, gin.H{"error": "Only scheduled price changes can be cancelled", "status": change.Status})
+		return
+	}
+
+	c.JSON(http.StatusOK, change)
+}
@@ -0,0 +1,45 @@
+package api
+
+import (
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// Full-text search configurations; they must match the search_vector column of
+// migration 005_product_search. Names and descriptions are stemmed as English, while SKUs
+// are indexed verbatim, so the query is parsed with both configurations.
+const (
+	productSearchQuery    = "(websearch_to_tsquery('english', @term) || websearch_to_tsquery('simple', @term))"
+	productSearchHeadline = "StartSel=<mark>, StopSel=</mark>, MaxWords=35, MinWords=15, ShortWord=3, MaxFragments=2"
+)
+
+// productSearchSnippetText is the text highlighted in search snippets. It is HTML-escaped
+// before the <mark> tags are added, so snippets can be rendered as HTML safely.
+const productSearchSnippetText = "replace(replace(replace(replace(replace(" +
+	"coalesce(nullif(products.description, ''), products.name), " +
+	"'&', '&amp;'), '<', '&lt;'), '>', '&gt;'), '\"', '&quot;'), '''', '&#39;')"
+
+// whereProductSearch restricts a product query to products matching the search terms.
+// Terms use web search syntax ("quoted phrases", -excluded, or); a SKU prefix also matches.
+func whereProductSearch(query *gorm.DB, term string) *gorm.DB {
+	return query.Where("products.search_vector @@ "+productSearchQuery+" OR products.sku ILIKE @prefix",
+		map[string]interface{}{"term": term, "prefix": skuPrefixPattern(term)})
+}
+
+// selectProductSearchRank adds the rank and highlighted snippet of each product for the
+// search terms and orders the most relevant products first
+func selectProductSearchRank(query *gorm.DB, term string) *gorm.DB {
+	return query.
+		Select("products.*, "+
+			"ts_rank(products.search_vector, "+productSearchQuery+") AS search_rank, "+
+			"ts_headline('english', "+productSearchSnippetText+", "+productSearchQuery+", @options) AS search_snippet",
+			map[string]interface{}{"term": term, "options": productSearchHeadline}).
+		Order("search_rank DESC, products.id ASC")
+}
+
+// skuPrefixPattern returns the ILIKE pattern matching SKUs starting with the term
+func skuPrefixPattern(term string) string {
+	escaped := strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(strings.TrimSpace(term))
+	return escaped + "%"
+}
@@ -0,0 +1,385 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"gaetanjaminon/GoTuto/internal/catalog/database"
+	"gaetanjaminon/GoTuto/internal/catalog/models"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// GetProductVariants retrieves the variants of a product, optionally filtered by
+// attributes (?attributes=size=M,color=red) and active state
+func GetProductVariants(c *gin.Context) {
+	product, ok := findVariantProduct(c)
+	if !ok {
+		return
+	}
+
+	query := database.DB.Where("product_id = ?", product.ID).Order("id ASC")
+
+	if value := c.Query("attributes"); value != "" {
+		attributes, err := models.ParseVariantAttributes(value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		query = whereVariantAttributes(query, attributes)
+	}
+
+	if isActive := c.Query("is_active"); isActive != "" {
+		query = query.Where("is_active = ?", isActive == "true")
+	}
+
+	var variants []models.ProductVariant
+	if err := query.Find(&variants).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve variants"})
+		return
+	}
+	for i := range variants {
+		variants[i].WithEffectivePrice(product.Price)
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"product_id": product.ID,
+		"variants":   variants,
+	})
+}
+
+// LookupProductVariant finds the single variant of a product with exactly the given
+// attributes (?attributes=size=M,color=red), e.g. to resolve a storefront selection
+func LookupProductVariant(c *gin.Context) {
+	product, ok := findVariantProduct(c)
+	if !ok {
+		return
+	}
+
+	attributes, err := models.ParseVariantAttributes(c.Query("attributes"))
+	if err != nil || len(attributes) == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "attributes query parameter is required, e.g. size=M,color=red"})
+		return
+	}
+
+	var variants []models.ProductVariant
+	if err := whereVariantAttributes(database.DB.Where("product_id = ?", product.ID), attributes).Find(&variants).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve variants"})
+		return
+	}
+
+	// Containment matched variants with extra attributes too; keep the exact match
+	for i := range variants {
+		if len(variants[i].Attributes) == len(attributes) {
+			c.JSON(http.StatusOK, variants[i].WithEffectivePrice(product.Price))
+			return
+		}
+	}
+
+	c.JSON(http.StatusNotFound, gin.H{
+		"error":      "No variant with these attributes",
+		"attributes": attributes,
+		"candidates": len(variants),
+	})
+}
+
+// GetProductVariant retrieves a single variant of a product
+func GetProductVariant(c *gin.Context) {
+	product, ok := findVariantProduct(c)
+	if !ok {
+		return
+	}
+
+	var variant models.ProductVariant
+	if err := database.DB.Where("product_id = ?", product.ID).First(&variant, c.Param("variant_id")).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Variant not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, variant.WithEffectivePrice(product.Price))
+}
+
+// CreateProductVariant creates a new variant for a product
+func CreateProductVariant(c *gin.Context) {
+	product, ok := findVariantProduct(c)
+	if !ok {
+		return
+	}
+
+	// Discontinued products are no longer sold, so they get no new variants
+	if product.Status == models.ProductStatusDiscontinued {
+		c.JSON(http.StatusConflict, gin.H{"error": models.ErrProductNotSellable.Error()})
+		return
+	}
+
+	var req models.CreateVariantRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// New variants start without stock, it is received through the inventory
+	if req.StockQuantity != nil && *req.StockQuantity != 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": models.ErrStockManagedByInventory.Error()})
+		return
+	}
+
+	variant := models.ProductVariant{
+		ProductID:       product.ID,
+		SKU:             strings.TrimSpace(req.SKU),
+		GTIN:            strings.TrimSpace(req.GTIN),
+		Name:            req.Name,
+		PriceAdjustment: req.PriceAdjustment,
+		Attributes:      req.Attributes,
+		IsActive:        true,
+	}
+	if req.IsActive != nil {
+		variant.IsActive = *req.IsActive
+	}
+
+	// Validate variant
+	if err := variant.Validate(product.Price); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Variant attributes must follow the schema of the product primary category
+	attributes, ok := checkAttributes(c, primaryCategoryID(product.ID), variant.Attributes, models.AttributeLevelVariant)
+	if !ok {
+		return
+	}
+	variant.Attributes = attributes
+
+	if !checkVariantUnique(c, variant) {
+		return
+	}
+
+	if err := database.DB.Create(&variant).Error; err != nil {
+		if errors.Is(err, gorm.ErrDuplicatedKey) {
+			c.JSON(http.StatusConflict, gin.H{"error": productCodeTaken})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create variant"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, variant.WithEffectivePrice(product.Price))
+}
+
+// UpdateProductVariant updates a variant of a product
+func UpdateProductVariant(c *gin.Context) {
+	product, ok := findVariantProduct(c)
+	if !ok {
+		return
+	}
+
+	var variant models.ProductVariant
+	if err := database.DB.Where("product_id = ?", product.ID).First(&variant, c.Param("variant_id")).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Variant not found"})
+		return
+	}
+
+	var req models.UpdateVariantRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if req.StockQuantity != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": models.ErrStockManagedByInventory.Error()})
+		return
+	}
+
+	// Update only provided fields
+	if req.SKU != "" {
+		variant.SKU = strings.TrimSpace(req.SKU)
+	}
+	if req.GTIN != nil {
+		variant.GTIN = strings.TrimSpace(*req.GTIN)
+	}
+	if req.Name != "" {
+		variant.Name = req.Name
+	}
+	if req.PriceAdjustment != nil {
+		variant.PriceAdjustment = *req.PriceAdjustment
+	}
+	if req.Attributes != nil {
+		variant.Attributes = req.Attributes
+	}
+	if req.IsActive != nil {
+		variant.IsActive = *req.IsActive
+	}
+
+	// Validate variant
+	if err := variant.Validate(product.Price); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Variant attributes must follow the schema of the product primary category
+	attributes, ok := checkAttributes(c, primaryCategoryID(product.ID), variant.Attributes, models.AttributeLevelVariant)
+	if !ok {
+		return
+	}
+	variant.Attributes = attributes
+
+	if !checkVariantUnique(c, variant) {
+		return
+	}
+
+	// stock_quantity is kept in sync by the inventory and not written back
+	if err := database.DB.Omit("stock_quantity").Save(&variant).Error; err != nil {
+		if errors.Is(err, gorm.ErrDuplicatedKey) {
+			c.JSON(http.StatusConflict, gin.H{"error": productCodeTaken})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update variant"})
+		return
+	}
+
+	c.JSON(http.StatusOK, variant.WithEffectivePrice(product.Price))
+}
+
+// DeleteProductVariant soft deletes a variant of a product
+func DeleteProductVariant(c *gin.Context) {
+	product, ok := findVariantProduct(c)
+	if !ok {
+		return
+	}
+
+	var variant models.ProductVariant
+	if err := database.DB.Where("product_id = ?", product.ID).First(&variant, c.Param("variant_id")).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Variant not found"})
+		return
+	}
+
+	if err := database.DB.Delete(&variant).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete variant"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Variant deleted successfully"})
+}
+
+// negativeVariantPrice returns the first variant of a product whose price would be
+// negative with the given base price
+func negativeVariantPrice(productID uint, basePrice float64) (uint, bool) {
+	var variants []models.ProductVariant
+	database.DB.Where("product_id = ? AND price_adjustment < 0", productID).Order("id ASC").Find(&variants)
+	for _, variant := range variants {
+		if variant.CalculateEffectivePrice(basePrice) < 0 {
+			return variant.ID, true
+		}
+	}
+	return 0, false
+}
+
+// findVariantProduct loads the product of a variant route; on failure it writes the
+// error response and returns false
+func findVariantProduct(c *gin.Context) (models.Product, bool) {
+	var product models.Product
+	if err := database.DB.First(&product, c.Param("id")).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Product not found"})
+		return product, false
+	}
+	return product, true
+}
+
+// whereVariantAttributes restricts a variant query to variants containing all attributes
+func whereVariantAttributes(query *gorm.DB, attributes models.VariantAttributes) *gorm.DB {
+	filter, _ := attributes.Value()
+	return query.Where("attributes @> CAST(? AS JSONB)", filter)
+}
+
+// checkVariantUnique rejects a variant whose SKU or GTIN is used by another product or
+// variant, or whose attributes duplicate another variant of the same product. On conflict
+// it writes the error response and returns false.
+func checkVariantUnique(c *gin.Context, variant models.ProductVariant) bool {
+	owner, err := skuOwner(variant.SKU, 0, variant.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to check SKU"})
+		return false
+	}
+	if owner != "" {
+		c.JSON(http.StatusConflict, gin.H{"error": "SKU is already used by a " + owner})
+		return false
+	}
+
+	if variant.GTIN != "" {
+		owner, err := gtinOwner(variant.GTIN, 0, variant.ID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to check GTIN"})
+			return false
+		}
+		if owner != "" {
+			c.JSON(http.StatusConflict, gin.H{"error": "GTIN is already used by a " + owner})
+			return false
+		}
+	}
+
+	if len(variant.Attributes) > 0 {
+		var siblings []models.ProductVariant
+		whereVariantAttributes(database.DB.Where("product_id = ? AND id <> ?", variant.ProductID, variant.ID), variant.Attributes).Find(&siblings)
+		for _, sibling := range siblings {
+			if len(sibling.Attributes) == len(variant.Attributes) {
+				c.JSON(http.StatusConflict, gin.H{
+					"error":      "Another variant of this product has the same attributes",
+					"variant_id": sibling.ID,
+				})
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+// productCodeTaken is answered when the database rejects a SKU or GTIN, for instance one
+// saved by a concurrent request after the owner checks passed
+const productCodeTaken = "SKU or GTIN is already used by another product or variant"
+
+// skuOwner returns "product" or "variant" when the SKU is already used, ignoring the given
+// product and variant IDs, and "" when it is free. Product and variant SKUs share one
+// namespace; soft-deleted rows still hold their SKU through the unique constraints.
+// The check gives a precise answer, while migration 012 enforces the shared namespace.
+func skuOwner(sku string, exceptProductID, exceptVariantID uint) (string, error) {
+	var count int64
+	if err := database.DB.Unscoped().Model(&models.Product{}).Where("sku = ? AND id <> ?", sku, exceptProductID).Count(&count).Error; err != nil {
+		return "", err
+	}
+	if count > 0 {
+		return "product", nil
+	}
+
+	if err := database.DB.Unscoped().Model(&models.ProductVariant{}).Where("sku = ? AND id <> ?", sku, exceptVariantID).Count(&count).Error; err != nil {
+		return "", err
+	}
+	if count > 0 {
+		return "variant", nil
+	}
+
+	return "", nil
+}
+
+// gtinOwner returns "product" or "variant" when the GTIN is already used, ignoring the
+// given product and variant IDs, and "" when it is free
+func gtinOwner(gtin string, exceptProductID, exceptVariantID uint) (string, error) {
+	var count int64
+	if err := database.DB.Model(&models.Product{}).Where("gtin = ? AND id <> ?", gtin, exceptProductID).Count(&count).Error; err != nil {
+		return "", err
+	}
+	if count > 0 {
+		return "product", nil
+	}
+
+	if err := database.DB.Model(&models.ProductVariant{}).Where("gtin = ? AND id <> ?", gtin, exceptVariantID).Count(&count).Error; err != nil {
+		return "", err
+	}
+	if count > 0 {
+		return "variant", nil
+	}
+
+	return "", nil
+}
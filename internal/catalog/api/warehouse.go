@@ -0,0 +1,158 @@
+package api
+
+import (
+	"net/http"
+
+	"gaetanjaminon/GoTuto/internal/catalog/database"
+	"gaetanjaminon/GoTuto/internal/catalog/models"
+	"gaetanjaminon/GoTuto/internal/catalog/services"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// GetWarehouses retrieves all warehouses (?is_active=true|false)
+func GetWarehouses(c *gin.Context) {
+	var warehouses []models.Warehouse
+
+	query := database.DB.Model(&models.Warehouse{})
+	if isActive := c.Query("is_active"); isActive != "" {
+		query = query.Where("is_active = ?", isActive == "true")
+	}
+
+	if err := query.Order("code ASC").Find(&warehouses).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve warehouses"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"warehouses": warehouses})
+}
+
+// GetWarehouse retrieves a single warehouse
+func GetWarehouse(c *gin.Context) {
+	var warehouse models.Warehouse
+
+	if err := database.DB.First(&warehouse, c.Param("id")).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Warehouse not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, warehouse)
+}
+
+// CreateWarehouse creates a new warehouse
+func CreateWarehouse(c *gin.Context) {
+	var req models.CreateWarehouseRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	warehouse := models.Warehouse{
+		Code:     req.Code,
+		Name:     req.Name,
+		Address:  req.Address,
+		IsActive: true,
+	}
+	if req.IsActive != nil {
+		warehouse.IsActive = *req.IsActive
+	}
+
+	// Validate warehouse
+	if err := warehouse.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Check if code already exists (soft-deleted warehouses keep their code)
+	var count int64
+	database.DB.Unscoped().Model(&models.Warehouse{}).Where("code = ?", warehouse.Code).Count(&count)
+	if count > 0 {
+		c.JSON(http.StatusConflict, gin.H{"error": "Warehouse with this code already exists"})
+		return
+	}
+
+	if err := database.DB.Create(&warehouse).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create warehouse"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, warehouse)
+}
+
+// UpdateWarehouse updates an existing warehouse
+func UpdateWarehouse(c *gin.Context) {
+	var warehouse models.Warehouse
+
+	if err := database.DB.First(&warehouse, c.Param("id")).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Warehouse not found"})
+		return
+	}
+
+	var req models.UpdateWarehouseRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Update only provided fields
+	if req.Name != "" {
+		warehouse.Name = req.Name
+	}
+	if req.Address != "" {
+		warehouse.Address = req.Address
+	}
+	wasActive := warehouse.IsActive
+	if req.IsActive != nil {
+		warehouse.IsActive = *req.IsActive
+	}
+
+	// Validate warehouse
+	if err := warehouse.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Variant stock quantities only count active warehouses
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(&warehouse).Error; err != nil {
+			return err
+		}
+		if warehouse.IsActive != wasActive {
+			return services.SyncWarehouseVariantStock(tx, warehouse.ID)
+		}
+		return nil
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update warehouse"})
+		return
+	}
+
+	c.JSON(http.StatusOK, warehouse)
+}
+
+// DeleteWarehouse soft deletes a warehouse that holds no stock
+func DeleteWarehouse(c *gin.Context) {
+	var warehouse models.Warehouse
+
+	if err := database.DB.First(&warehouse, c.Param("id")).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Warehouse not found"})
+		return
+	}
+
+	// Check if the warehouse still holds stock
+	var count int64
+	database.DB.Model(&models.StockLevel{}).Where("warehouse_id = ? AND on_hand > 0", warehouse.ID).Count(&count)
+	if count > 0 {
+		c.JSON(http.StatusConflict, gin.H{"error": "Cannot delete warehouse holding stock"})
+		return
+	}
+
+	if err := database.DB.Delete(&warehouse).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete warehouse"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Warehouse deleted successfully"})
+}
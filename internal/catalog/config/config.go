@@ -1,6 +1,8 @@
 package config
 
 import (
+	"time"
+	
 	"gaetanjaminon/GoTuto/internal/shared/infrastructure"
 )
 
@@ -16,6 +18,8 @@ type CatalogConfig struct {
 	Pagination PaginationConfig `mapstructure:"pagination"`
 	Product    ProductConfig    `mapstructure:"product"`
 	Category   CategoryConfig   `mapstructure:"category"`
+	Inventory  InventoryConfig  `mapstructure:"inventory"`
+	Media      MediaConfig      `mapstructure:"media"`
 }
 
 // PaginationConfig holds pagination settings for catalog domain
@@ -29,12 +33,35 @@ type ProductConfig struct {
 	SKUPrefix       string `mapstructure:"sku_prefix"`
 	DefaultCurrency string `mapstructure:"default_currency"`
 	AllowZeroPrice  bool   `mapstructure:"allow_zero_price"`
+	
+	// Pattern of generated SKUs: {prefix}, {category}, {seq:<width>} and {check}
+	SKUPattern string `mapstructure:"sku_pattern"`
+	
+	// How often scheduled price changes are applied (0 disables the price scheduler)
+	PriceSchedulerInterval time.Duration `mapstructure:"price_scheduler_interval"`
 }
 
 // CategoryConfig holds category-specific settings
 type CategoryConfig struct {
-	MaxDepth         int  `mapstructure:"max_depth"`
-	AllowCircularRefs bool `mapstructure:"allow_circular_refs"`
+	MaxDepth          int  `mapstructure:"max_depth"`
+	AllowCircularRefs bool `mapstructure:"allow_circular_refs"` // Not supported: startup fails when set
+}
+
+// InventoryConfig holds inventory settings
+type InventoryConfig struct {
+	// Default lifetime of stock reservations
+	ReservationTTL time.Duration `mapstructure:"reservation_ttl"`
+	
+	// How often expired reservations are released (0 disables the expiry job)
+	ExpiryInterval time.Duration `mapstructure:"expiry_interval"`
+}
+
+// MediaConfig holds product media settings
+type MediaConfig struct {
+	StoragePath   string `mapstructure:"storage_path"`    // Directory of the local storage
+	BaseURL       string `mapstructure:"base_url"`        // URL path the stored files are served under
+	MaxUploadSize int64  `mapstructure:"max_upload_size"` // Bytes
+	ThumbnailSize int    `mapstructure:"thumbnail_size"`  // Pixels, longest side
 }
 
 // Load reads catalog configuration from files and environment
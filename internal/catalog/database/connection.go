@@ -31,6 +31,8 @@ func Connect(cfg *config.CatalogConfig) (*gorm.DB, error) {
 
 	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{
 		Logger: logger.Default.LogMode(logLevel),
+		// Constraint violations come back as gorm errors (gorm.ErrDuplicatedKey, ...)
+		TranslateError: true,
 	})
 
 	if err != nil {
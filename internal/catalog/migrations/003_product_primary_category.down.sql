@@ -0,0 +1,4 @@
+-- Ensure we're in the catalog schema
+SET search_path TO catalog;
+
+DROP INDEX IF EXISTS idx_product_categories_primary;
\ No newline at end of file
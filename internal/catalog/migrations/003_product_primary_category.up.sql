@@ -0,0 +1,7 @@
+-- Ensure we're in the catalog schema
+SET search_path TO catalog;
+
+-- Products are categorized through product_categories only; a product has at most one
+-- primary category (check_single_primary_category demotes the previous one)
+CREATE UNIQUE INDEX IF NOT EXISTS idx_product_categories_primary
+    ON product_categories(product_id) WHERE is_primary;
\ No newline at end of file
@@ -0,0 +1,6 @@
+-- Ensure we're in the catalog schema
+SET search_path TO catalog;
+
+DROP INDEX IF EXISTS idx_categories_path;
+ALTER TABLE categories DROP COLUMN IF EXISTS depth;
+ALTER TABLE categories DROP COLUMN IF EXISTS path;
\ No newline at end of file
@@ -0,0 +1,25 @@
+-- Ensure we're in the catalog schema
+SET search_path TO catalog;
+
+-- Materialized category paths: path lists the IDs from the root down to the category
+-- ("/1/4/9/"), so a subtree is every category whose path starts with its root's path.
+-- Both columns are maintained by the API when categories are created or moved.
+ALTER TABLE categories ADD COLUMN IF NOT EXISTS path VARCHAR(1000) NOT NULL DEFAULT '';
+ALTER TABLE categories ADD COLUMN IF NOT EXISTS depth INTEGER NOT NULL DEFAULT 0;
+
+-- Backfill existing categories
+WITH RECURSIVE tree AS (
+    SELECT id, '/' || id || '/' AS path, 0 AS depth
+    FROM categories
+    WHERE parent_id IS NULL
+    UNION ALL
+    SELECT c.id, tree.path || c.id || '/', tree.depth + 1
+    FROM categories c
+    JOIN tree ON c.parent_id = tree.id
+)
+UPDATE categories
+SET path = tree.path, depth = tree.depth
+FROM tree
+WHERE categories.id = tree.id;
+
+CREATE INDEX IF NOT EXISTS idx_categories_path ON categories(path varchar_pattern_ops);
\ No newline at end of file
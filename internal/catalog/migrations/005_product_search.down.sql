@@ -0,0 +1,5 @@
+-- Ensure we're in the catalog schema
+SET search_path TO catalog;
+
+DROP INDEX IF EXISTS idx_products_search_vector;
+ALTER TABLE products DROP COLUMN IF EXISTS search_vector;
\ No newline at end of file
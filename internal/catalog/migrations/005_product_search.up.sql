@@ -0,0 +1,14 @@
+-- Ensure we're in the catalog schema
+SET search_path TO catalog;
+
+-- Full-text search: name (A) ranks above SKU (B) and description (C). Name and
+-- description are stemmed as English; SKUs are indexed verbatim. The API queries with
+-- the same configurations (see internal/catalog/api/product_search.go).
+ALTER TABLE products ADD COLUMN IF NOT EXISTS search_vector TSVECTOR
+    GENERATED ALWAYS AS (
+        setweight(to_tsvector('english', coalesce(name, '')), 'A') ||
+        setweight(to_tsvector('simple', coalesce(sku, '')), 'B') ||
+        setweight(to_tsvector('english', coalesce(description, '')), 'C')
+    ) STORED;
+
+CREATE INDEX IF NOT EXISTS idx_products_search_vector ON products USING GIN (search_vector);
\ No newline at end of file
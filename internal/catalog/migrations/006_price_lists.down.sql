@@ -0,0 +1,5 @@
+-- Ensure we're in the catalog schema
+SET search_path TO catalog;
+
+DROP TABLE IF EXISTS price_list_items;
+DROP TABLE IF EXISTS price_lists;
\ No newline at end of file
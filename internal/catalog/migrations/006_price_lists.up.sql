@@ -0,0 +1,38 @@
+-- Ensure we're in the catalog schema
+SET search_path TO catalog;
+
+-- Price lists (wholesale, retail, promotions...) override the product price when a price
+-- is resolved with their code. When several lists apply, the highest priority wins.
+CREATE TABLE IF NOT EXISTS price_lists (
+    id SERIAL PRIMARY KEY,
+    code VARCHAR(50) UNIQUE NOT NULL,
+    name VARCHAR(255) NOT NULL,
+    description TEXT,
+    currency VARCHAR(3) NOT NULL DEFAULT 'USD',
+    priority INTEGER NOT NULL DEFAULT 0,
+    valid_from TIMESTAMP WITH TIME ZONE,
+    valid_to TIMESTAMP WITH TIME ZONE,
+    is_active BOOLEAN DEFAULT true,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP WITH TIME ZONE,
+    CHECK (valid_from IS NULL OR valid_to IS NULL OR valid_from < valid_to)
+);
+
+CREATE INDEX IF NOT EXISTS idx_price_lists_deleted_at ON price_lists(deleted_at);
+
+-- Prices of a list, for a whole product or one of its variants, from a minimum quantity
+CREATE TABLE IF NOT EXISTS price_list_items (
+    id SERIAL PRIMARY KEY,
+    price_list_id INTEGER NOT NULL REFERENCES price_lists(id) ON DELETE CASCADE,
+    product_id INTEGER NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+    variant_id INTEGER REFERENCES product_variants(id) ON DELETE CASCADE,
+    min_quantity INTEGER NOT NULL DEFAULT 1 CHECK (min_quantity >= 1),
+    price DECIMAL(10,2) NOT NULL CHECK (price >= 0),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_price_list_items_tier
+    ON price_list_items(price_list_id, product_id, COALESCE(variant_id, 0), min_quantity);
+CREATE INDEX IF NOT EXISTS idx_price_list_items_product_id ON price_list_items(product_id);
\ No newline at end of file
@@ -0,0 +1,4 @@
+-- Ensure we're in the catalog schema
+SET search_path TO catalog;
+
+DROP TABLE IF EXISTS product_prices;
\ No newline at end of file
@@ -0,0 +1,27 @@
+-- Ensure we're in the catalog schema
+SET search_path TO catalog;
+
+-- Price history of products. Applied rows are the successive prices of a product, each
+-- effective from effective_from until effective_to (NULL for the current price).
+-- Scheduled rows are future changes, applied by the price scheduler when they are due.
+CREATE TABLE IF NOT EXISTS product_prices (
+    id SERIAL PRIMARY KEY,
+    product_id INTEGER NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+    price DECIMAL(10,2) NOT NULL CHECK (price >= 0),
+    currency VARCHAR(3) NOT NULL DEFAULT 'USD',
+    effective_from TIMESTAMP WITH TIME ZONE NOT NULL,
+    effective_to TIMESTAMP WITH TIME ZONE,
+    status VARCHAR(20) NOT NULL DEFAULT 'applied' CHECK (status IN ('scheduled', 'applied', 'cancelled')),
+    reason TEXT,
+    applied_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_product_prices_product_id ON product_prices(product_id, effective_from);
+CREATE INDEX IF NOT EXISTS idx_product_prices_scheduled ON product_prices(effective_from) WHERE status = 'scheduled';
+
+-- Current prices start the history of existing products
+INSERT INTO product_prices (product_id, price, currency, effective_from, status, reason, applied_at)
+SELECT id, price, COALESCE(currency, 'USD'), COALESCE(created_at, CURRENT_TIMESTAMP), 'applied', 'Initial price', CURRENT_TIMESTAMP
+FROM products;
\ No newline at end of file
@@ -0,0 +1,7 @@
+-- Ensure we're in the catalog schema
+SET search_path TO catalog;
+
+DROP TABLE IF EXISTS stock_movements;
+DROP TABLE IF EXISTS stock_reservations;
+DROP TABLE IF EXISTS stock_levels;
+DROP TABLE IF EXISTS warehouses;
\ No newline at end of file
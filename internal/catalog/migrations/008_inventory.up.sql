@@ -0,0 +1,87 @@
+-- Ensure we're in the catalog schema
+SET search_path TO catalog;
+
+-- Warehouses holding stock
+CREATE TABLE IF NOT EXISTS warehouses (
+    id SERIAL PRIMARY KEY,
+    code VARCHAR(50) UNIQUE NOT NULL,
+    name VARCHAR(255) NOT NULL,
+    address TEXT,
+    is_active BOOLEAN DEFAULT true,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX IF NOT EXISTS idx_warehouses_deleted_at ON warehouses(deleted_at);
+
+-- Stock of a product (or one of its variants) in a warehouse. Reserved units are still
+-- on hand but no longer available; neither can go negative.
+CREATE TABLE IF NOT EXISTS stock_levels (
+    id SERIAL PRIMARY KEY,
+    warehouse_id INTEGER NOT NULL REFERENCES warehouses(id) ON DELETE CASCADE,
+    product_id INTEGER NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+    variant_id INTEGER REFERENCES product_variants(id) ON DELETE CASCADE,
+    on_hand INTEGER NOT NULL DEFAULT 0 CHECK (on_hand >= 0),
+    reserved INTEGER NOT NULL DEFAULT 0 CHECK (reserved >= 0),
+    low_stock_threshold INTEGER NOT NULL DEFAULT 0 CHECK (low_stock_threshold >= 0),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    CHECK (reserved <= on_hand)
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_stock_levels_item
+    ON stock_levels(warehouse_id, product_id, COALESCE(variant_id, 0));
+CREATE INDEX IF NOT EXISTS idx_stock_levels_product_id ON stock_levels(product_id);
+CREATE INDEX IF NOT EXISTS idx_stock_levels_variant_id ON stock_levels(variant_id);
+
+-- Every stock change, with the signed quantity and the resulting on-hand quantity
+CREATE TABLE IF NOT EXISTS stock_movements (
+    id SERIAL PRIMARY KEY,
+    stock_level_id INTEGER NOT NULL REFERENCES stock_levels(id) ON DELETE CASCADE,
+    type VARCHAR(20) NOT NULL CHECK (type IN ('receipt', 'sale', 'adjustment', 'return')),
+    quantity INTEGER NOT NULL CHECK (quantity <> 0),
+    on_hand_after INTEGER NOT NULL,
+    reservation_id INTEGER,
+    reference VARCHAR(255),
+    note TEXT,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_stock_movements_stock_level_id ON stock_movements(stock_level_id, created_at);
+
+-- Units held for a pending order until fulfilled, released or expired
+CREATE TABLE IF NOT EXISTS stock_reservations (
+    id SERIAL PRIMARY KEY,
+    stock_level_id INTEGER NOT NULL REFERENCES stock_levels(id) ON DELETE CASCADE,
+    quantity INTEGER NOT NULL CHECK (quantity > 0),
+    status VARCHAR(20) NOT NULL DEFAULT 'active' CHECK (status IN ('active', 'fulfilled', 'released', 'expired')),
+    reference VARCHAR(255),
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_stock_reservations_stock_level_id ON stock_reservations(stock_level_id);
+CREATE INDEX IF NOT EXISTS idx_stock_reservations_expiry ON stock_reservations(expires_at) WHERE status = 'active';
+
+ALTER TABLE stock_movements ADD CONSTRAINT fk_stock_movements_reservation
+    FOREIGN KEY (reservation_id) REFERENCES stock_reservations(id) ON DELETE SET NULL;
+
+-- Move the stock recorded on variants into a default warehouse, as an opening receipt
+INSERT INTO warehouses (code, name)
+SELECT 'MAIN', 'Main warehouse'
+WHERE EXISTS (SELECT 1 FROM product_variants WHERE stock_quantity > 0 AND deleted_at IS NULL)
+ON CONFLICT (code) DO NOTHING;
+
+INSERT INTO stock_levels (warehouse_id, product_id, variant_id, on_hand)
+SELECT w.id, v.product_id, v.id, v.stock_quantity
+FROM product_variants v
+CROSS JOIN warehouses w
+WHERE w.code = 'MAIN' AND v.stock_quantity > 0 AND v.deleted_at IS NULL
+ON CONFLICT DO NOTHING;
+
+INSERT INTO stock_movements (stock_level_id, type, quantity, on_hand_after, reference)
+SELECT id, 'receipt', on_hand, on_hand, 'Opening stock'
+FROM stock_levels
+WHERE on_hand > 0;
\ No newline at end of file
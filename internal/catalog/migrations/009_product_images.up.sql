@@ -0,0 +1,23 @@
+-- Ensure we're in the catalog schema
+SET search_path TO catalog;
+
+-- Images of products and variants. Files live in the media storage under storage_key;
+-- thumbnail_key is the generated thumbnail.
+CREATE TABLE IF NOT EXISTS product_images (
+    id SERIAL PRIMARY KEY,
+    product_id INTEGER NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+    variant_id INTEGER REFERENCES product_variants(id) ON DELETE CASCADE,
+    storage_key VARCHAR(500) NOT NULL UNIQUE,
+    thumbnail_key VARCHAR(500),
+    content_type VARCHAR(100) NOT NULL,
+    size BIGINT NOT NULL DEFAULT 0 CHECK (size >= 0),
+    width INTEGER NOT NULL DEFAULT 0,
+    height INTEGER NOT NULL DEFAULT 0,
+    alt_text VARCHAR(255),
+    sort_order INTEGER DEFAULT 0,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_product_images_product_id ON product_images(product_id, sort_order);
+CREATE INDEX IF NOT EXISTS idx_product_images_variant_id ON product_images(variant_id);
\ No newline at end of file
@@ -0,0 +1,7 @@
+-- Ensure we're in the catalog schema
+SET search_path TO catalog;
+
+DROP INDEX IF EXISTS idx_product_variants_attributes;
+DROP INDEX IF EXISTS idx_products_attributes;
+ALTER TABLE products DROP COLUMN IF EXISTS attributes;
+DROP TABLE IF EXISTS category_attributes;
\ No newline at end of file
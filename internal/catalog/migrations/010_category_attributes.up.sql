@@ -0,0 +1,31 @@
+-- Ensure we're in the catalog schema
+SET search_path TO catalog;
+
+-- Attribute schemas of categories, inherited by subcategories. Values are stored as
+-- strings in the attributes JSONB objects of products (level 'product') and variants
+-- (level 'variant').
+CREATE TABLE IF NOT EXISTS category_attributes (
+    id SERIAL PRIMARY KEY,
+    category_id INTEGER NOT NULL REFERENCES categories(id) ON DELETE CASCADE,
+    name VARCHAR(50) NOT NULL,
+    label VARCHAR(100),
+    type VARCHAR(20) NOT NULL CHECK (type IN ('text', 'number', 'boolean', 'enum')),
+    unit VARCHAR(20),
+    allowed_values JSONB NOT NULL DEFAULT '[]',
+    level VARCHAR(20) NOT NULL DEFAULT 'product' CHECK (level IN ('product', 'variant')),
+    is_required BOOLEAN DEFAULT false,
+    is_filterable BOOLEAN DEFAULT false,
+    sort_order INTEGER DEFAULT 0,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (category_id, name)
+);
+
+CREATE INDEX IF NOT EXISTS idx_category_attributes_filterable ON category_attributes(name) WHERE is_filterable;
+
+-- Product-level attributes
+ALTER TABLE products ADD COLUMN IF NOT EXISTS attributes JSONB NOT NULL DEFAULT '{}';
+
+-- Attribute filters in product listings
+CREATE INDEX IF NOT EXISTS idx_products_attributes ON products USING GIN (attributes);
+CREATE INDEX IF NOT EXISTS idx_product_variants_attributes ON product_variants USING GIN (attributes);
\ No newline at end of file
@@ -0,0 +1,10 @@
+-- Ensure we're in the catalog schema
+SET search_path TO catalog;
+
+DROP INDEX IF EXISTS idx_product_variants_gtin;
+DROP INDEX IF EXISTS idx_products_gtin;
+ALTER TABLE product_variants DROP COLUMN IF EXISTS gtin;
+ALTER TABLE products DROP COLUMN IF EXISTS gtin;
+DROP TABLE IF EXISTS sku_sequences;
+DROP INDEX IF EXISTS idx_categories_code;
+ALTER TABLE categories DROP COLUMN IF EXISTS code;
\ No newline at end of file
@@ -0,0 +1,19 @@
+-- Ensure we're in the catalog schema
+SET search_path TO catalog;
+
+-- Short category codes used in generated SKUs (SKU-SHO-0004275)
+ALTER TABLE categories ADD COLUMN IF NOT EXISTS code VARCHAR(10);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_categories_code ON categories(code) WHERE code IS NOT NULL AND code <> '' AND deleted_at IS NULL;
+
+-- One row per SKU prefix (and category code); last_value is the last allocated number
+CREATE TABLE IF NOT EXISTS sku_sequences (
+    scope VARCHAR(100) PRIMARY KEY,
+    last_value BIGINT NOT NULL DEFAULT 0,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+-- GTIN/EAN barcodes, unique across live products and variants
+ALTER TABLE products ADD COLUMN IF NOT EXISTS gtin VARCHAR(14);
+ALTER TABLE product_variants ADD COLUMN IF NOT EXISTS gtin VARCHAR(14);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_products_gtin ON products(gtin) WHERE gtin IS NOT NULL AND gtin <> '' AND deleted_at IS NULL;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_product_variants_gtin ON product_variants(gtin) WHERE gtin IS NOT NULL AND gtin <> '' AND deleted_at IS NULL;
\ No newline at end of file
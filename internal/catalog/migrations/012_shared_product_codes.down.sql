@@ -0,0 +1,6 @@
+-- Ensure we're in the catalog schema
+SET search_path TO catalog;
+
+DROP TRIGGER IF EXISTS ensure_shared_variant_codes ON product_variants;
+DROP TRIGGER IF EXISTS ensure_shared_product_codes ON products;
+DROP FUNCTION IF EXISTS check_shared_product_codes();
@@ -0,0 +1,43 @@
+-- Ensure we're in the catalog schema
+SET search_path TO catalog;
+
+-- Products and variants share SKUs and GTINs. The unique indexes cover each table only,
+-- so this trigger checks the other table. Writers of the same code are serialized with a
+-- transaction lock, so two concurrent inserts cannot both pass the check. Like the indexes,
+-- SKUs stay taken by soft-deleted rows while GTINs are only unique among live rows.
+CREATE OR REPLACE FUNCTION check_shared_product_codes()
+RETURNS TRIGGER AS $$
+BEGIN
+    PERFORM pg_advisory_xact_lock(hashtext('sku:' || NEW.sku));
+    IF TG_TABLE_NAME = 'products' THEN
+        IF EXISTS (SELECT 1 FROM product_variants WHERE sku = NEW.sku) THEN
+            RAISE EXCEPTION 'SKU % is already used by a variant', NEW.sku USING ERRCODE = 'unique_violation';
+        END IF;
+    ELSIF EXISTS (SELECT 1 FROM products WHERE sku = NEW.sku) THEN
+        RAISE EXCEPTION 'SKU % is already used by a product', NEW.sku USING ERRCODE = 'unique_violation';
+    END IF;
+
+    IF NEW.gtin IS NOT NULL AND NEW.gtin <> '' AND NEW.deleted_at IS NULL THEN
+        PERFORM pg_advisory_xact_lock(hashtext('gtin:' || NEW.gtin));
+        IF TG_TABLE_NAME = 'products' THEN
+            IF EXISTS (SELECT 1 FROM product_variants WHERE gtin = NEW.gtin AND deleted_at IS NULL) THEN
+                RAISE EXCEPTION 'GTIN % is already used by a variant', NEW.gtin USING ERRCODE = 'unique_violation';
+            END IF;
+        ELSIF EXISTS (SELECT 1 FROM products WHERE gtin = NEW.gtin AND deleted_at IS NULL) THEN
+            RAISE EXCEPTION 'GTIN % is already used by a product', NEW.gtin USING ERRCODE = 'unique_violation';
+        END IF;
+    END IF;
+
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER ensure_shared_product_codes
+BEFORE INSERT OR UPDATE OF sku, gtin, deleted_at ON products
+FOR EACH ROW
+EXECUTE FUNCTION check_shared_product_codes();
+
+CREATE TRIGGER ensure_shared_variant_codes
+BEFORE INSERT OR UPDATE OF sku, gtin, deleted_at ON product_variants
+FOR EACH ROW
+EXECUTE FUNCTION check_shared_product_codes();
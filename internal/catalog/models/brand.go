@@ -0,0 +1,69 @@
+package models
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Brand represents a product brand in the catalog domain
+type Brand struct {
+	ID          uint           `json:"id" gorm:"primarykey"`
+	Name        string         `json:"name" gorm:"uniqueIndex;not null"`
+	Description string         `json:"description"`
+	Website     string         `json:"website"`
+	IsActive    bool           `json:"is_active" gorm:"not null"` // Always set on insert, true unless created inactive
+	CreatedAt   time.Time      `json:"created_at"`
+	UpdatedAt   time.Time      `json:"updated_at"`
+	DeletedAt   gorm.DeletedAt `json:"deleted_at,omitempty" gorm:"index"`
+}
+
+// Validate validates brand business rules
+func (b *Brand) Validate() error {
+	if strings.TrimSpace(b.Name) == "" {
+		return fmt.Errorf("brand name is required")
+	}
+
+	if len(b.Name) > 255 {
+		return fmt.Errorf("brand name cannot exceed 255 characters")
+	}
+
+	return validateWebsite(b.Website)
+}
+
+// CreateBrandRequest represents the request to create a new brand
+type CreateBrandRequest struct {
+	Name        string `json:"name" binding:"required"`
+	Description string `json:"description"`
+	Website     string `json:"website"`
+	IsActive    *bool  `json:"is_active"`
+}
+
+// UpdateBrandRequest represents the request to update a brand
+type UpdateBrandRequest struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Website     string `json:"website"`
+	IsActive    *bool  `json:"is_active"`
+}
+
+// validateWebsite accepts an empty value or an absolute http(s) URL of at most 500 characters
+func validateWebsite(website string) error {
+	if website == "" {
+		return nil
+	}
+
+	if len(website) > 500 {
+		return fmt.Errorf("brand website cannot exceed 500 characters")
+	}
+
+	u, err := url.Parse(website)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("brand website must be an http or https URL")
+	}
+
+	return nil
+}
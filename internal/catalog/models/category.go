@@ -1,6 +1,7 @@
 package models
 
 import (
+	"errors"
 	"fmt"
 	"strings"
 	"time"
@@ -12,13 +13,16 @@ import (
 type Category struct {
 	ID          uint           `json:"id" gorm:"primarykey"`
 	Name        string         `json:"name" gorm:"not null"`
+	Code        string         `json:"code"` // Used in generated SKUs, see CategorySKUCode
 	Description string         `json:"description"`
 	ParentID    *uint          `json:"parent_id"`
 	Parent      *Category      `json:"parent,omitempty"`
 	Children    []Category     `json:"children,omitempty" gorm:"foreignKey:ParentID"`
-	Products    []Product      `json:"products,omitempty"`
+	Products    []Product      `json:"products,omitempty" gorm:"many2many:product_categories"`
 	IsActive    bool           `json:"is_active" gorm:"default:true"`
 	SortOrder   int            `json:"sort_order" gorm:"default:0"`
+	Path        string         `json:"path"`                   // Materialized path of IDs from the root, e.g. "/1/4/9/"
+	Depth       int            `json:"depth" gorm:"default:0"` // 0 for root categories
 	CreatedAt   time.Time      `json:"created_at"`
 	UpdatedAt   time.Time      `json:"updated_at"`
 	DeletedAt   gorm.DeletedAt `json:"deleted_at,omitempty" gorm:"index"`
@@ -38,6 +42,12 @@ func (c *Category) Validate() error {
 		return fmt.Errorf("category description cannot exceed 500 characters")
 	}
 
+	if c.Code != "" {
+		if err := ValidateCategoryCode(c.Code); err != nil {
+			return err
+		}
+	}
+
 	// Prevent self-reference
 	if c.ParentID != nil && *c.ParentID == c.ID {
 		return fmt.Errorf("category cannot be its own parent")
@@ -46,13 +56,16 @@ func (c *Category) Validate() error {
 	return nil
 }
 
-// GetDepth calculates the depth of the category in the hierarchy
+// GetDepth calculates the depth of the category in the hierarchy.
+// It stops at the first repeated category if the loaded parents form a cycle.
 func (c *Category) GetDepth() int {
 	depth := 0
+	visited := map[*Category]bool{c: true}
 	current := c
-	for current.Parent != nil {
+	for current.Parent != nil && !visited[current.Parent] {
 		depth++
 		current = current.Parent
+		visited[current] = true
 	}
 	return depth
 }
@@ -67,17 +80,22 @@ func (c *Category) HasChildren() bool {
 	return len(c.Children) > 0
 }
 
-// GetFullPath returns the full path of the category (e.g., "Electronics > Computers > Laptops")
+// GetFullPath returns the full path of the category (e.g., "Electronics > Computers > Laptops").
+// It stops at the first repeated category if the loaded parents form a cycle.
 func (c *Category) GetFullPath() string {
-	if c.Parent == nil {
-		return c.Name
+	names := []string{c.Name}
+	visited := map[*Category]bool{c: true}
+	for current := c.Parent; current != nil && !visited[current]; current = current.Parent {
+		names = append([]string{current.Name}, names...)
+		visited[current] = true
 	}
-	return c.Parent.GetFullPath() + " > " + c.Name
+	return strings.Join(names, " > ")
 }
 
 // CreateCategoryRequest represents the request to create a new category
 type CreateCategoryRequest struct {
 	Name        string `json:"name" binding:"required"`
+	Code        string `json:"code"` // Optional SKU code, e.g. SHOES
 	Description string `json:"description"`
 	ParentID    *uint  `json:"parent_id"`
 	IsActive    *bool  `json:"is_active"`
@@ -98,16 +116,24 @@ func (r *CreateCategoryRequest) Validate() error {
 		return fmt.Errorf("category description cannot exceed 500 characters")
 	}
 
+	if r.Code != "" {
+		r.Code = strings.ToUpper(strings.TrimSpace(r.Code))
+		if err := ValidateCategoryCode(r.Code); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
 // UpdateCategoryRequest represents the request to update a category
 type UpdateCategoryRequest struct {
-	Name        string `json:"name"`
-	Description string `json:"description"`
-	ParentID    *uint  `json:"parent_id"`
-	IsActive    *bool  `json:"is_active"`
-	SortOrder   *int   `json:"sort_order"`
+	Name        string  `json:"name"`
+	Code        *string `json:"code"` // Empty removes the code
+	Description string  `json:"description"`
+	ParentID    *uint   `json:"parent_id"`
+	IsActive    *bool   `json:"is_active"`
+	SortOrder   *int    `json:"sort_order"`
 }
 
 // Validate validates the update category request
@@ -120,6 +146,15 @@ func (r *UpdateCategoryRequest) Validate() error {
 		return fmt.Errorf("category description cannot exceed 500 characters")
 	}
 
+	if r.Code != nil {
+		*r.Code = strings.ToUpper(strings.TrimSpace(*r.Code))
+		if *r.Code != "" {
+			if err := ValidateCategoryCode(*r.Code); err != nil {
+				return err
+			}
+		}
+	}
+
 	return nil
 }
 
@@ -132,6 +167,8 @@ type CategoryWithProductCount struct {
 // MoveCategoryRequest represents the request to move a category to a different parent
 type MoveCategoryRequest struct {
 	NewParentID *uint `json:"new_parent_id"`
+	BeforeID    *uint `json:"before_id"` // Sibling to insert before under the new parent
+	AfterID     *uint `json:"after_id"`  // Sibling to insert after under the new parent
 }
 
 // Validate validates the move category request
@@ -141,5 +178,60 @@ func (r *MoveCategoryRequest) Validate(categoryID uint) error {
 		return fmt.Errorf("category cannot be moved to itself")
 	}
 
+	if r.BeforeID != nil && r.AfterID != nil {
+		return fmt.Errorf("before_id and after_id cannot both be set")
+	}
+
+	return nil
+}
+
+// ErrCategoryCycle is returned when a category would become its own ancestor
+var ErrCategoryCycle = errors.New("category cannot be moved under itself or one of its descendants")
+
+// CategoryPlacement describes placing a category, with its subtree, under a parent.
+// Depths start at 0 for root categories, as in GetDepth.
+type CategoryPlacement struct {
+	CategoryID    uint   // 0 for a new category
+	ParentID      *uint  // nil places the category at the root
+	ParentDepth   int    // Depth of the parent
+	SubtreeHeight int    // Levels of descendants below the category
+	Descendants   []uint // IDs of all descendants of the category
+}
+
+// CreatesCycle reports whether the parent is the category itself or one of its descendants
+func (p CategoryPlacement) CreatesCycle() bool {
+	if p.ParentID == nil {
+		return false
+	}
+	if *p.ParentID == p.CategoryID {
+		return true
+	}
+	for _, id := range p.Descendants {
+		if id == *p.ParentID {
+			return true
+		}
+	}
+	return false
+}
+
+// Depth returns the depth of the deepest category of the placed subtree
+func (p CategoryPlacement) Depth() int {
+	if p.ParentID == nil {
+		return p.SubtreeHeight
+	}
+	return p.ParentDepth + 1 + p.SubtreeHeight
+}
+
+// Validate checks the placement against the hierarchy rules. maxDepth is the number of
+// levels allowed (0 or less means unlimited). Cycles are always rejected.
+func (p CategoryPlacement) Validate(maxDepth int) error {
+	if p.CreatesCycle() {
+		return ErrCategoryCycle
+	}
+
+	if maxDepth > 0 && p.Depth() >= maxDepth {
+		return fmt.Errorf("category hierarchy cannot exceed %d levels", maxDepth)
+	}
+
 	return nil
 }
\ No newline at end of file
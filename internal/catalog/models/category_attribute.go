@@ -0,0 +1,306 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// AttributeType is the type of the values of a category attribute. Values are stored as
+// strings in the attributes JSONB objects, in the canonical form of their type.
+type AttributeType string
+
+const (
+	AttributeTypeText    AttributeType = "text"
+	AttributeTypeNumber  AttributeType = "number"  // Decimal number, in Unit when set
+	AttributeTypeBoolean AttributeType = "boolean" // "true" or "false"
+	AttributeTypeEnum    AttributeType = "enum"    // One of AllowedValues
+)
+
+// AttributeLevel tells whether an attribute describes the product as a whole (material,
+// warranty) or distinguishes its variants (size, color)
+type AttributeLevel string
+
+const (
+	AttributeLevelProduct AttributeLevel = "product"
+	AttributeLevelVariant AttributeLevel = "variant"
+)
+
+// attributeNamePattern matches attribute names: lowercase letters, digits and underscores
+var attributeNamePattern = regexp.MustCompile(`^[a-z][a-z0-9_]*$`)
+
+// CategoryAttribute defines an attribute of the products of a category and of its
+// subcategories. A subcategory attribute with the same name overrides the inherited one.
+type CategoryAttribute struct {
+	ID            uint            `json:"id" gorm:"primarykey"`
+	CategoryID    uint            `json:"category_id" gorm:"not null;index"`
+	Name          string          `json:"name" gorm:"not null"`
+	Label         string          `json:"label"`
+	Type          AttributeType   `json:"type" gorm:"not null"`
+	Unit          string          `json:"unit"`
+	AllowedValues AttributeValues `json:"allowed_values" gorm:"type:jsonb"`
+	Level         AttributeLevel  `json:"level" gorm:"default:'product'"`
+	IsRequired    bool            `json:"is_required" gorm:"default:false"`
+	IsFilterable  bool            `json:"is_filterable" gorm:"default:false"`
+	SortOrder     int             `json:"sort_order" gorm:"default:0"`
+	CreatedAt     time.Time       `json:"created_at"`
+	UpdatedAt     time.Time       `json:"updated_at"`
+}
+
+// AttributeValues are the allowed values of an enum attribute, stored as a JSONB array
+type AttributeValues []string
+
+// Value implements driver.Valuer
+func (v AttributeValues) Value() (driver.Value, error) {
+	if v == nil {
+		return "[]", nil
+	}
+	data, err := json.Marshal(v)
+	return string(data), err
+}
+
+// Scan implements sql.Scanner
+func (v *AttributeValues) Scan(src interface{}) error {
+	var data []byte
+	switch value := src.(type) {
+	case nil:
+		*v = AttributeValues{}
+		return nil
+	case []byte:
+		data = value
+	case string:
+		data = []byte(value)
+	default:
+		return fmt.Errorf("cannot scan %T into AttributeValues", src)
+	}
+	return json.Unmarshal(data, v)
+}
+
+// Validate validates category attribute business rules
+func (a *CategoryAttribute) Validate() error {
+	a.Name = strings.ToLower(strings.TrimSpace(a.Name))
+	if a.Name == "" {
+		return fmt.Errorf("attribute name is required")
+	}
+
+	if len(a.Name) > 50 || !attributeNamePattern.MatchString(a.Name) {
+		return fmt.Errorf("attribute name must be at most 50 lowercase letters, digits or underscores, starting with a letter")
+	}
+
+	if len(a.Label) > 100 {
+		return fmt.Errorf("attribute label cannot exceed 100 characters")
+	}
+
+	switch a.Type {
+	case AttributeTypeText, AttributeTypeNumber, AttributeTypeBoolean, AttributeTypeEnum:
+	default:
+		return fmt.Errorf("invalid attribute type: %s", a.Type)
+	}
+
+	switch a.Level {
+	case AttributeLevelProduct, AttributeLevelVariant:
+	default:
+		return fmt.Errorf("invalid attribute level: %s", a.Level)
+	}
+
+	if a.Unit != "" && a.Type != AttributeTypeNumber {
+		return fmt.Errorf("only number attributes can have a unit")
+	}
+
+	if len(a.Unit) > 20 {
+		return fmt.Errorf("attribute unit cannot exceed 20 characters")
+	}
+
+	if a.Type != AttributeTypeEnum {
+		if len(a.AllowedValues) > 0 {
+			return fmt.Errorf("only enum attributes can have allowed values")
+		}
+		return nil
+	}
+
+	if len(a.AllowedValues) == 0 {
+		return fmt.Errorf("enum attributes need at least one allowed value")
+	}
+	seen := make(map[string]bool, len(a.AllowedValues))
+	for i, value := range a.AllowedValues {
+		value = strings.TrimSpace(value)
+		if value == "" || len(value) > 100 {
+			return fmt.Errorf("allowed values must be 1 to 100 characters")
+		}
+		if seen[strings.ToLower(value)] {
+			return fmt.Errorf("allowed value %q is listed more than once", value)
+		}
+		seen[strings.ToLower(value)] = true
+		a.AllowedValues[i] = value
+	}
+
+	return nil
+}
+
+// NormalizeValue checks a value against the attribute type and returns its canonical form
+func (a *CategoryAttribute) NormalizeValue(value string) (string, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return "", nil
+	}
+
+	switch a.Type {
+	case AttributeTypeNumber:
+		number, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return "", fmt.Errorf("attribute %q must be a number", a.Name)
+		}
+		return strconv.FormatFloat(number, 'f', -1, 64), nil
+	case AttributeTypeBoolean:
+		boolean, err := strconv.ParseBool(value)
+		if err != nil {
+			return "", fmt.Errorf("attribute %q must be true or false", a.Name)
+		}
+		return strconv.FormatBool(boolean), nil
+	case AttributeTypeEnum:
+		for _, allowed := range a.AllowedValues {
+			if strings.EqualFold(allowed, value) {
+				return allowed, nil
+			}
+		}
+		return "", fmt.Errorf("attribute %q must be one of: %s", a.Name, strings.Join(a.AllowedValues, ", "))
+	default:
+		return value, nil
+	}
+}
+
+// NormalizeFilterValue returns the canonical forms of a filter value under the definitions
+// of one attribute name. Categories may define the same name with different types, so the
+// value is only rejected when no definition accepts it.
+func NormalizeFilterValue(definitions []CategoryAttribute, value string) ([]string, error) {
+	var normalized []string
+	var firstErr error
+	for i := range definitions {
+		canonical, err := definitions[i].NormalizeValue(value)
+		if err != nil {
+			if firstErr == nil {
+				firstErr = err
+			}
+			continue
+		}
+		if !containsString(normalized, canonical) {
+			normalized = append(normalized, canonical)
+		}
+	}
+	if len(normalized) == 0 && firstErr != nil {
+		return nil, firstErr
+	}
+	return normalized, nil
+}
+
+// containsString reports whether value is one of values
+func containsString(values []string, value string) bool {
+	for _, candidate := range values {
+		if candidate == value {
+			return true
+		}
+	}
+	return false
+}
+
+// AttributeSchema is the set of attributes applying to the products of a category: its own
+// attributes and those inherited from its ancestors
+type AttributeSchema []CategoryAttribute
+
+// BuildAttributeSchema merges the attributes of a category and its ancestors, given as
+// categoryIDs from the root down to the category. The nearest definition of a name wins.
+func BuildAttributeSchema(attributes []CategoryAttribute, categoryIDs []uint) AttributeSchema {
+	depth := make(map[uint]int, len(categoryIDs))
+	for i, id := range categoryIDs {
+		depth[id] = i
+	}
+
+	byName := map[string]CategoryAttribute{}
+	for _, attribute := range attributes {
+		attributeDepth, ok := depth[attribute.CategoryID]
+		if !ok {
+			continue
+		}
+		if current, exists := byName[attribute.Name]; exists && depth[current.CategoryID] > attributeDepth {
+			continue
+		}
+		byName[attribute.Name] = attribute
+	}
+
+	schema := make(AttributeSchema, 0, len(byName))
+	for _, attribute := range byName {
+		schema = append(schema, attribute)
+	}
+	sort.Slice(schema, func(i, j int) bool {
+		if schema[i].SortOrder != schema[j].SortOrder {
+			return schema[i].SortOrder < schema[j].SortOrder
+		}
+		return schema[i].Name < schema[j].Name
+	})
+	return schema
+}
+
+// Find returns the attribute with the given name, or nil
+func (s AttributeSchema) Find(name string) *CategoryAttribute {
+	for i := range s {
+		if s[i].Name == name {
+			return &s[i]
+		}
+	}
+	return nil
+}
+
+// ValidateAttributes checks the product or variant attributes against the schema and
+// returns them in canonical form. Every attribute must be defined at that level and all
+// required ones must be set. Categories without a schema accept any attributes.
+func (s AttributeSchema) ValidateAttributes(attributes VariantAttributes, level AttributeLevel) (VariantAttributes, error) {
+	if len(s) == 0 {
+		return attributes, nil
+	}
+
+	validated := make(VariantAttributes, len(attributes))
+	for name, value := range attributes {
+		attribute := s.Find(name)
+		if attribute == nil {
+			return nil, fmt.Errorf("attribute %q is not defined for this category", name)
+		}
+		if attribute.Level != level {
+			return nil, fmt.Errorf("attribute %q is a %s attribute", name, attribute.Level)
+		}
+
+		normalized, err := attribute.NormalizeValue(value)
+		if err != nil {
+			return nil, err
+		}
+		if normalized == "" {
+			continue
+		}
+		validated[name] = normalized
+	}
+
+	for _, attribute := range s {
+		if attribute.Level == level && attribute.IsRequired && validated[attribute.Name] == "" {
+			return nil, fmt.Errorf("attribute %q is required", attribute.Name)
+		}
+	}
+
+	return validated, nil
+}
+
+// CategoryAttributeRequest represents the request to create or update a category attribute
+type CategoryAttributeRequest struct {
+	Name          string          `json:"name" binding:"required"`
+	Label         string          `json:"label"`
+	Type          AttributeType   `json:"type" binding:"required"`
+	Unit          string          `json:"unit"`
+	AllowedValues AttributeValues `json:"allowed_values"`
+	Level         AttributeLevel  `json:"level"` // Defaults to product
+	IsRequired    bool            `json:"is_required"`
+	IsFilterable  bool            `json:"is_filterable"`
+	SortOrder     int             `json:"sort_order"`
+}
@@ -0,0 +1,113 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCategoryAttributeValidate(t *testing.T) {
+	attribute := CategoryAttribute{Name: " Screen_Size ", Type: AttributeTypeNumber, Unit: "in", Level: AttributeLevelProduct}
+	assert.NoError(t, attribute.Validate())
+	assert.Equal(t, "screen_size", attribute.Name)
+
+	attribute.Name = "screen size"
+	assert.Error(t, attribute.Validate())
+
+	attribute = CategoryAttribute{Name: "color", Type: AttributeTypeEnum, Level: AttributeLevelVariant}
+	assert.Error(t, attribute.Validate(), "enum attributes need allowed values")
+
+	attribute.AllowedValues = AttributeValues{"Red", "red"}
+	assert.Error(t, attribute.Validate(), "allowed values are unique, ignoring case")
+
+	attribute.AllowedValues = AttributeValues{"Red", "Blue"}
+	assert.NoError(t, attribute.Validate())
+
+	attribute.Unit = "cm"
+	assert.Error(t, attribute.Validate(), "only numbers have a unit")
+
+	attribute = CategoryAttribute{Name: "waterproof", Type: "bool", Level: AttributeLevelProduct}
+	assert.Error(t, attribute.Validate())
+}
+
+func TestCategoryAttributeNormalizeValue(t *testing.T) {
+	number := CategoryAttribute{Name: "weight", Type: AttributeTypeNumber}
+	value, err := number.NormalizeValue(" 1.50 ")
+	require.NoError(t, err)
+	assert.Equal(t, "1.5", value)
+	_, err = number.NormalizeValue("heavy")
+	assert.Error(t, err)
+
+	boolean := CategoryAttribute{Name: "waterproof", Type: AttributeTypeBoolean}
+	value, err = boolean.NormalizeValue("TRUE")
+	require.NoError(t, err)
+	assert.Equal(t, "true", value)
+
+	enum := CategoryAttribute{Name: "color", Type: AttributeTypeEnum, AllowedValues: AttributeValues{"Red", "Blue"}}
+	value, err = enum.NormalizeValue("blue")
+	require.NoError(t, err)
+	assert.Equal(t, "Blue", value, "enum values take the case of the allowed value")
+	_, err = enum.NormalizeValue("green")
+	assert.Error(t, err)
+}
+
+func TestNormalizeFilterValue(t *testing.T) {
+	definitions := []CategoryAttribute{
+		{Name: "size", Type: AttributeTypeEnum, AllowedValues: AttributeValues{"S", "M", "L"}},
+		{Name: "size", Type: AttributeTypeNumber},
+	}
+
+	values, err := NormalizeFilterValue(definitions, "42.0")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"42"}, values, "valid for the number definition only")
+
+	values, err = NormalizeFilterValue(definitions, "m")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"M"}, values)
+
+	_, err = NormalizeFilterValue(definitions, "XXL")
+	assert.Error(t, err, "no definition accepts the value")
+}
+
+func TestBuildAttributeSchemaInheritance(t *testing.T) {
+	attributes := []CategoryAttribute{
+		{CategoryID: 1, Name: "brand_line", Type: AttributeTypeText, SortOrder: 2},
+		{CategoryID: 1, Name: "color", Type: AttributeTypeText, SortOrder: 1},
+		{CategoryID: 5, Name: "color", Type: AttributeTypeEnum, AllowedValues: AttributeValues{"Red"}, SortOrder: 1},
+		{CategoryID: 9, Name: "unrelated", Type: AttributeTypeText},
+	}
+
+	schema := BuildAttributeSchema(attributes, []uint{1, 5})
+	require.Len(t, schema, 2)
+	assert.Equal(t, "color", schema[0].Name)
+	assert.Equal(t, AttributeTypeEnum, schema[0].Type, "the subcategory definition overrides the inherited one")
+	assert.Equal(t, "brand_line", schema[1].Name)
+	assert.Nil(t, schema.Find("unrelated"))
+}
+
+func TestAttributeSchemaValidateAttributes(t *testing.T) {
+	schema := AttributeSchema{
+		{Name: "material", Type: AttributeTypeText, Level: AttributeLevelProduct, IsRequired: true},
+		{Name: "size", Type: AttributeTypeEnum, AllowedValues: AttributeValues{"S", "M", "L"}, Level: AttributeLevelVariant, IsRequired: true},
+		{Name: "weight", Type: AttributeTypeNumber, Level: AttributeLevelVariant},
+	}
+
+	validated, err := schema.ValidateAttributes(VariantAttributes{"size": "m", "weight": "0.250"}, AttributeLevelVariant)
+	require.NoError(t, err)
+	assert.Equal(t, VariantAttributes{"size": "M", "weight": "0.25"}, validated)
+
+	_, err = schema.ValidateAttributes(VariantAttributes{"weight": "1"}, AttributeLevelVariant)
+	assert.Error(t, err, "size is required")
+
+	_, err = schema.ValidateAttributes(VariantAttributes{"size": "M", "material": "wool"}, AttributeLevelVariant)
+	assert.Error(t, err, "material is a product attribute")
+
+	_, err = schema.ValidateAttributes(VariantAttributes{"material": "wool", "origin": "FR"}, AttributeLevelProduct)
+	assert.Error(t, err, "origin is not defined")
+
+	free := VariantAttributes{"anything": "goes"}
+	validated, err = AttributeSchema{}.ValidateAttributes(free, AttributeLevelProduct)
+	require.NoError(t, err)
+	assert.Equal(t, free, validated, "categories without a schema accept any attributes")
+}
@@ -0,0 +1,72 @@
+package models
+
+import "fmt"
+
+// ReorderChildrenRequest lists all children of a category in their new order
+type ReorderChildrenRequest struct {
+	ChildIDs []uint `json:"child_ids" binding:"required"`
+}
+
+// Validate checks that the request lists exactly the current children, once each
+func (r *ReorderChildrenRequest) Validate(currentIDs []uint) error {
+	current := make(map[uint]bool, len(currentIDs))
+	for _, id := range currentIDs {
+		current[id] = true
+	}
+
+	seen := make(map[uint]bool, len(r.ChildIDs))
+	for _, id := range r.ChildIDs {
+		if !current[id] {
+			return fmt.Errorf("category %d is not a child of this category", id)
+		}
+		if seen[id] {
+			return fmt.Errorf("category %d is listed more than once", id)
+		}
+		seen[id] = true
+	}
+
+	if len(seen) != len(current) {
+		return fmt.Errorf("child_ids must list all %d children of the category", len(current))
+	}
+
+	return nil
+}
+
+// PositionAmongSiblings inserts id into the ordered sibling IDs before beforeID or after
+// afterID; without either, it is appended. id is removed from its previous position first.
+func PositionAmongSiblings(siblingIDs []uint, id uint, beforeID, afterID *uint) ([]uint, error) {
+	if beforeID != nil && afterID != nil {
+		return nil, fmt.Errorf("before_id and after_id cannot both be set")
+	}
+
+	order := make([]uint, 0, len(siblingIDs)+1)
+	for _, siblingID := range siblingIDs {
+		if siblingID != id {
+			order = append(order, siblingID)
+		}
+	}
+
+	anchor := beforeID
+	if anchor == nil {
+		anchor = afterID
+	}
+	if anchor == nil {
+		return append(order, id), nil
+	}
+	if *anchor == id {
+		return nil, fmt.Errorf("category cannot be positioned relative to itself")
+	}
+
+	for i, siblingID := range order {
+		if siblingID != *anchor {
+			continue
+		}
+		if afterID != nil {
+			i++
+		}
+		order = append(order[:i], append([]uint{id}, order[i:]...)...)
+		return order, nil
+	}
+
+	return nil, fmt.Errorf("category %d is not a sibling at the destination", *anchor)
+}
@@ -0,0 +1,46 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReorderChildrenRequestValidate(t *testing.T) {
+	current := []uint{1, 2, 3}
+
+	assert.NoError(t, (&ReorderChildrenRequest{ChildIDs: []uint{3, 1, 2}}).Validate(current))
+	assert.Error(t, (&ReorderChildrenRequest{ChildIDs: []uint{3, 1}}).Validate(current), "missing child")
+	assert.Error(t, (&ReorderChildrenRequest{ChildIDs: []uint{3, 1, 2, 4}}).Validate(current), "not a child")
+	assert.Error(t, (&ReorderChildrenRequest{ChildIDs: []uint{3, 1, 1}}).Validate(current), "duplicate")
+	assert.NoError(t, (&ReorderChildrenRequest{ChildIDs: []uint{}}).Validate(nil))
+}
+
+func TestPositionAmongSiblings(t *testing.T) {
+	siblings := []uint{10, 20, 30}
+
+	order, err := PositionAmongSiblings(siblings, 5, uintPtr(20), nil)
+	require.NoError(t, err)
+	assert.Equal(t, []uint{10, 5, 20, 30}, order)
+
+	order, err = PositionAmongSiblings(siblings, 5, nil, uintPtr(30))
+	require.NoError(t, err)
+	assert.Equal(t, []uint{10, 20, 30, 5}, order)
+
+	order, err = PositionAmongSiblings(siblings, 5, nil, nil)
+	require.NoError(t, err)
+	assert.Equal(t, []uint{10, 20, 30, 5}, order)
+
+	order, err = PositionAmongSiblings(siblings, 30, uintPtr(10), nil)
+	require.NoError(t, err)
+	assert.Equal(t, []uint{30, 10, 20}, order, "an existing sibling is moved")
+	assert.Equal(t, []uint{10, 20, 30}, siblings, "input is left untouched")
+
+	_, err = PositionAmongSiblings(siblings, 5, uintPtr(99), nil)
+	assert.Error(t, err)
+	_, err = PositionAmongSiblings(siblings, 20, uintPtr(20), nil)
+	assert.Error(t, err)
+	_, err = PositionAmongSiblings(siblings, 5, uintPtr(10), uintPtr(20))
+	assert.Error(t, err)
+}
@@ -0,0 +1,43 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func uintPtr(v uint) *uint { return &v }
+
+func TestCategoryPlacementValidate(t *testing.T) {
+	// Category 2 has descendants 3 (child) and 4 (grandchild)
+	move := CategoryPlacement{CategoryID: 2, SubtreeHeight: 2, Descendants: []uint{3, 4}}
+
+	move.ParentID = uintPtr(4)
+	assert.ErrorIs(t, move.Validate(5), ErrCategoryCycle, "grandchild cannot become the parent")
+
+	move.ParentID = uintPtr(2)
+	assert.ErrorIs(t, move.Validate(0), ErrCategoryCycle)
+
+	move.ParentID = uintPtr(1)
+	move.ParentDepth = 1
+	assert.Equal(t, 4, move.Depth())
+	assert.NoError(t, move.Validate(5))
+	assert.Error(t, move.Validate(4), "the subtree would reach a fifth level")
+	assert.NoError(t, move.Validate(0), "no depth limit")
+
+	move.ParentID = nil
+	assert.Equal(t, 2, move.Depth())
+	assert.NoError(t, move.Validate(3))
+
+	create := CategoryPlacement{ParentID: uintPtr(9), ParentDepth: 4}
+	assert.Error(t, create.Validate(5))
+}
+
+func TestCategoryGetFullPath_Cycle(t *testing.T) {
+	parent := &Category{Name: "Computers"}
+	child := &Category{Name: "Laptops", Parent: parent}
+	parent.Parent = child
+
+	assert.Equal(t, "Computers > Laptops", child.GetFullPath())
+	assert.Equal(t, 1, child.GetDepth())
+}
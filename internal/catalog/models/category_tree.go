@@ -0,0 +1,87 @@
+package models
+
+import (
+	"strconv"
+	"strings"
+)
+
+// MaxCategoryTreeDepth bounds hierarchy queries so a corrupted parent chain cannot
+// make them recurse forever
+const MaxCategoryTreeDepth = 100
+
+// CategoryTreeNode is a category in the nested category tree
+type CategoryTreeNode struct {
+	ID          uint                `json:"id"`
+	Name        string              `json:"name"`
+	Description string              `json:"description"`
+	ParentID    *uint               `json:"parent_id"`
+	IsActive    bool                `json:"is_active"`
+	SortOrder   int                 `json:"sort_order"`
+	Depth       int                 `json:"depth"` // Relative to the root of the tree
+	Children    []*CategoryTreeNode `json:"children" gorm:"-"`
+}
+
+// BuildCategoryTree nests flat tree rows under their parents. Rows must be ordered
+// parents first; the order of siblings is kept. Rows whose parent is not part of the
+// result are returned as roots.
+func BuildCategoryTree(rows []CategoryTreeNode) []*CategoryTreeNode {
+	nodes := make(map[uint]*CategoryTreeNode, len(rows))
+	roots := []*CategoryTreeNode{}
+
+	for i := range rows {
+		node := &rows[i]
+		node.Children = []*CategoryTreeNode{}
+		nodes[node.ID] = node
+
+		if node.ParentID != nil {
+			if parent, ok := nodes[*node.ParentID]; ok {
+				parent.Children = append(parent.Children, node)
+				continue
+			}
+		}
+		roots = append(roots, node)
+	}
+
+	return roots
+}
+
+// LinkCategoryPath links categories ordered from the root to the leaf through their
+// Parent field and returns the leaf, so GetDepth and GetFullPath work on it
+func LinkCategoryPath(path []Category) *Category {
+	if len(path) == 0 {
+		return nil
+	}
+	for i := 1; i < len(path); i++ {
+		path[i].Parent = &path[i-1]
+	}
+	return &path[len(path)-1]
+}
+
+// BuildCategoryPath returns the materialized path of a category under a parent with the
+// given path ("" for root categories)
+func BuildCategoryPath(parentPath string, id uint) string {
+	if parentPath == "" {
+		parentPath = "/"
+	}
+	return parentPath + strconv.FormatUint(uint64(id), 10) + "/"
+}
+
+// CategoryPathIDs returns the category IDs of a materialized path, from the root down
+func CategoryPathIDs(path string) []uint {
+	var ids []uint
+	for _, segment := range strings.Split(strings.Trim(path, "/"), "/") {
+		if id, err := strconv.ParseUint(segment, 10, 64); err == nil {
+			ids = append(ids, uint(id))
+		}
+	}
+	return ids
+}
+
+// CategoryPathDepth returns the depth of a category from its materialized path
+func CategoryPathDepth(path string) int {
+	depth := strings.Count(path, "/") - 2
+	if depth < 0 {
+		return 0
+	}
+	return depth
+}
@@ -0,0 +1,59 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildCategoryTree(t *testing.T) {
+	electronics, computers := uint(1), uint(2)
+	rows := []CategoryTreeNode{
+		{ID: 1, Name: "Electronics"},
+		{ID: 5, Name: "Books"},
+		{ID: 2, Name: "Computers", ParentID: &electronics, Depth: 1},
+		{ID: 3, Name: "Phones", ParentID: &electronics, Depth: 1},
+		{ID: 4, Name: "Laptops", ParentID: &computers, Depth: 2},
+	}
+
+	roots := BuildCategoryTree(rows)
+	require.Len(t, roots, 2)
+	assert.Equal(t, "Electronics", roots[0].Name)
+	assert.Equal(t, "Books", roots[1].Name)
+	assert.NotNil(t, roots[1].Children, "leaves have an empty children list")
+
+	require.Len(t, roots[0].Children, 2)
+	assert.Equal(t, "Computers", roots[0].Children[0].Name)
+	assert.Equal(t, "Phones", roots[0].Children[1].Name)
+	require.Len(t, roots[0].Children[0].Children, 1)
+	assert.Equal(t, "Laptops", roots[0].Children[0].Children[0].Name)
+}
+
+func TestBuildCategoryTree_SubtreeRoot(t *testing.T) {
+	electronics := uint(1)
+	roots := BuildCategoryTree([]CategoryTreeNode{{ID: 2, Name: "Computers", ParentID: &electronics}})
+	require.Len(t, roots, 1, "a parent outside the result makes the row a root")
+	assert.Equal(t, "Computers", roots[0].Name)
+}
+
+func TestLinkCategoryPath(t *testing.T) {
+	leaf := LinkCategoryPath([]Category{{Name: "Electronics"}, {Name: "Computers"}, {Name: "Laptops"}})
+	require.NotNil(t, leaf)
+	assert.Equal(t, "Electronics > Computers > Laptops", leaf.GetFullPath())
+	assert.Equal(t, 2, leaf.GetDepth())
+
+	assert.Nil(t, LinkCategoryPath(nil))
+}
+
+func TestBuildCategoryPath(t *testing.T) {
+	root := BuildCategoryPath("", 1)
+	assert.Equal(t, "/1/", root)
+	assert.Equal(t, 0, CategoryPathDepth(root))
+
+	leaf := BuildCategoryPath(BuildCategoryPath(root, 4), 9)
+	assert.Equal(t, "/1/4/9/", leaf)
+	assert.Equal(t, 2, CategoryPathDepth(leaf))
+
+	assert.Equal(t, 0, CategoryPathDepth(""))
+}
@@ -0,0 +1,203 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Warehouse is a location holding stock
+type Warehouse struct {
+	ID        uint           `json:"id" gorm:"primarykey"`
+	Code      string         `json:"code" gorm:"uniqueIndex;not null"`
+	Name      string         `json:"name" gorm:"not null"`
+	Address   string         `json:"address"`
+	IsActive  bool           `json:"is_active" gorm:"not null"` // Always set on insert
+	CreatedAt time.Time      `json:"created_at"`
+	UpdatedAt time.Time      `json:"updated_at"`
+	DeletedAt gorm.DeletedAt `json:"deleted_at,omitempty" gorm:"index"`
+}
+
+// Validate validates warehouse business rules
+func (w *Warehouse) Validate() error {
+	w.Code = strings.ToUpper(strings.TrimSpace(w.Code))
+	if w.Code == "" {
+		return fmt.Errorf("warehouse code is required")
+	}
+
+	if len(w.Code) > 50 || !priceListCodePattern.MatchString(w.Code) {
+		return fmt.Errorf("warehouse code must be at most 50 letters, digits, '-' or '_'")
+	}
+
+	if strings.TrimSpace(w.Name) == "" {
+		return fmt.Errorf("warehouse name is required")
+	}
+
+	if len(w.Name) > 255 {
+		return fmt.Errorf("warehouse name cannot exceed 255 characters")
+	}
+
+	return nil
+}
+
+// StockLevel is the stock of a product, or one of its variants, in a warehouse.
+// Reserved units are still on hand but no longer available for sale.
+type StockLevel struct {
+	ID                uint       `json:"id" gorm:"primarykey"`
+	WarehouseID       uint       `json:"warehouse_id" gorm:"not null"`
+	Warehouse         *Warehouse `json:"warehouse,omitempty"`
+	ProductID         uint       `json:"product_id" gorm:"not null;index"`
+	VariantID         *uint      `json:"variant_id" gorm:"index"`
+	OnHand            int        `json:"on_hand" gorm:"default:0"`
+	Reserved          int        `json:"reserved" gorm:"default:0"`
+	Available         int        `json:"available" gorm:"-"`                   // Filled by WithAvailable
+	LowStockThreshold int        `json:"low_stock_threshold" gorm:"default:0"` // 0 disables low-stock alerts
+	IsLowStock        bool       `json:"is_low_stock" gorm:"-"`
+	CreatedAt         time.Time  `json:"created_at"`
+	UpdatedAt         time.Time  `json:"updated_at"`
+}
+
+// AvailableQuantity returns the units that can still be reserved or sold
+func (s *StockLevel) AvailableQuantity() int {
+	return s.OnHand - s.Reserved
+}
+
+// LowStock reports whether the available quantity reached the low-stock threshold
+func (s *StockLevel) LowStock() bool {
+	return s.LowStockThreshold > 0 && s.AvailableQuantity() <= s.LowStockThreshold
+}
+
+// WithAvailable fills the computed Available and IsLowStock fields
+func (s *StockLevel) WithAvailable() *StockLevel {
+	s.Available = s.AvailableQuantity()
+	s.IsLowStock = s.LowStock()
+	return s
+}
+
+// StockMovementType is the kind of a stock movement
+type StockMovementType string
+
+const (
+	StockMovementReceipt    StockMovementType = "receipt"    // Goods received, adds stock
+	StockMovementSale       StockMovementType = "sale"       // Goods sold, removes stock
+	StockMovementAdjustment StockMovementType = "adjustment" // Inventory count correction, either way
+	StockMovementReturn     StockMovementType = "return"     // Goods returned by a client, adds stock
+)
+
+// ErrInsufficientStock is returned when a movement or reservation needs more units than available
+var ErrInsufficientStock = errors.New("insufficient stock available")
+
+// StockMovement is a change of the on-hand stock of a stock level. Quantity is signed:
+// positive adds stock, negative removes it.
+type StockMovement struct {
+	ID            uint              `json:"id" gorm:"primarykey"`
+	StockLevelID  uint              `json:"stock_level_id" gorm:"not null;index"`
+	StockLevel    *StockLevel       `json:"stock_level,omitempty"`
+	Type          StockMovementType `json:"type" gorm:"not null"`
+	Quantity      int               `json:"quantity" gorm:"not null"`
+	OnHandAfter   int               `json:"on_hand_after"`
+	ReservationID *uint             `json:"reservation_id"`
+	Reference     string            `json:"reference"` // Order, delivery or count reference
+	Note          string            `json:"note"`
+	CreatedAt     time.Time         `json:"created_at"`
+}
+
+// SignedStockQuantity returns the signed on-hand change of a movement. Receipts, sales and
+// returns take a positive quantity; adjustments take the signed difference.
+func SignedStockQuantity(movementType StockMovementType, quantity int) (int, error) {
+	switch movementType {
+	case StockMovementReceipt, StockMovementReturn, StockMovementSale:
+		if quantity <= 0 {
+			return 0, fmt.Errorf("%s quantity must be positive", movementType)
+		}
+		if movementType == StockMovementSale {
+			return -quantity, nil
+		}
+		return quantity, nil
+	case StockMovementAdjustment:
+		if quantity == 0 {
+			return 0, fmt.Errorf("adjustment quantity cannot be zero")
+		}
+		return quantity, nil
+	default:
+		return 0, fmt.Errorf("invalid stock movement type: %s", movementType)
+	}
+}
+
+// ReservationStatus is the status of a stock reservation
+type ReservationStatus string
+
+const (
+	ReservationActive    ReservationStatus = "active"
+	ReservationFulfilled ReservationStatus = "fulfilled" // Turned into a sale
+	ReservationReleased  ReservationStatus = "released"  // Cancelled before expiry
+	ReservationExpired   ReservationStatus = "expired"
+)
+
+// ErrReservationNotActive is returned when a fulfilled, released or expired reservation is used
+var ErrReservationNotActive = errors.New("reservation is no longer active")
+
+// StockReservation holds units of a stock level for a pending order until it expires
+type StockReservation struct {
+	ID           uint              `json:"id" gorm:"primarykey"`
+	StockLevelID uint              `json:"stock_level_id" gorm:"not null;index"`
+	StockLevel   *StockLevel       `json:"stock_level,omitempty"`
+	Quantity     int               `json:"quantity" gorm:"not null"`
+	Status       ReservationStatus `json:"status" gorm:"default:'active'"`
+	Reference    string            `json:"reference"`
+	ExpiresAt    time.Time         `json:"expires_at" gorm:"not null"`
+	CreatedAt    time.Time         `json:"created_at"`
+	UpdatedAt    time.Time         `json:"updated_at"`
+}
+
+// IsActiveAt reports whether the reservation still holds its units at the given time
+func (r *StockReservation) IsActiveAt(at time.Time) bool {
+	return r.Status == ReservationActive && at.Before(r.ExpiresAt)
+}
+
+// CreateWarehouseRequest represents the request to create a warehouse
+type CreateWarehouseRequest struct {
+	Code     string `json:"code" binding:"required"`
+	Name     string `json:"name" binding:"required"`
+	Address  string `json:"address"`
+	IsActive *bool  `json:"is_active"`
+}
+
+// UpdateWarehouseRequest represents the request to update a warehouse
+type UpdateWarehouseRequest struct {
+	Name     string `json:"name"`
+	Address  string `json:"address"`
+	IsActive *bool  `json:"is_active"`
+}
+
+// StockItemRequest identifies the stock of a product or variant in a warehouse
+type StockItemRequest struct {
+	WarehouseID uint  `json:"warehouse_id" binding:"required"`
+	ProductID   uint  `json:"product_id" binding:"required"`
+	VariantID   *uint `json:"variant_id"`
+}
+
+// CreateStockMovementRequest represents the request to record a stock movement
+type CreateStockMovementRequest struct {
+	StockItemRequest
+	Type      StockMovementType `json:"type" binding:"required"`
+	Quantity  int               `json:"quantity" binding:"required"` // Positive, except for adjustments
+	Reference string            `json:"reference"`
+	Note      string            `json:"note"`
+}
+
+// CreateReservationRequest represents the request to reserve stock
+type CreateReservationRequest struct {
+	StockItemRequest
+	Quantity   int    `json:"quantity" binding:"required,min=1"`
+	Reference  string `json:"reference"`
+	TTLMinutes int    `json:"ttl_minutes" binding:"min=0"` // Defaults to the configured reservation TTL
+}
+
+// UpdateStockLevelRequest represents the request to update stock level settings
+type UpdateStockLevelRequest struct {
+	LowStockThreshold *int `json:"low_stock_threshold" binding:"required,min=0"`
+}
@@ -0,0 +1,69 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWarehouseValidate(t *testing.T) {
+	warehouse := Warehouse{Code: " paris-1 ", Name: "Paris"}
+	assert.NoError(t, warehouse.Validate())
+	assert.Equal(t, "PARIS-1", warehouse.Code)
+
+	warehouse.Code = "PARIS 1"
+	assert.Error(t, warehouse.Validate())
+
+	warehouse.Code = "PARIS-1"
+	warehouse.Name = " "
+	assert.Error(t, warehouse.Validate())
+}
+
+func TestSignedStockQuantity(t *testing.T) {
+	quantity, err := SignedStockQuantity(StockMovementReceipt, 10)
+	assert.NoError(t, err)
+	assert.Equal(t, 10, quantity)
+
+	quantity, err = SignedStockQuantity(StockMovementSale, 3)
+	assert.NoError(t, err)
+	assert.Equal(t, -3, quantity)
+
+	quantity, err = SignedStockQuantity(StockMovementAdjustment, -2)
+	assert.NoError(t, err)
+	assert.Equal(t, -2, quantity)
+
+	_, err = SignedStockQuantity(StockMovementSale, -3)
+	assert.Error(t, err, "sales take a positive quantity")
+
+	_, err = SignedStockQuantity(StockMovementAdjustment, 0)
+	assert.Error(t, err)
+
+	_, err = SignedStockQuantity("transfer", 1)
+	assert.Error(t, err)
+}
+
+func TestStockLevelAvailability(t *testing.T) {
+	level := StockLevel{OnHand: 10, Reserved: 4, LowStockThreshold: 5}
+	assert.Equal(t, 6, level.AvailableQuantity())
+	assert.False(t, level.LowStock())
+
+	level.Reserved = 5
+	level.WithAvailable()
+	assert.Equal(t, 5, level.Available)
+	assert.True(t, level.IsLowStock)
+
+	level.LowStockThreshold = 0
+	assert.False(t, level.LowStock(), "a zero threshold disables alerts")
+}
+
+func TestStockReservationIsActiveAt(t *testing.T) {
+	expiresAt := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	reservation := StockReservation{Status: ReservationActive, ExpiresAt: expiresAt}
+
+	assert.True(t, reservation.IsActiveAt(expiresAt.Add(-time.Minute)))
+	assert.False(t, reservation.IsActiveAt(expiresAt))
+
+	reservation.Status = ReservationReleased
+	assert.False(t, reservation.IsActiveAt(expiresAt.Add(-time.Minute)))
+}
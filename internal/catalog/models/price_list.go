@@ -0,0 +1,245 @@
+package models
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// PriceList is a set of prices overriding product prices (wholesale, retail,
+// promotions...) when a price is resolved with its code. Prices are in the list currency.
+// When several lists apply, the one with the highest priority wins.
+type PriceList struct {
+	ID          uint            `json:"id" gorm:"primarykey"`
+	Code        string          `json:"code" gorm:"uniqueIndex;not null"` // Referenced by clients, e.g. "WHOLESALE"
+	Name        string          `json:"name" gorm:"not null"`
+	Description string          `json:"description"`
+	Currency    string          `json:"currency" gorm:"default:'USD'"`
+	Priority    int             `json:"priority" gorm:"default:0"`
+	ValidFrom   *time.Time      `json:"valid_from"`
+	ValidTo     *time.Time      `json:"valid_to"`                  // Exclusive
+	IsActive    bool            `json:"is_active" gorm:"not null"` // Always set on insert
+	Items       []PriceListItem `json:"items,omitempty" gorm:"foreignKey:PriceListID"`
+	CreatedAt   time.Time       `json:"created_at"`
+	UpdatedAt   time.Time       `json:"updated_at"`
+	DeletedAt   gorm.DeletedAt  `json:"deleted_at,omitempty" gorm:"index"`
+}
+
+// PriceListItem is the price of a product, or of one of its variants, in a price list
+// from a minimum quantity (quantity tiers)
+type PriceListItem struct {
+	ID          uint      `json:"id" gorm:"primarykey"`
+	PriceListID uint      `json:"price_list_id" gorm:"not null;index"`
+	ProductID   uint      `json:"product_id" gorm:"not null;index"`
+	VariantID   *uint     `json:"variant_id"` // nil prices the product and all its variants
+	MinQuantity int       `json:"min_quantity" gorm:"default:1"`
+	Price       float64   `json:"price" gorm:"not null"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+var priceListCodePattern = regexp.MustCompile(`^[A-Z0-9_-]+$`)
+
+// Validate validates price list business rules
+func (p *PriceList) Validate() error {
+	p.Code = strings.ToUpper(strings.TrimSpace(p.Code))
+	if p.Code == "" {
+		return fmt.Errorf("price list code is required")
+	}
+
+	if len(p.Code) > 50 || !priceListCodePattern.MatchString(p.Code) {
+		return fmt.Errorf("price list code must be at most 50 letters, digits, '-' or '_'")
+	}
+
+	if strings.TrimSpace(p.Name) == "" {
+		return fmt.Errorf("price list name is required")
+	}
+
+	if len(p.Name) > 255 {
+		return fmt.Errorf("price list name cannot exceed 255 characters")
+	}
+
+	if len(p.Currency) != 3 {
+		return fmt.Errorf("currency must be a 3-letter code")
+	}
+
+	if p.ValidFrom != nil && p.ValidTo != nil && !p.ValidFrom.Before(*p.ValidTo) {
+		return fmt.Errorf("valid_from must be before valid_to")
+	}
+
+	return nil
+}
+
+// IsValidAt reports whether the price list applies at the given time
+func (p *PriceList) IsValidAt(at time.Time) bool {
+	if !p.IsActive {
+		return false
+	}
+	if p.ValidFrom != nil && at.Before(*p.ValidFrom) {
+		return false
+	}
+	if p.ValidTo != nil && !at.Before(*p.ValidTo) {
+		return false
+	}
+	return true
+}
+
+// Validate validates price list item business rules
+func (i *PriceListItem) Validate() error {
+	if i.MinQuantity < 1 {
+		return fmt.Errorf("min_quantity must be at least 1")
+	}
+
+	if i.Price < 0 {
+		return fmt.Errorf("price cannot be negative")
+	}
+
+	return nil
+}
+
+// PriceResolution is the effective price of a product for a quantity
+type PriceResolution struct {
+	ProductID     uint    `json:"product_id"`
+	VariantID     *uint   `json:"variant_id,omitempty"`
+	Quantity      int     `json:"quantity"`
+	UnitPrice     float64 `json:"unit_price"`
+	Total         float64 `json:"total"`
+	Currency      string  `json:"currency"`
+	Source        string  `json:"source"` // "price_list" or "base" (product price)
+	PriceListID   *uint   `json:"price_list_id,omitempty"`
+	PriceListCode string  `json:"price_list_code,omitempty"`
+	MinQuantity   int     `json:"min_quantity,omitempty"` // Quantity tier applied
+}
+
+// Price resolution sources
+const (
+	PriceSourceBase      = "base"
+	PriceSourcePriceList = "price_list"
+)
+
+// ResolvePrice returns the effective price of a product, or one of its variants, for a
+// quantity at a given time. Among the lists valid at that time, the highest priority list
+// with a price for the product wins. Within a list, a variant price beats a product price
+// and the highest quantity tier not above the quantity applies. A product price from a
+// list still gets the variant price adjustment, so it only applies to an adjusted variant
+// when the list is in the product currency; foreign-currency lists need variant prices.
+// Without any list price, the product (or variant) price applies.
+func ResolvePrice(product Product, variant *ProductVariant, quantity int, lists []PriceList, at time.Time) PriceResolution {
+	resolution := PriceResolution{
+		ProductID: product.ID,
+		Quantity:  quantity,
+		UnitPrice: product.Price,
+		Currency:  product.Currency,
+		Source:    PriceSourceBase,
+	}
+	adjustment := 0.0
+	if variant != nil {
+		resolution.VariantID = &variant.ID
+		adjustment = variant.PriceAdjustment
+		resolution.UnitPrice = variant.CalculateEffectivePrice(product.Price)
+	}
+
+	candidates := make([]PriceList, 0, len(lists))
+	for _, list := range lists {
+		if list.IsValidAt(at) {
+			candidates = append(candidates, list)
+		}
+	}
+	sort.SliceStable(candidates, func(a, b int) bool {
+		return candidates[a].Priority > candidates[b].Priority
+	})
+
+	for _, list := range candidates {
+		productItems := adjustment == 0 || list.Currency == product.Currency
+		item := bestPriceListItem(list.Items, product.ID, variant, quantity, productItems)
+		if item == nil {
+			continue
+		}
+
+		resolution.UnitPrice = item.Price
+		if item.VariantID == nil {
+			resolution.UnitPrice = roundPrice(item.Price + adjustment)
+		}
+		resolution.Currency = list.Currency
+		resolution.Source = PriceSourcePriceList
+		resolution.PriceListID = &list.ID
+		resolution.PriceListCode = list.Code
+		resolution.MinQuantity = item.MinQuantity
+		break
+	}
+
+	resolution.Total = roundPrice(resolution.UnitPrice * float64(quantity))
+	return resolution
+}
+
+// bestPriceListItem returns the item of a list pricing the product for the quantity:
+// variant items first, then the highest tier not above the quantity. Items without a
+// variant are skipped unless productItems is set.
+func bestPriceListItem(items []PriceListItem, productID uint, variant *ProductVariant, quantity int, productItems bool) *PriceListItem {
+	var best *PriceListItem
+	for i := range items {
+		item := &items[i]
+		if item.ProductID != productID || item.MinQuantity > quantity {
+			continue
+		}
+		if item.VariantID != nil && (variant == nil || *item.VariantID != variant.ID) {
+			continue
+		}
+		if item.VariantID == nil && !productItems {
+			continue
+		}
+
+		switch {
+		case best == nil:
+			best = item
+		case (item.VariantID != nil) != (best.VariantID != nil):
+			if item.VariantID != nil {
+				best = item
+			}
+		case item.MinQuantity > best.MinQuantity:
+			best = item
+		}
+	}
+	return best
+}
+
+// roundPrice rounds a price to cents
+func roundPrice(price float64) float64 {
+	return math.Round(price*100) / 100
+}
+
+// CreatePriceListRequest represents the request to create a price list
+type CreatePriceListRequest struct {
+	Code        string     `json:"code" binding:"required"`
+	Name        string     `json:"name" binding:"required"`
+	Description string     `json:"description"`
+	Currency    string     `json:"currency"`
+	Priority    int        `json:"priority"`
+	ValidFrom   *time.Time `json:"valid_from"`
+	ValidTo     *time.Time `json:"valid_to"`
+	IsActive    *bool      `json:"is_active"`
+}
+
+// UpdatePriceListRequest represents the request to update a price list
+type UpdatePriceListRequest struct {
+	Name        string     `json:"name"`
+	Description string     `json:"description"`
+	Currency    string     `json:"currency"`
+	Priority    *int       `json:"priority"`
+	ValidFrom   *time.Time `json:"valid_from"`
+	ValidTo     *time.Time `json:"valid_to"`
+	IsActive    *bool      `json:"is_active"`
+}
+
+// PriceListItemRequest represents the request to set a price in a price list
+type PriceListItemRequest struct {
+	ProductID   uint     `json:"product_id" binding:"required"`
+	VariantID   *uint    `json:"variant_id"`
+	MinQuantity int      `json:"min_quantity"` // Defaults to 1
+	Price       *float64 `json:"price" binding:"required,min=0"`
+}
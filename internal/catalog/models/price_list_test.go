@@ -0,0 +1,83 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPriceListValidate(t *testing.T) {
+	list := PriceList{Code: " wholesale ", Name: "Wholesale", Currency: "EUR"}
+	require.NoError(t, list.Validate())
+	assert.Equal(t, "WHOLESALE", list.Code)
+
+	list.Code = "WHOLE SALE"
+	assert.Error(t, list.Validate())
+
+	from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	list = PriceList{Code: "PROMO", Name: "Promo", Currency: "EUR", ValidFrom: &from, ValidTo: &from}
+	assert.Error(t, list.Validate(), "empty validity window")
+}
+
+func TestPriceListIsValidAt(t *testing.T) {
+	from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 1, 0)
+	list := PriceList{IsActive: true, ValidFrom: &from, ValidTo: &to}
+
+	assert.True(t, list.IsValidAt(from))
+	assert.False(t, list.IsValidAt(from.Add(-time.Second)))
+	assert.False(t, list.IsValidAt(to), "valid_to is exclusive")
+
+	list.IsActive = false
+	assert.False(t, list.IsValidAt(from))
+}
+
+func TestResolvePrice(t *testing.T) {
+	now := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	expired := now.Add(-time.Hour)
+	product := Product{ID: 1, Price: 100, Currency: "USD"}
+	variant := ProductVariant{ID: 7, ProductID: 1, PriceAdjustment: 5}
+	variantID := variant.ID
+
+	wholesale := PriceList{ID: 1, Code: "WHOLESALE", Currency: "EUR", Priority: 1, IsActive: true, Items: []PriceListItem{
+		{ProductID: 1, MinQuantity: 1, Price: 80},
+		{ProductID: 1, MinQuantity: 10, Price: 70},
+		{ProductID: 1, VariantID: &variantID, MinQuantity: 1, Price: 90},
+	}}
+	promo := PriceList{ID: 2, Code: "PROMO", Currency: "EUR", Priority: 5, IsActive: true, ValidTo: &expired, Items: []PriceListItem{
+		{ProductID: 1, MinQuantity: 1, Price: 10},
+	}}
+
+	base := ResolvePrice(product, nil, 2, nil, now)
+	assert.Equal(t, PriceSourceBase, base.Source)
+	assert.Equal(t, 100.0, base.UnitPrice)
+	assert.Equal(t, 200.0, base.Total)
+	assert.Equal(t, "USD", base.Currency)
+
+	resolved := ResolvePrice(product, nil, 12, []PriceList{promo, wholesale}, now)
+	assert.Equal(t, PriceSourcePriceList, resolved.Source)
+	assert.Equal(t, "WHOLESALE", resolved.PriceListCode, "expired promotion is ignored")
+	assert.Equal(t, 70.0, resolved.UnitPrice, "highest reached quantity tier")
+	assert.Equal(t, 10, resolved.MinQuantity)
+	assert.Equal(t, "EUR", resolved.Currency)
+
+	resolved = ResolvePrice(product, &variant, 12, []PriceList{wholesale}, now)
+	assert.Equal(t, 90.0, resolved.UnitPrice, "variant price beats product price")
+
+	other := ProductVariant{ID: 8, ProductID: 1, PriceAdjustment: 5}
+	resolved = ResolvePrice(product, &other, 1, []PriceList{wholesale}, now)
+	assert.Equal(t, PriceSourceBase, resolved.Source, "adjusted variants need variant prices in foreign-currency lists")
+	assert.Equal(t, 105.0, resolved.UnitPrice)
+	assert.Equal(t, "USD", resolved.Currency)
+
+	domestic := wholesale
+	domestic.Currency = "USD"
+	resolved = ResolvePrice(product, &other, 1, []PriceList{domestic}, now)
+	assert.Equal(t, 85.0, resolved.UnitPrice, "product list price plus variant adjustment")
+
+	promo.ValidTo = nil
+	resolved = ResolvePrice(product, nil, 1, []PriceList{wholesale, promo}, now)
+	assert.Equal(t, "PROMO", resolved.PriceListCode, "highest priority wins")
+}
@@ -1,6 +1,7 @@
 package models
 
 import (
+	"errors"
 	"fmt"
 	"strings"
 	"time"
@@ -10,29 +11,68 @@ import (
 
 // Product represents a product in the catalog domain
 type Product struct {
-	ID          uint           `json:"id" gorm:"primarykey"`
-	SKU         string         `json:"sku" gorm:"uniqueIndex;not null"`
-	Name        string         `json:"name" gorm:"not null"`
-	Description string         `json:"description"`
-	Price       float64        `json:"price" gorm:"not null"`
-	Currency    string         `json:"currency" gorm:"default:'USD'"`
-	CategoryID  *uint          `json:"category_id"`
-	Category    *Category      `json:"category,omitempty"`
-	IsActive    bool           `json:"is_active" gorm:"default:true"`
-	CreatedAt   time.Time      `json:"created_at"`
-	UpdatedAt   time.Time      `json:"updated_at"`
-	DeletedAt   gorm.DeletedAt `json:"deleted_at,omitempty" gorm:"index"`
-}
-
-// ProductStatus represents the status of a product
+	ID            uint              `json:"id" gorm:"primarykey"`
+	SKU           string            `json:"sku" gorm:"uniqueIndex;not null"`
+	GTIN          string            `json:"gtin,omitempty"` // EAN/UPC barcode
+	Name          string            `json:"name" gorm:"not null"`
+	Description   string            `json:"description"`
+	Price         float64           `json:"price" gorm:"not null"`
+	Currency      string            `json:"currency" gorm:"default:'USD'"`
+	CategoryID    *uint             `json:"category_id" gorm:"-"` // Primary category, filled from Categories
+	Categories    []ProductCategory `json:"categories,omitempty" gorm:"foreignKey:ProductID"`
+	BrandID       *uint             `json:"brand_id"`
+	Brand         *Brand            `json:"brand,omitempty"`
+	Variants      []ProductVariant  `json:"variants,omitempty" gorm:"foreignKey:ProductID"`
+	Status        ProductStatus     `json:"status" gorm:"default:'active'"`
+	Attributes    VariantAttributes `json:"attributes" gorm:"type:jsonb"`                   // Checked against the primary category schema
+	SearchRank    float64           `json:"search_rank,omitempty" gorm:"->;-:migration"`    // Only filled by full-text searches
+	SearchSnippet string            `json:"search_snippet,omitempty" gorm:"->;-:migration"` // HTML-escaped description excerpt with <mark> highlights
+	CreatedAt     time.Time         `json:"created_at"`
+	UpdatedAt     time.Time         `json:"updated_at"`
+	DeletedAt     gorm.DeletedAt    `json:"deleted_at,omitempty" gorm:"index"`
+}
+
+// AfterFind fills CategoryID with the primary category when Categories are loaded
+func (p *Product) AfterFind(tx *gorm.DB) error {
+	p.CategoryID = nil
+	if primary := p.PrimaryCategory(); primary != nil {
+		categoryID := primary.CategoryID
+		p.CategoryID = &categoryID
+	}
+	return nil
+}
+
+// PrimaryCategory returns the primary category link of the product, or nil when
+// Categories are not loaded or empty
+func (p *Product) PrimaryCategory() *ProductCategory {
+	for i := range p.Categories {
+		if p.Categories[i].IsPrimary {
+			return &p.Categories[i]
+		}
+	}
+	return nil
+}
+
+// ProductStatus represents the status of a product.
+// Products are created active unless created as draft (not visible, not sellable) or
+// inactive, then go through draft → active → inactive/discontinued; discontinued is final.
 type ProductStatus string
 
 const (
+	ProductStatusDraft        ProductStatus = "draft"
 	ProductStatusActive       ProductStatus = "active"
 	ProductStatusInactive     ProductStatus = "inactive"
 	ProductStatusDiscontinued ProductStatus = "discontinued"
 )
 
+// productStatusTransitions lists the statuses each status can move to
+var productStatusTransitions = map[ProductStatus][]ProductStatus{
+	ProductStatusDraft:        {ProductStatusActive},
+	ProductStatusActive:       {ProductStatusInactive, ProductStatusDiscontinued},
+	ProductStatusInactive:     {ProductStatusActive, ProductStatusDiscontinued},
+	ProductStatusDiscontinued: {},
+}
+
 // Validate validates product business rules
 func (p *Product) Validate() error {
 	if strings.TrimSpace(p.Name) == "" {
@@ -64,19 +104,75 @@ func (p *Product) Validate() error {
 		return fmt.Errorf("currency must be a 3-letter code")
 	}
 
+	if p.Status != "" && !IsValidProductStatus(p.Status) {
+		return fmt.Errorf("invalid product status: %s", p.Status)
+	}
+
+	if p.GTIN != "" {
+		if err := ValidateGTIN(p.GTIN); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
 // IsValidStatus checks if the product status is valid
 func IsValidProductStatus(status ProductStatus) bool {
 	switch status {
-	case ProductStatusActive, ProductStatusInactive, ProductStatusDiscontinued:
+	case ProductStatusDraft, ProductStatusActive, ProductStatusInactive, ProductStatusDiscontinued:
 		return true
 	default:
 		return false
 	}
 }
 
+// IsValidInitialProductStatus checks if a new product can be created with the status
+func IsValidInitialProductStatus(status ProductStatus) bool {
+	return status == ProductStatusDraft || status == ProductStatusActive || status == ProductStatusInactive
+}
+
+// CanTransitionTo checks if the product can move from its current status to next.
+// Keeping the current status is always allowed.
+func (p *Product) CanTransitionTo(next ProductStatus) bool {
+	if next == p.Status {
+		return true
+	}
+	for _, allowed := range productStatusTransitions[p.Status] {
+		if allowed == next {
+			return true
+		}
+	}
+	return false
+}
+
+// TransitionTo moves the product to the next status if the lifecycle allows it
+func (p *Product) TransitionTo(next ProductStatus) error {
+	if !IsValidProductStatus(next) {
+		return fmt.Errorf("invalid product status: %s", next)
+	}
+	if !p.CanTransitionTo(next) {
+		return fmt.Errorf("product status cannot change from %s to %s", p.Status, next)
+	}
+	p.Status = next
+	return nil
+}
+
+// AllowedTransitions returns the statuses the product can move to
+func (p *Product) AllowedTransitions() []ProductStatus {
+	return append([]ProductStatus{}, productStatusTransitions[p.Status]...)
+}
+
+// ErrProductNotSellable is returned when a sale, reservation or new offer targets a
+// product that is not active
+var ErrProductNotSellable = errors.New("product is not available for sale")
+
+// CanBeSold reports whether the product can be ordered, reserved or sold. Only active
+// products are sellable; discontinued products are kept for existing orders only.
+func (p *Product) CanBeSold() bool {
+	return p.Status == ProductStatusActive
+}
+
 // FormatPrice formats the price with currency
 func (p *Product) FormatPrice() string {
 	if p.Currency == "" {
@@ -87,23 +183,47 @@ func (p *Product) FormatPrice() string {
 
 // CreateProductRequest represents the request to create a new product
 type CreateProductRequest struct {
-	SKU         string  `json:"sku" binding:"required"`
-	Name        string  `json:"name" binding:"required"`
-	Description string  `json:"description"`
-	Price       float64 `json:"price" binding:"required,min=0"`
-	Currency    string  `json:"currency"`
-	CategoryID  *uint   `json:"category_id"`
-	IsActive    *bool   `json:"is_active"`
+	SKU         string            `json:"sku"` // Generated from the configured SKU pattern when empty
+	GTIN        string            `json:"gtin"`
+	Name        string            `json:"name" binding:"required"`
+	Description string            `json:"description"`
+	Price       float64           `json:"price" binding:"required,min=0"`
+	Currency    string            `json:"currency"`
+	CategoryID  *uint             `json:"category_id"`  // Primary category
+	CategoryIDs []uint            `json:"category_ids"` // Additional categories
+	BrandID     *uint             `json:"brand_id"`
+	Status      ProductStatus     `json:"status"` // draft, active (default) or inactive
+	Attributes  VariantAttributes `json:"attributes"`
+	IsActive    *bool             `json:"is_active"` // Deprecated: status active (true) or inactive (false)
+}
+
+// statusFromIsActive maps the deprecated is_active field of product requests onto the
+// active or inactive status. Sending both is accepted only when they agree.
+func statusFromIsActive(status ProductStatus, isActive *bool) (ProductStatus, error) {
+	if isActive == nil {
+		return status, nil
+	}
+
+	mapped := ProductStatusInactive
+	if *isActive {
+		mapped = ProductStatusActive
+	}
+	if status != "" && status != mapped {
+		return "", fmt.Errorf("is_active %t conflicts with status %s", *isActive, status)
+	}
+	return mapped, nil
 }
 
 // Validate validates the create product request
 func (r *CreateProductRequest) Validate() error {
-	if strings.TrimSpace(r.Name) == "" {
-		return fmt.Errorf("product name is required")
+	status, err := statusFromIsActive(r.Status, r.IsActive)
+	if err != nil {
+		return err
 	}
+	r.Status = status
 
-	if strings.TrimSpace(r.SKU) == "" {
-		return fmt.Errorf("product SKU is required")
+	if strings.TrimSpace(r.Name) == "" {
+		return fmt.Errorf("product name is required")
 	}
 
 	if r.Price < 0 {
@@ -114,29 +234,59 @@ func (r *CreateProductRequest) Validate() error {
 		return fmt.Errorf("product name cannot exceed 200 characters")
 	}
 
-	if len(r.SKU) > 50 {
-		return fmt.Errorf("product SKU cannot exceed 50 characters")
+	if len(r.SKU) > MaxSKULength {
+		return fmt.Errorf("product SKU cannot exceed %d characters", MaxSKULength)
+	}
+
+	r.SKU = strings.TrimSpace(r.SKU)
+	r.GTIN = strings.TrimSpace(r.GTIN)
+	if r.GTIN != "" {
+		if err := ValidateGTIN(r.GTIN); err != nil {
+			return err
+		}
 	}
 
 	if len(r.Description) > 1000 {
 		return fmt.Errorf("product description cannot exceed 1000 characters")
 	}
 
+	if r.Status != "" && !IsValidInitialProductStatus(r.Status) {
+		return fmt.Errorf("new products must be draft, active or inactive")
+	}
+
+	attributes, err := normalizeVariantAttributes(r.Attributes)
+	if err != nil {
+		return err
+	}
+	r.Attributes = attributes
+
 	return nil
 }
 
 // UpdateProductRequest represents the request to update a product
 type UpdateProductRequest struct {
-	Name        string   `json:"name"`
-	Description string   `json:"description"`
-	Price       *float64 `json:"price"`
-	Currency    string   `json:"currency"`
-	CategoryID  *uint    `json:"category_id"`
-	IsActive    *bool    `json:"is_active"`
+	Name              string            `json:"name"`
+	GTIN              *string           `json:"gtin"` // Empty removes the barcode
+	Description       string            `json:"description"`
+	Price             *float64          `json:"price"`
+	Currency          string            `json:"currency"`
+	CategoryID        *uint             `json:"category_id"`         // Makes this category primary, adding it if needed
+	CategoryIDs       []uint            `json:"category_ids"`        // Replaces all categories when provided
+	BrandID           *uint             `json:"brand_id"`            // 0 removes the brand
+	Status            ProductStatus     `json:"status"`              // Must be an allowed transition
+	PriceChangeReason string            `json:"price_change_reason"` // Recorded in the price history
+	Attributes        VariantAttributes `json:"attributes"`          // Replaces all attributes when provided
+	IsActive          *bool             `json:"is_active"`           // Deprecated: status active (true) or inactive (false)
 }
 
 // Validate validates the update product request
 func (r *UpdateProductRequest) Validate() error {
+	status, err := statusFromIsActive(r.Status, r.IsActive)
+	if err != nil {
+		return err
+	}
+	r.Status = status
+
 	if r.Name != "" && len(r.Name) > 200 {
 		return fmt.Errorf("product name cannot exceed 200 characters")
 	}
@@ -153,5 +303,22 @@ func (r *UpdateProductRequest) Validate() error {
 		return fmt.Errorf("currency must be a 3-letter code")
 	}
 
+	if r.GTIN != nil {
+		*r.GTIN = strings.TrimSpace(*r.GTIN)
+		if *r.GTIN != "" {
+			if err := ValidateGTIN(*r.GTIN); err != nil {
+				return err
+			}
+		}
+	}
+
+	if r.Attributes != nil {
+		attributes, err := normalizeVariantAttributes(r.Attributes)
+		if err != nil {
+			return err
+		}
+		r.Attributes = attributes
+	}
+
 	return nil
 }
\ No newline at end of file
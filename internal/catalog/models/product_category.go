@@ -0,0 +1,52 @@
+package models
+
+import (
+	"fmt"
+	"time"
+)
+
+// ProductCategory links a product to one of its categories. A product can be listed in
+// several categories; exactly one of them is its primary category (breadcrumbs,
+// canonical URL, reporting).
+type ProductCategory struct {
+	ProductID  uint      `json:"-" gorm:"primaryKey"`
+	CategoryID uint      `json:"category_id" gorm:"primaryKey"`
+	IsPrimary  bool      `json:"is_primary" gorm:"default:false"`
+	CreatedAt  time.Time `json:"created_at"`
+	Category   *Category `json:"category,omitempty" gorm:"foreignKey:CategoryID"`
+}
+
+// BuildProductCategories returns the links of a product to the given categories. The
+// primary category is included when missing; without one, the first category is primary.
+func BuildProductCategories(productID uint, categoryIDs []uint, primaryID *uint) ([]ProductCategory, error) {
+	ids := make([]uint, 0, len(categoryIDs)+1)
+	seen := map[uint]bool{}
+	if primaryID != nil {
+		if *primaryID == 0 {
+			return nil, fmt.Errorf("primary category ID is invalid")
+		}
+		ids = append(ids, *primaryID)
+		seen[*primaryID] = true
+	}
+	for _, id := range categoryIDs {
+		if id == 0 {
+			return nil, fmt.Errorf("category ID is invalid")
+		}
+		if !seen[id] {
+			ids = append(ids, id)
+			seen[id] = true
+		}
+	}
+
+	links := make([]ProductCategory, len(ids))
+	for i, id := range ids {
+		links[i] = ProductCategory{ProductID: productID, CategoryID: id, IsPrimary: i == 0}
+	}
+	return links, nil
+}
+
+// SetProductCategoriesRequest replaces the categories of a product
+type SetProductCategoriesRequest struct {
+	CategoryIDs       []uint `json:"category_ids" binding:"required"`
+	PrimaryCategoryID *uint  `json:"primary_category_id"` // Defaults to the first category
+}
@@ -0,0 +1,43 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildProductCategories(t *testing.T) {
+	primary := uint(7)
+
+	links, err := BuildProductCategories(1, []uint{3, 7, 3, 5}, &primary)
+	require.NoError(t, err)
+	require.Len(t, links, 3)
+	assert.Equal(t, ProductCategory{ProductID: 1, CategoryID: 7, IsPrimary: true}, links[0])
+	assert.Equal(t, uint(3), links[1].CategoryID)
+	assert.False(t, links[1].IsPrimary)
+	assert.Equal(t, uint(5), links[2].CategoryID)
+
+	links, err = BuildProductCategories(1, []uint{4, 2}, nil)
+	require.NoError(t, err)
+	assert.True(t, links[0].IsPrimary, "first category is primary by default")
+	assert.Equal(t, uint(4), links[0].CategoryID)
+
+	links, err = BuildProductCategories(1, nil, nil)
+	require.NoError(t, err)
+	assert.Empty(t, links)
+
+	_, err = BuildProductCategories(1, []uint{0}, nil)
+	assert.Error(t, err)
+}
+
+func TestProduct_PrimaryCategory(t *testing.T) {
+	product := Product{Categories: []ProductCategory{{CategoryID: 2}, {CategoryID: 9, IsPrimary: true}}}
+	require.NoError(t, product.AfterFind(nil))
+	require.NotNil(t, product.CategoryID)
+	assert.Equal(t, uint(9), *product.CategoryID)
+
+	product = Product{}
+	require.NoError(t, product.AfterFind(nil))
+	assert.Nil(t, product.CategoryID)
+}
@@ -0,0 +1,186 @@
+package models
+
+import (
+	"fmt"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ProductSearchFilters are the storefront search filters. Values selected within one
+// facet are alternatives (brand 1 OR brand 2); different facets must all match.
+type ProductSearchFilters struct {
+	Query              string              `json:"q,omitempty"`
+	BrandIDs           []uint              `json:"brand_ids,omitempty"`
+	CategoryIDs        []uint              `json:"category_ids,omitempty"`
+	IncludeDescendants bool                `json:"include_descendants"`
+	MinPrice           *float64            `json:"min_price,omitempty"`  // Inclusive
+	MaxPrice           *float64            `json:"max_price,omitempty"`  // Exclusive, like the price range buckets
+	Attributes         map[string][]string `json:"attributes,omitempty"` // Product or variant attributes, e.g. color: [red, blue]
+}
+
+// Facet names, used to leave a facet's own filter out when counting its buckets
+const (
+	FacetBrand     = "brand"
+	FacetCategory  = "category"
+	FacetPrice     = "price"
+	FacetAttribute = "attribute"
+)
+
+// ParseProductSearchFilters reads the filters from query parameters: q, brand_id,
+// category_id, include_descendants, min_price, max_price and attr[<name>]. Multiple
+// values are given comma-separated or by repeating the parameter.
+func ParseProductSearchFilters(values url.Values) (ProductSearchFilters, error) {
+	filters := ProductSearchFilters{
+		Query:              strings.TrimSpace(values.Get("q")),
+		IncludeDescendants: values.Get("include_descendants") == "true",
+	}
+
+	var err error
+	if filters.BrandIDs, err = parseFacetIDs(values["brand_id"], "brand_id"); err != nil {
+		return filters, err
+	}
+	if filters.CategoryIDs, err = parseFacetIDs(values["category_id"], "category_id"); err != nil {
+		return filters, err
+	}
+	if filters.MinPrice, err = parseFacetPrice(values.Get("min_price"), "min_price"); err != nil {
+		return filters, err
+	}
+	if filters.MaxPrice, err = parseFacetPrice(values.Get("max_price"), "max_price"); err != nil {
+		return filters, err
+	}
+	if filters.MinPrice != nil && filters.MaxPrice != nil && *filters.MinPrice >= *filters.MaxPrice {
+		return filters, fmt.Errorf("min_price must be lower than max_price")
+	}
+
+	if filters.Attributes, err = ParseAttributeFilters(values); err != nil {
+		return filters, err
+	}
+
+	return filters, nil
+}
+
+// ParseAttributeFilters reads the attr[<name>] query parameters into the accepted values
+// of each attribute. Names are lowercased.
+func ParseAttributeFilters(values url.Values) (map[string][]string, error) {
+	attributes := map[string][]string{}
+	for param, vals := range values {
+		if !strings.HasPrefix(param, "attr[") || !strings.HasSuffix(param, "]") {
+			continue
+		}
+		name := strings.ToLower(strings.TrimSpace(param[len("attr[") : len(param)-1]))
+		if name == "" {
+			return nil, fmt.Errorf("attribute filter name cannot be empty")
+		}
+		for _, value := range splitFacetValues(vals) {
+			attributes[name] = append(attributes[name], value)
+		}
+	}
+	return attributes, nil
+}
+
+// AttributeNames returns the names of the filtered attributes in a stable order
+func (f ProductSearchFilters) AttributeNames() []string {
+	names := make([]string, 0, len(f.Attributes))
+	for name, values := range f.Attributes {
+		if len(values) > 0 {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// FacetBucket is one value of a facet with the number of matching products
+type FacetBucket struct {
+	ID       uint     `json:"id,omitempty"`
+	Name     string   `json:"name,omitempty"`
+	Value    string   `json:"value,omitempty"`
+	Min      *float64 `json:"min,omitempty"`
+	Max      *float64 `json:"max,omitempty"` // Exclusive; nil for the open-ended bucket
+	Count    int64    `json:"count"`
+	Selected bool     `json:"selected"`
+}
+
+// ProductFacets are the facet buckets of a product search. The buckets of a facet are
+// counted with every filter except the facet's own, so other values stay selectable.
+type ProductFacets struct {
+	Brands      []FacetBucket            `json:"brands"`
+	Categories  []FacetBucket            `json:"categories"`
+	PriceRanges []FacetBucket            `json:"price_ranges"`
+	Attributes  map[string][]FacetBucket `json:"attributes"`
+}
+
+// priceRangeBounds are the boundaries of the price range buckets: [0, 25), [25, 50), ...
+// and a last open-ended bucket
+var priceRangeBounds = []float64{0, 25, 50, 100, 200, 500}
+
+// PriceRangeBuckets returns empty price range buckets, marking the one matching the
+// price filters as selected. Each bucket gets its own copy of the bounds.
+func PriceRangeBuckets(filters ProductSearchFilters) []FacetBucket {
+	buckets := make([]FacetBucket, len(priceRangeBounds))
+	for i := range priceRangeBounds {
+		lower := priceRangeBounds[i]
+		bucket := FacetBucket{Min: &lower}
+		if i+1 < len(priceRangeBounds) {
+			upper := priceRangeBounds[i+1]
+			bucket.Max = &upper
+		}
+		bucket.Selected = filters.MinPrice != nil && *filters.MinPrice == *bucket.Min &&
+			((filters.MaxPrice == nil && bucket.Max == nil) ||
+				(filters.MaxPrice != nil && bucket.Max != nil && *filters.MaxPrice == *bucket.Max))
+		buckets[i] = bucket
+	}
+	return buckets
+}
+
+// ContainsID reports whether id is one of ids
+func ContainsID(ids []uint, id uint) bool {
+	for _, candidate := range ids {
+		if candidate == id {
+			return true
+		}
+	}
+	return false
+}
+
+// parseFacetIDs parses comma-separated or repeated IDs
+func parseFacetIDs(values []string, param string) ([]uint, error) {
+	var ids []uint
+	for _, value := range splitFacetValues(values) {
+		id, err := strconv.ParseUint(value, 10, 32)
+		if err != nil || id == 0 {
+			return nil, fmt.Errorf("invalid %s: %s", param, value)
+		}
+		if !ContainsID(ids, uint(id)) {
+			ids = append(ids, uint(id))
+		}
+	}
+	return ids, nil
+}
+
+// parseFacetPrice parses an optional non-negative price
+func parseFacetPrice(value, param string) (*float64, error) {
+	if value == "" {
+		return nil, nil
+	}
+	price, err := strconv.ParseFloat(value, 64)
+	if err != nil || price < 0 {
+		return nil, fmt.Errorf("invalid %s: %s", param, value)
+	}
+	return &price, nil
+}
+
+// splitFacetValues splits comma-separated values and drops empty ones
+func splitFacetValues(values []string) []string {
+	var result []string
+	for _, value := range values {
+		for _, part := range strings.Split(value, ",") {
+			if part = strings.TrimSpace(part); part != "" {
+				result = append(result, part)
+			}
+		}
+	}
+	return result
+}
@@ -0,0 +1,60 @@
+package models
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseProductSearchFilters(t *testing.T) {
+	values, err := url.ParseQuery("q=running+shoes&brand_id=1,2&brand_id=2&category_id=7&min_price=25&max_price=50&attr[Color]=red,blue&attr[size]=M&include_descendants=true")
+	require.NoError(t, err)
+
+	filters, err := ParseProductSearchFilters(values)
+	require.NoError(t, err)
+	assert.Equal(t, "running shoes", filters.Query)
+	assert.Equal(t, []uint{1, 2}, filters.BrandIDs)
+	assert.Equal(t, []uint{7}, filters.CategoryIDs)
+	assert.True(t, filters.IncludeDescendants)
+	assert.Equal(t, 25.0, *filters.MinPrice)
+	assert.Equal(t, 50.0, *filters.MaxPrice)
+	assert.Equal(t, []string{"red", "blue"}, filters.Attributes["color"])
+	assert.Equal(t, []string{"color", "size"}, filters.AttributeNames())
+}
+
+func TestParseProductSearchFilters_Invalid(t *testing.T) {
+	for _, query := range []string{
+		"brand_id=abc",
+		"category_id=0",
+		"min_price=-1",
+		"min_price=50&max_price=25",
+		"attr[]=red",
+	} {
+		values, err := url.ParseQuery(query)
+		require.NoError(t, err)
+		_, err = ParseProductSearchFilters(values)
+		assert.Error(t, err, query)
+	}
+}
+
+func TestPriceRangeBuckets(t *testing.T) {
+	min, max := 25.0, 50.0
+	buckets := PriceRangeBuckets(ProductSearchFilters{MinPrice: &min, MaxPrice: &max})
+
+	require.Len(t, buckets, len(priceRangeBounds))
+	assert.Equal(t, 0.0, *buckets[0].Min)
+	assert.Equal(t, 25.0, *buckets[0].Max)
+	assert.True(t, buckets[1].Selected)
+	assert.False(t, buckets[0].Selected)
+	assert.Nil(t, buckets[len(buckets)-1].Max, "last bucket is open-ended")
+
+	open := 500.0
+	buckets = PriceRangeBuckets(ProductSearchFilters{MinPrice: &open})
+	assert.True(t, buckets[len(buckets)-1].Selected)
+
+	*buckets[0].Max = 30
+	assert.Equal(t, 25.0, *buckets[1].Min, "buckets do not share their bounds")
+	assert.Equal(t, 25.0, *PriceRangeBuckets(ProductSearchFilters{})[0].Max)
+}
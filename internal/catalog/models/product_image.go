@@ -0,0 +1,79 @@
+package models
+
+import (
+	"fmt"
+	"time"
+)
+
+// ProductImage is an image of a product, or of one of its variants, kept in the media
+// storage. Images are displayed in SortOrder; the first one is the main image.
+type ProductImage struct {
+	ID           uint      `json:"id" gorm:"primarykey"`
+	ProductID    uint      `json:"product_id" gorm:"not null;index"`
+	VariantID    *uint     `json:"variant_id" gorm:"index"`
+	StorageKey   string    `json:"-" gorm:"not null"`
+	ThumbnailKey string    `json:"-"`
+	URL          string    `json:"url" gorm:"-"`           // Filled by WithURLs
+	ThumbnailURL string    `json:"thumbnail_url" gorm:"-"` // Filled by WithURLs
+	ContentType  string    `json:"content_type" gorm:"not null"`
+	Size         int64     `json:"size"` // Bytes
+	Width        int       `json:"width"`
+	Height       int       `json:"height"`
+	AltText      string    `json:"alt_text"`
+	SortOrder    int       `json:"sort_order" gorm:"default:0"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+// WithURLs fills the URL fields from the storage keys
+func (i *ProductImage) WithURLs(url func(key string) string) *ProductImage {
+	i.URL = url(i.StorageKey)
+	if i.ThumbnailKey != "" {
+		i.ThumbnailURL = url(i.ThumbnailKey)
+	}
+	return i
+}
+
+// Validate validates product image business rules
+func (i *ProductImage) Validate() error {
+	if len(i.AltText) > 255 {
+		return fmt.Errorf("alt text cannot exceed 255 characters")
+	}
+
+	return nil
+}
+
+// UpdateProductImageRequest represents the request to update a product image
+type UpdateProductImageRequest struct {
+	AltText *string `json:"alt_text"`
+}
+
+// ReorderImagesRequest lists all images of a product in their new order
+type ReorderImagesRequest struct {
+	ImageIDs []uint `json:"image_ids" binding:"required"`
+}
+
+// Validate checks that the request lists exactly the current images, once each
+func (r *ReorderImagesRequest) Validate(currentIDs []uint) error {
+	current := make(map[uint]bool, len(currentIDs))
+	for _, id := range currentIDs {
+		current[id] = true
+	}
+
+	seen := make(map[uint]bool, len(r.ImageIDs))
+	for _, id := range r.ImageIDs {
+		if !current[id] {
+			return fmt.Errorf("image %d is not an image of this product", id)
+		}
+		if seen[id] {
+			return fmt.Errorf("image %d is listed more than once", id)
+		}
+		seen[id] = true
+	}
+
+	if len(seen) != len(current) {
+		return fmt.Errorf("image_ids must list all %d images of the product", len(current))
+	}
+
+	return nil
+}
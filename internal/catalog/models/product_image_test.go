@@ -0,0 +1,29 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReorderImagesRequestValidate(t *testing.T) {
+	req := ReorderImagesRequest{ImageIDs: []uint{3, 1, 2}}
+	assert.NoError(t, req.Validate([]uint{1, 2, 3}))
+
+	req.ImageIDs = []uint{3, 1}
+	assert.Error(t, req.Validate([]uint{1, 2, 3}), "all images must be listed")
+
+	req.ImageIDs = []uint{3, 1, 1}
+	assert.Error(t, req.Validate([]uint{1, 2, 3}))
+
+	req.ImageIDs = []uint{3, 1, 4}
+	assert.Error(t, req.Validate([]uint{1, 2, 3}))
+}
+
+func TestProductImageWithURLs(t *testing.T) {
+	image := ProductImage{StorageKey: "products/1/a.jpg", ThumbnailKey: "products/1/a_thumb.jpg"}
+	image.WithURLs(func(key string) string { return "/media/" + key })
+
+	assert.Equal(t, "/media/products/1/a.jpg", image.URL)
+	assert.Equal(t, "/media/products/1/a_thumb.jpg", image.ThumbnailURL)
+}
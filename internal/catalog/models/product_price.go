@@ -0,0 +1,77 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// PriceChangeStatus is the status of a product price change
+type PriceChangeStatus string
+
+const (
+	PriceChangeScheduled PriceChangeStatus = "scheduled" // Future change, applied by the price scheduler
+	PriceChangeApplied   PriceChangeStatus = "applied"   // Past or current price
+	PriceChangeCancelled PriceChangeStatus = "cancelled" // Scheduled change that will not be applied
+)
+
+// IsValidPriceChangeStatus checks if the price change status is valid
+func IsValidPriceChangeStatus(status PriceChangeStatus) bool {
+	switch status {
+	case PriceChangeScheduled, PriceChangeApplied, PriceChangeCancelled:
+		return true
+	default:
+		return false
+	}
+}
+
+// ProductPrice is an entry of the price history of a product. Applied prices follow each
+// other: a price is effective from EffectiveFrom until EffectiveTo (nil for the current one).
+type ProductPrice struct {
+	ID            uint              `json:"id" gorm:"primarykey"`
+	ProductID     uint              `json:"product_id" gorm:"not null;index"`
+	Price         float64           `json:"price" gorm:"not null"`
+	Currency      string            `json:"currency" gorm:"default:'USD'"`
+	EffectiveFrom time.Time         `json:"effective_from" gorm:"not null"`
+	EffectiveTo   *time.Time        `json:"effective_to"`
+	Status        PriceChangeStatus `json:"status" gorm:"default:'applied'"`
+	Reason        string            `json:"reason"`
+	AppliedAt     *time.Time        `json:"applied_at"`
+	CreatedAt     time.Time         `json:"created_at"`
+	UpdatedAt     time.Time         `json:"updated_at"`
+}
+
+// IsCurrent reports whether this is the price applying right now
+func (p *ProductPrice) IsCurrent() bool {
+	return p.Status == PriceChangeApplied && p.EffectiveTo == nil
+}
+
+// SchedulePriceChangeRequest represents the request to schedule a future price change
+type SchedulePriceChangeRequest struct {
+	Price         *float64  `json:"price" binding:"required,min=0"`
+	Currency      string    `json:"currency"` // Must be the product currency when set
+	EffectiveFrom time.Time `json:"effective_from" binding:"required"`
+	Reason        string    `json:"reason"`
+}
+
+// Validate validates the schedule price change request. Scheduled prices are in the
+// product currency: applying them never switches the currency of the product.
+func (r *SchedulePriceChangeRequest) Validate(now time.Time, productCurrency string) error {
+	if !r.EffectiveFrom.After(now) {
+		return fmt.Errorf("effective_from must be in the future")
+	}
+
+	if r.Currency != "" && len(r.Currency) != 3 {
+		return fmt.Errorf("currency must be a 3-letter code")
+	}
+
+	if r.Currency != "" && !strings.EqualFold(r.Currency, productCurrency) {
+		return fmt.Errorf("scheduled prices must be in the product currency (%s)", productCurrency)
+	}
+
+	if len(r.Reason) > 500 {
+		return fmt.Errorf("reason cannot exceed 500 characters")
+	}
+
+	return nil
+}
@@ -0,0 +1,41 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSchedulePriceChangeRequestValidate(t *testing.T) {
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	price := 19.99
+
+	req := SchedulePriceChangeRequest{Price: &price, EffectiveFrom: now.Add(time.Hour)}
+	assert.NoError(t, req.Validate(now, "USD"))
+
+	req.EffectiveFrom = now
+	assert.Error(t, req.Validate(now, "USD"), "changes must be in the future")
+
+	req.EffectiveFrom = now.Add(time.Hour)
+	req.Currency = "EURO"
+	assert.Error(t, req.Validate(now, "USD"))
+
+	req.Currency = "usd"
+	assert.NoError(t, req.Validate(now, "USD"))
+	req.Currency = "EUR"
+	assert.Error(t, req.Validate(now, "USD"), "the product currency is not switched")
+}
+
+func TestIsValidPriceChangeStatus(t *testing.T) {
+	assert.True(t, IsValidPriceChangeStatus(PriceChangeScheduled))
+	assert.False(t, IsValidPriceChangeStatus("pending"))
+}
+
+func TestProductPriceIsCurrent(t *testing.T) {
+	closed := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	assert.True(t, (&ProductPrice{Status: PriceChangeApplied}).IsCurrent())
+	assert.False(t, (&ProductPrice{Status: PriceChangeApplied, EffectiveTo: &closed}).IsCurrent())
+	assert.False(t, (&ProductPrice{Status: PriceChangeScheduled}).IsCurrent())
+}
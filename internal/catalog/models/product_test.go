@@ -0,0 +1,65 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProductTransitionTo(t *testing.T) {
+	product := Product{Status: ProductStatusDraft}
+
+	assert.Error(t, product.TransitionTo(ProductStatusInactive), "draft must be activated first")
+	require.NoError(t, product.TransitionTo(ProductStatusActive))
+	assert.Equal(t, ProductStatusActive, product.Status)
+
+	require.NoError(t, product.TransitionTo(ProductStatusInactive))
+	require.NoError(t, product.TransitionTo(ProductStatusActive))
+	assert.Error(t, product.TransitionTo(ProductStatusDraft), "products cannot go back to draft")
+
+	require.NoError(t, product.TransitionTo(ProductStatusDiscontinued))
+	assert.Error(t, product.TransitionTo(ProductStatusActive), "discontinued is final")
+	assert.Empty(t, product.AllowedTransitions())
+	require.NoError(t, product.TransitionTo(ProductStatusDiscontinued), "keeping the status is allowed")
+
+	assert.Error(t, product.TransitionTo("archived"))
+}
+
+func TestProductCanBeSold(t *testing.T) {
+	for status, sellable := range map[ProductStatus]bool{
+		ProductStatusDraft:        false,
+		ProductStatusActive:       true,
+		ProductStatusInactive:     false,
+		ProductStatusDiscontinued: false,
+	} {
+		product := Product{Status: status}
+		assert.Equal(t, sellable, product.CanBeSold(), string(status))
+	}
+}
+
+func TestCreateProductRequestValidateStatus(t *testing.T) {
+	req := CreateProductRequest{SKU: "SKU-1", Name: "Widget", Price: 10, Status: ProductStatusDraft}
+	assert.NoError(t, req.Validate())
+
+	req.Status = ProductStatusDiscontinued
+	assert.Error(t, req.Validate())
+}
+
+func TestProductRequestsMapIsActive(t *testing.T) {
+	inactive := false
+	create := CreateProductRequest{Name: "Widget", Price: 10, IsActive: &inactive}
+	require.NoError(t, create.Validate())
+	assert.Equal(t, ProductStatusInactive, create.Status)
+
+	create = CreateProductRequest{Name: "Widget", Price: 10, Status: ProductStatusActive, IsActive: &inactive}
+	assert.Error(t, create.Validate(), "is_active and status disagree")
+
+	active := true
+	update := UpdateProductRequest{IsActive: &active}
+	require.NoError(t, update.Validate())
+	assert.Equal(t, ProductStatusActive, update.Status)
+
+	product := Product{Status: ProductStatusActive}
+	assert.NoError(t, product.TransitionTo(update.Status), "already active")
+}
@@ -0,0 +1,213 @@
+package models
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// DefaultSKUPattern is used when product.sku_pattern is not configured, e.g. SKU-SHO-0004275
+const DefaultSKUPattern = "{prefix}-{category}-{seq:6}{check}"
+
+// DefaultSKUPrefix is used when product.sku_prefix is not configured
+const DefaultSKUPrefix = "SKU"
+
+// maxSKUSequenceWidth bounds {seq:<width>}; wider sequences could not fit in a SKU anyway
+const maxSKUSequenceWidth = 18
+
+// MaxSKULength is the longest SKU accepted for products
+const MaxSKULength = 50
+
+// skuPatternToken matches the placeholders of a SKU pattern: {prefix}, {category},
+// {seq} or {seq:<width>} and {check}
+var skuPatternToken = regexp.MustCompile(`\{(prefix|category|seq(?::(\d+))?|check)\}`)
+
+// categoryCodePattern matches category codes used in generated SKUs
+var categoryCodePattern = regexp.MustCompile(`^[A-Z0-9]{1,10}$`)
+
+// skuPrefixPattern matches the configured SKU prefix
+var skuPrefixPattern = regexp.MustCompile(`^[A-Z0-9._-]{1,20}$`)
+
+// SKUParts are the values a SKU pattern is filled with
+type SKUParts struct {
+	Prefix       string
+	CategoryCode string
+	Sequence     int64
+}
+
+// ValidateSKUPattern checks that a pattern only uses known placeholders and contains the
+// sequence exactly once, so every allocated sequence number gives a distinct SKU
+func ValidateSKUPattern(pattern string) error {
+	if strings.Count(pattern, "{seq") != 1 {
+		return fmt.Errorf("SKU pattern must contain {seq} exactly once")
+	}
+	if strings.Count(pattern, "{check}") > 1 {
+		return fmt.Errorf("SKU pattern can contain {check} only once")
+	}
+
+	for _, match := range skuPatternToken.FindAllStringSubmatch(pattern, -1) {
+		if match[2] == "" {
+			continue
+		}
+		if width, err := strconv.Atoi(match[2]); err != nil || width < 1 || width > maxSKUSequenceWidth {
+			return fmt.Errorf("SKU sequence width must be between 1 and %d", maxSKUSequenceWidth)
+		}
+	}
+
+	rest := skuPatternToken.ReplaceAllString(pattern, "")
+	if strings.ContainsAny(rest, "{}") {
+		return fmt.Errorf("SKU pattern contains an unknown placeholder: %s", pattern)
+	}
+	for _, r := range rest {
+		if !(r >= 'A' && r <= 'Z') && !(r >= 'a' && r <= 'z') && !(r >= '0' && r <= '9') && r != '-' && r != '_' && r != '.' {
+			return fmt.Errorf("SKU pattern can only contain letters, digits, '-', '_' and '.' outside placeholders")
+		}
+	}
+
+	return nil
+}
+
+// ValidateSKUSettings checks the configured SKU pattern and prefix by formatting a sample
+// SKU with the longest category code, so a configuration that cannot produce valid SKUs
+// is rejected at startup instead of failing every product creation
+func ValidateSKUSettings(pattern, prefix string) error {
+	if !skuPrefixPattern.MatchString(prefix) {
+		return fmt.Errorf("SKU prefix must be 1 to 20 uppercase letters, digits, '-', '_' or '.'")
+	}
+
+	_, err := FormatSKU(pattern, SKUParts{Prefix: prefix, CategoryCode: "XXXXXXXXXX", Sequence: 1})
+	return err
+}
+
+// FormatSKU fills a SKU pattern. The check digit is the Luhn check digit of the
+// zero-padded sequence, so a mistyped sequence number is detected.
+func FormatSKU(pattern string, parts SKUParts) (string, error) {
+	if err := ValidateSKUPattern(pattern); err != nil {
+		return "", err
+	}
+	if parts.Sequence <= 0 {
+		return "", fmt.Errorf("SKU sequence must be positive")
+	}
+
+	var sequence string
+	sku := skuPatternToken.ReplaceAllStringFunc(pattern, func(token string) string {
+		match := skuPatternToken.FindStringSubmatch(token)
+		switch match[1] {
+		case "prefix":
+			return parts.Prefix
+		case "category":
+			return parts.CategoryCode
+		case "check":
+			return "{check}" // Filled once the sequence is known
+		default:
+			width, _ := strconv.Atoi(match[2])
+			sequence = fmt.Sprintf("%0*d", width, parts.Sequence)
+			return sequence
+		}
+	})
+	sku = strings.ReplaceAll(sku, "{check}", strconv.Itoa(LuhnCheckDigit(sequence)))
+
+	if len(sku) > MaxSKULength {
+		return "", fmt.Errorf("generated SKU %q exceeds %d characters", sku, MaxSKULength)
+	}
+	return sku, nil
+}
+
+// SKUSequenceScope returns the name of the sequence SKUs are allocated from: one
+// sequence per prefix, and per category code when the pattern includes it
+func SKUSequenceScope(pattern string, parts SKUParts) string {
+	if strings.Contains(pattern, "{category}") {
+		return parts.Prefix + "/" + parts.CategoryCode
+	}
+	return parts.Prefix
+}
+
+// CategorySKUCode returns the code of a category used in generated SKUs: its code when
+// set, otherwise the first three letters or digits of its name. Products without a
+// category use "GEN".
+func CategorySKUCode(category *Category) string {
+	if category == nil {
+		return "GEN"
+	}
+	if category.Code != "" {
+		return category.Code
+	}
+
+	var code strings.Builder
+	for _, r := range strings.ToUpper(category.Name) {
+		if (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') {
+			code.WriteRune(r)
+			if code.Len() == 3 {
+				break
+			}
+		}
+	}
+	if code.Len() == 0 {
+		return "GEN"
+	}
+	return code.String()
+}
+
+// ValidateCategoryCode checks a category code: 1 to 10 uppercase letters or digits
+func ValidateCategoryCode(code string) error {
+	if !categoryCodePattern.MatchString(code) {
+		return fmt.Errorf("category code must be 1 to 10 uppercase letters or digits")
+	}
+	return nil
+}
+
+// LuhnCheckDigit returns the Luhn (mod 10) check digit of a string of digits
+func LuhnCheckDigit(digits string) int {
+	sum := 0
+	double := true // The check digit is appended, so the rightmost digit is doubled
+	for i := len(digits) - 1; i >= 0; i-- {
+		digit := int(digits[i] - '0')
+		if double {
+			digit *= 2
+			if digit > 9 {
+				digit -= 9
+			}
+		}
+		sum += digit
+		double = !double
+	}
+	return (10 - sum%10) % 10
+}
+
+// ValidateGTIN checks a GTIN barcode (GTIN-8, UPC-A/GTIN-12, EAN-13/GTIN-13 or GTIN-14):
+// digits only, a valid length and a correct GS1 check digit
+func ValidateGTIN(gtin string) error {
+	switch len(gtin) {
+	case 8, 12, 13, 14:
+	default:
+		return fmt.Errorf("GTIN must have 8, 12, 13 or 14 digits")
+	}
+
+	for _, r := range gtin {
+		if r < '0' || r > '9' {
+			return fmt.Errorf("GTIN must contain digits only")
+		}
+	}
+
+	body, check := gtin[:len(gtin)-1], int(gtin[len(gtin)-1]-'0')
+	if expected := GTINCheckDigit(body); check != expected {
+		return fmt.Errorf("invalid GTIN check digit: expected %d", expected)
+	}
+
+	return nil
+}
+
+// GTINCheckDigit returns the GS1 check digit of a GTIN without its check digit: digits
+// are weighted 3 and 1 alternately from the right
+func GTINCheckDigit(body string) int {
+	sum := 0
+	for i := len(body) - 1; i >= 0; i-- {
+		digit := int(body[i] - '0')
+		if (len(body)-1-i)%2 == 0 {
+			digit *= 3
+		}
+		sum += digit
+	}
+	return (10 - sum%10) % 10
+}
@@ -0,0 +1,79 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFormatSKU(t *testing.T) {
+	sku, err := FormatSKU(DefaultSKUPattern, SKUParts{Prefix: "SKU", CategoryCode: "SHO", Sequence: 427})
+	require.NoError(t, err)
+	assert.Equal(t, "SKU-SHO-0004275", sku)
+
+	sku, err = FormatSKU("{prefix}{seq}", SKUParts{Prefix: "P", Sequence: 1234567})
+	require.NoError(t, err)
+	assert.Equal(t, "P1234567", sku, "the sequence is not padded without a width")
+
+	_, err = FormatSKU(DefaultSKUPattern, SKUParts{Prefix: "SKU", CategoryCode: "SHO"})
+	assert.Error(t, err, "sequences start at 1")
+}
+
+func TestValidateSKUPattern(t *testing.T) {
+	assert.NoError(t, ValidateSKUPattern(DefaultSKUPattern))
+	assert.NoError(t, ValidateSKUPattern("{category}.{seq:8}"))
+
+	assert.Error(t, ValidateSKUPattern("{prefix}-{category}"), "the sequence is required")
+	assert.Error(t, ValidateSKUPattern("{seq}-{seq}"), "the sequence is used once")
+	assert.Error(t, ValidateSKUPattern("{seq}{check}{check}"))
+	assert.Error(t, ValidateSKUPattern("{brand}-{seq}"), "unknown placeholder")
+	assert.Error(t, ValidateSKUPattern("{prefix} {seq}"), "spaces are not allowed")
+	assert.Error(t, ValidateSKUPattern("{seq:0}"))
+	assert.Error(t, ValidateSKUPattern("{seq:99999999999999999999}"), "the width is bounded")
+}
+
+func TestValidateSKUSettings(t *testing.T) {
+	assert.NoError(t, ValidateSKUSettings(DefaultSKUPattern, DefaultSKUPrefix))
+
+	assert.Error(t, ValidateSKUSettings(DefaultSKUPattern, "SKU 1"), "invalid prefix")
+	assert.Error(t, ValidateSKUSettings(DefaultSKUPattern, "sku"), "prefixes are uppercase")
+	assert.Error(t, ValidateSKUSettings("{prefix}-{category}-{seq:18}-{prefix}-{prefix}", "PREFIXPREFIX"), "sample SKU too long")
+}
+
+func TestSKUSequenceScope(t *testing.T) {
+	parts := SKUParts{Prefix: "SKU", CategoryCode: "SHO"}
+	assert.Equal(t, "SKU/SHO", SKUSequenceScope(DefaultSKUPattern, parts))
+	assert.Equal(t, "SKU", SKUSequenceScope("{prefix}-{seq:6}", parts))
+}
+
+func TestCategorySKUCode(t *testing.T) {
+	assert.Equal(t, "GEN", CategorySKUCode(nil))
+	assert.Equal(t, "SHOES", CategorySKUCode(&Category{Name: "Shoes", Code: "SHOES"}))
+	assert.Equal(t, "TSH", CategorySKUCode(&Category{Name: "T-shirts"}))
+	assert.Equal(t, "GEN", CategorySKUCode(&Category{Name: "--"}))
+}
+
+func TestValidateCategoryCode(t *testing.T) {
+	assert.NoError(t, ValidateCategoryCode("SHO"))
+	assert.Error(t, ValidateCategoryCode("sho"))
+	assert.Error(t, ValidateCategoryCode("SHO-1"))
+	assert.Error(t, ValidateCategoryCode("ABCDEFGHIJK"))
+}
+
+func TestLuhnCheckDigit(t *testing.T) {
+	assert.Equal(t, 3, LuhnCheckDigit("7992739871"))
+	assert.Equal(t, 0, LuhnCheckDigit("0"))
+}
+
+func TestValidateGTIN(t *testing.T) {
+	assert.NoError(t, ValidateGTIN("4006381333931"), "EAN-13")
+	assert.NoError(t, ValidateGTIN("036000291452"), "UPC-A")
+	assert.NoError(t, ValidateGTIN("96385074"), "GTIN-8")
+	assert.NoError(t, ValidateGTIN("10036000291459"), "GTIN-14")
+
+	assert.Error(t, ValidateGTIN("4006381333932"), "wrong check digit")
+	assert.Error(t, ValidateGTIN("400638133393"), "wrong check digit for 12 digits")
+	assert.Error(t, ValidateGTIN("40063813339"), "invalid length")
+	assert.Error(t, ValidateGTIN("40063813339A1"), "digits only")
+}
@@ -0,0 +1,188 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ErrStockManagedByInventory is returned when a variant stock quantity is set directly
+var ErrStockManagedByInventory = errors.New("stock_quantity is managed through inventory movements")
+
+// ProductVariant is a sellable variation of a product (size, color, ...) with its own SKU.
+// Its price is the product price plus PriceAdjustment.
+type ProductVariant struct {
+	ID              uint              `json:"id" gorm:"primarykey"`
+	ProductID       uint              `json:"product_id" gorm:"not null;index"`
+	SKU             string            `json:"sku" gorm:"uniqueIndex;not null"`
+	GTIN            string            `json:"gtin,omitempty"` // EAN/UPC barcode
+	Name            string            `json:"name" gorm:"not null"`
+	PriceAdjustment float64           `json:"price_adjustment" gorm:"default:0"`
+	EffectivePrice  float64           `json:"effective_price" gorm:"-"`        // Filled from the product price, see WithEffectivePrice
+	StockQuantity   int               `json:"stock_quantity" gorm:"default:0"` // Available units across active warehouses, maintained by the inventory
+	Attributes      VariantAttributes `json:"attributes" gorm:"type:jsonb"`
+	IsActive        bool              `json:"is_active" gorm:"not null"` // Always set on insert
+	CreatedAt       time.Time         `json:"created_at"`
+	UpdatedAt       time.Time         `json:"updated_at"`
+	DeletedAt       gorm.DeletedAt    `json:"deleted_at,omitempty" gorm:"index"`
+}
+
+// VariantAttributes are the attributes distinguishing a variant, e.g. {"size": "M", "color": "red"}.
+// They are stored as a JSONB object.
+type VariantAttributes map[string]string
+
+// Value implements driver.Valuer
+func (a VariantAttributes) Value() (driver.Value, error) {
+	if a == nil {
+		return "{}", nil
+	}
+	data, err := json.Marshal(a)
+	return string(data), err
+}
+
+// Scan implements sql.Scanner
+func (a *VariantAttributes) Scan(src interface{}) error {
+	var data []byte
+	switch v := src.(type) {
+	case nil:
+		*a = VariantAttributes{}
+		return nil
+	case []byte:
+		data = v
+	case string:
+		data = []byte(v)
+	default:
+		return fmt.Errorf("cannot scan %T into VariantAttributes", src)
+	}
+	return json.Unmarshal(data, a)
+}
+
+// String formats the attributes in the key=value,key=value form accepted by ParseVariantAttributes
+func (a VariantAttributes) String() string {
+	keys := make([]string, 0, len(a))
+	for key := range a {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	pairs := make([]string, len(keys))
+	for i, key := range keys {
+		pairs[i] = key + "=" + a[key]
+	}
+	return strings.Join(pairs, ",")
+}
+
+// ParseVariantAttributes parses an attribute filter such as "size=M,color=red".
+// Keys are lowercased; values are kept as given.
+func ParseVariantAttributes(value string) (VariantAttributes, error) {
+	attributes := VariantAttributes{}
+	for _, pair := range strings.Split(value, ",") {
+		if strings.TrimSpace(pair) == "" {
+			continue
+		}
+		key, val, found := strings.Cut(pair, "=")
+		key = strings.ToLower(strings.TrimSpace(key))
+		if !found || key == "" {
+			return nil, fmt.Errorf("invalid attribute %q, expected key=value", pair)
+		}
+		attributes[key] = strings.TrimSpace(val)
+	}
+	return attributes, nil
+}
+
+// normalizeVariantAttributes lowercases and trims attribute keys and trims values
+func normalizeVariantAttributes(attributes VariantAttributes) (VariantAttributes, error) {
+	normalized := VariantAttributes{}
+	for key, value := range attributes {
+		key = strings.ToLower(strings.TrimSpace(key))
+		if key == "" {
+			return nil, fmt.Errorf("attribute names cannot be empty")
+		}
+		if len(key) > 50 || len(value) > 100 {
+			return nil, fmt.Errorf("attribute %q is too long", key)
+		}
+		normalized[key] = strings.TrimSpace(value)
+	}
+	return normalized, nil
+}
+
+// CalculateEffectivePrice returns the variant price for the given product base price
+func (v *ProductVariant) CalculateEffectivePrice(basePrice float64) float64 {
+	return math.Round((basePrice+v.PriceAdjustment)*100) / 100
+}
+
+// WithEffectivePrice fills EffectivePrice from the product base price
+func (v *ProductVariant) WithEffectivePrice(basePrice float64) *ProductVariant {
+	v.EffectivePrice = v.CalculateEffectivePrice(basePrice)
+	return v
+}
+
+// Validate validates variant business rules against the product base price
+func (v *ProductVariant) Validate(basePrice float64) error {
+	if strings.TrimSpace(v.Name) == "" {
+		return fmt.Errorf("variant name is required")
+	}
+
+	if len(v.Name) > 255 {
+		return fmt.Errorf("variant name cannot exceed 255 characters")
+	}
+
+	if strings.TrimSpace(v.SKU) == "" {
+		return fmt.Errorf("variant SKU is required")
+	}
+
+	if len(v.SKU) > 100 {
+		return fmt.Errorf("variant SKU cannot exceed 100 characters")
+	}
+
+	if v.GTIN != "" {
+		if err := ValidateGTIN(v.GTIN); err != nil {
+			return err
+		}
+	}
+
+	if v.StockQuantity < 0 {
+		return fmt.Errorf("variant stock quantity cannot be negative")
+	}
+
+	if v.CalculateEffectivePrice(basePrice) < 0 {
+		return fmt.Errorf("variant price adjustment cannot make the price negative")
+	}
+
+	attributes, err := normalizeVariantAttributes(v.Attributes)
+	if err != nil {
+		return err
+	}
+	v.Attributes = attributes
+
+	return nil
+}
+
+// CreateVariantRequest represents the request to create a product variant
+type CreateVariantRequest struct {
+	SKU             string            `json:"sku" binding:"required"`
+	GTIN            string            `json:"gtin"`
+	Name            string            `json:"name" binding:"required"`
+	PriceAdjustment float64           `json:"price_adjustment"`
+	StockQuantity   *int              `json:"stock_quantity"` // Rejected: stock is managed through inventory movements
+	Attributes      VariantAttributes `json:"attributes"`
+	IsActive        *bool             `json:"is_active"`
+}
+
+// UpdateVariantRequest represents the request to update a product variant
+type UpdateVariantRequest struct {
+	SKU             string            `json:"sku"`
+	GTIN            *string           `json:"gtin"` // Empty removes the barcode
+	Name            string            `json:"name"`
+	PriceAdjustment *float64          `json:"price_adjustment"`
+	StockQuantity   *int              `json:"stock_quantity"` // Rejected: stock is managed through inventory movements
+	Attributes      VariantAttributes `json:"attributes"`     // Replaces all attributes when provided
+	IsActive        *bool             `json:"is_active"`
+}
@@ -0,0 +1,35 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseVariantAttributes(t *testing.T) {
+	attributes, err := ParseVariantAttributes("Size=M, color=red")
+	require.NoError(t, err)
+	assert.Equal(t, VariantAttributes{"size": "M", "color": "red"}, attributes)
+	assert.Equal(t, "color=red,size=M", attributes.String())
+
+	_, err = ParseVariantAttributes("size")
+	assert.Error(t, err)
+
+	attributes, err = ParseVariantAttributes("")
+	require.NoError(t, err)
+	assert.Empty(t, attributes)
+}
+
+func TestProductVariant_Validate(t *testing.T) {
+	variant := ProductVariant{SKU: "TSHIRT-M-RED", Name: "T-shirt M red", PriceAdjustment: -5.5, Attributes: VariantAttributes{" Size ": " M "}}
+
+	require.NoError(t, variant.Validate(20))
+	assert.Equal(t, VariantAttributes{"size": "M"}, variant.Attributes)
+	assert.Equal(t, 14.5, variant.WithEffectivePrice(20).EffectivePrice)
+
+	assert.Error(t, variant.Validate(5), "adjustment cannot make the price negative")
+
+	variant.StockQuantity = -1
+	assert.Error(t, variant.Validate(20))
+}
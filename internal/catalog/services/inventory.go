@@ -0,0 +1,222 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"gaetanjaminon/GoTuto/internal/catalog/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// LockStockLevel returns the stock level of a product or variant in a warehouse, creating
+// it when missing, locked for the rest of the transaction
+func LockStockLevel(tx *gorm.DB, warehouseID, productID uint, variantID *uint) (models.StockLevel, error) {
+	level := models.StockLevel{WarehouseID: warehouseID, ProductID: productID, VariantID: variantID}
+	if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&level).Error; err != nil {
+		return level, err
+	}
+
+	query := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("warehouse_id = ? AND product_id = ?", warehouseID, productID)
+	if variantID == nil {
+		query = query.Where("variant_id IS NULL")
+	} else {
+		query = query.Where("variant_id = ?", *variantID)
+	}
+
+	level = models.StockLevel{}
+	err := query.First(&level).Error
+	return level, err
+}
+
+// ApplyStockMovement changes the on-hand stock of a locked stock level by the movement
+// quantity and records the movement. Stock never goes below the reserved units: the
+// update is conditional and the table constraints reject negative stock.
+func ApplyStockMovement(tx *gorm.DB, level *models.StockLevel, movement *models.StockMovement) error {
+	if level.AvailableQuantity()+movement.Quantity < 0 {
+		return models.ErrInsufficientStock
+	}
+
+	result := tx.Model(&models.StockLevel{}).
+		Where("id = ? AND on_hand - reserved + ? >= 0", level.ID, movement.Quantity).
+		Updates(map[string]interface{}{"on_hand": gorm.Expr("on_hand + ?", movement.Quantity), "updated_at": time.Now()})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return models.ErrInsufficientStock
+	}
+	level.OnHand += movement.Quantity
+
+	movement.StockLevelID = level.ID
+	movement.OnHandAfter = level.OnHand
+	if err := tx.Create(movement).Error; err != nil {
+		return err
+	}
+
+	return syncVariantStock(tx, level.VariantID)
+}
+
+// ReserveStock holds units of a locked stock level for a reservation
+func ReserveStock(tx *gorm.DB, level *models.StockLevel, reservation *models.StockReservation) error {
+	result := tx.Model(&models.StockLevel{}).
+		Where("id = ? AND on_hand - reserved >= ?", level.ID, reservation.Quantity).
+		Updates(map[string]interface{}{"reserved": gorm.Expr("reserved + ?", reservation.Quantity), "updated_at": time.Now()})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return models.ErrInsufficientStock
+	}
+	level.Reserved += reservation.Quantity
+
+	reservation.StockLevelID = level.ID
+	reservation.Status = models.ReservationActive
+	if err := tx.Create(reservation).Error; err != nil {
+		return err
+	}
+
+	return syncVariantStock(tx, level.VariantID)
+}
+
+// ReleaseReservation gives the units of an active reservation back to the available
+// stock, leaving it with the given status (released or expired)
+func ReleaseReservation(tx *gorm.DB, reservation *models.StockReservation, status models.ReservationStatus) error {
+	level, err := lockReservation(tx, reservation)
+	if err != nil {
+		return err
+	}
+
+	if err := tx.Model(&models.StockLevel{}).Where("id = ?", level.ID).
+		Updates(map[string]interface{}{"reserved": gorm.Expr("reserved - ?", reservation.Quantity), "updated_at": time.Now()}).Error; err != nil {
+		return err
+	}
+	if err := tx.Model(reservation).Update("status", status).Error; err != nil {
+		return err
+	}
+
+	return syncVariantStock(tx, level.VariantID)
+}
+
+// FulfillReservation turns an active reservation into a sale: the reserved units leave
+// the stock and a sale movement is recorded
+func FulfillReservation(tx *gorm.DB, reservation *models.StockReservation, reference string) (models.StockMovement, error) {
+	movement := models.StockMovement{
+		Type:          models.StockMovementSale,
+		Quantity:      -reservation.Quantity,
+		ReservationID: &reservation.ID,
+		Reference:     reference,
+	}
+	if movement.Reference == "" {
+		movement.Reference = reservation.Reference
+	}
+
+	level, err := lockReservation(tx, reservation)
+	if err != nil {
+		return movement, err
+	}
+	if !reservation.IsActiveAt(time.Now()) {
+		return movement, models.ErrReservationNotActive
+	}
+
+	// Release the units first, so the sale takes exactly them
+	if err := tx.Model(&models.StockLevel{}).Where("id = ?", level.ID).
+		Update("reserved", gorm.Expr("reserved - ?", reservation.Quantity)).Error; err != nil {
+		return movement, err
+	}
+	level.Reserved -= reservation.Quantity
+
+	if err := ApplyStockMovement(tx, &level, &movement); err != nil {
+		return movement, err
+	}
+
+	return movement, tx.Model(reservation).Update("status", models.ReservationFulfilled).Error
+}
+
+// lockReservation locks an active reservation and its stock level
+func lockReservation(tx *gorm.DB, reservation *models.StockReservation) (models.StockLevel, error) {
+	var level models.StockLevel
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(reservation, reservation.ID).Error; err != nil {
+		return level, err
+	}
+	if reservation.Status != models.ReservationActive {
+		return level, models.ErrReservationNotActive
+	}
+
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&level, reservation.StockLevelID).Error
+	return level, err
+}
+
+// variantStockSQL sets product_variants.stock_quantity to the units available across the
+// active warehouses; stock in inactive or deleted warehouses cannot be sold
+const variantStockSQL = `UPDATE product_variants SET stock_quantity =
+	(SELECT COALESCE(SUM(stock_levels.on_hand - stock_levels.reserved), 0)
+	FROM stock_levels
+	JOIN warehouses ON warehouses.id = stock_levels.warehouse_id
+	WHERE stock_levels.variant_id = product_variants.id
+		AND warehouses.is_active AND warehouses.deleted_at IS NULL)`
+
+// syncVariantStock keeps product_variants.stock_quantity equal to the units of the
+// variant available across all active warehouses
+func syncVariantStock(tx *gorm.DB, variantID *uint) error {
+	if variantID == nil {
+		return nil
+	}
+	return tx.Exec(variantStockSQL+" WHERE id = ?", *variantID).Error
+}
+
+// SyncWarehouseVariantStock recomputes the stock quantity of every variant stocked in a
+// warehouse, after the warehouse was activated or deactivated
+func SyncWarehouseVariantStock(tx *gorm.DB, warehouseID uint) error {
+	return tx.Exec(variantStockSQL+" WHERE id IN (SELECT variant_id FROM stock_levels WHERE warehouse_id = ?)", warehouseID).Error
+}
+
+// ExpireStockReservations releases the active reservations expired at now and returns
+// how many were released
+func ExpireStockReservations(db *gorm.DB, now time.Time) (int, error) {
+	var expired []models.StockReservation
+	err := db.Where("status = ? AND expires_at <= ?", models.ReservationActive, now).
+		Order("expires_at ASC").Find(&expired).Error
+	if err != nil {
+		return 0, err
+	}
+
+	released := 0
+	for i := range expired {
+		err := db.Transaction(func(tx *gorm.DB) error {
+			return ReleaseReservation(tx, &expired[i], models.ReservationExpired)
+		})
+		// Fulfilled or released in the meantime
+		if errors.Is(err, models.ErrReservationNotActive) {
+			continue
+		}
+		if err != nil {
+			return released, err
+		}
+		released++
+	}
+	return released, nil
+}
+
+// RunReservationExpiry releases expired reservations every interval until ctx is done
+func RunReservationExpiry(ctx context.Context, db *gorm.DB, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if released, err := ExpireStockReservations(db.WithContext(ctx), time.Now()); err != nil {
+			log.Printf("Reservation expiry: failed to release reservations: %v", err)
+		} else if released > 0 {
+			log.Printf("Reservation expiry: released %d reservation(s)", released)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,170 @@
+package services
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	_ "image/gif" // Registers the GIF decoder
+	"image/jpeg"
+	"image/png"
+	"net/http"
+)
+
+// MaxImagePixels bounds the decoded size of uploaded images, so a small compressed file
+// cannot expand into gigabytes of memory
+const MaxImagePixels = 40_000_000
+
+// ErrUnsupportedMediaType is returned for uploads that are not JPEG, PNG or GIF images
+var ErrUnsupportedMediaType = errors.New("unsupported media type: only JPEG, PNG and GIF images are accepted")
+
+// imageExtensions maps the accepted sniffed content types to their file extension
+var imageExtensions = map[string]string{
+	"image/jpeg": ".jpg",
+	"image/png":  ".png",
+	"image/gif":  ".gif",
+}
+
+// ProcessedImage is an uploaded image checked and decoded, with its thumbnail
+type ProcessedImage struct {
+	ContentType string
+	Extension   string
+	Width       int
+	Height      int
+
+	Thumbnail            []byte
+	ThumbnailContentType string
+	ThumbnailExtension   string
+}
+
+// ProcessImage sniffs the content type of an uploaded file from its content (the client
+// supplied type is not trusted), decodes it and renders a thumbnail fitting in
+// thumbnailSize x thumbnailSize pixels
+func ProcessImage(data []byte, thumbnailSize int) (ProcessedImage, error) {
+	var processed ProcessedImage
+
+	processed.ContentType = http.DetectContentType(data)
+	extension, ok := imageExtensions[processed.ContentType]
+	if !ok {
+		return processed, ErrUnsupportedMediaType
+	}
+	processed.Extension = extension
+
+	// Check the dimensions from the header before decoding the pixels
+	config, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return processed, fmt.Errorf("%w: %v", ErrUnsupportedMediaType, err)
+	}
+	if config.Width <= 0 || config.Height <= 0 || config.Width*config.Height > MaxImagePixels {
+		return processed, fmt.Errorf("image dimensions %dx%d exceed the %d pixel limit", config.Width, config.Height, MaxImagePixels)
+	}
+	processed.Width, processed.Height = config.Width, config.Height
+
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return processed, fmt.Errorf("%w: %v", ErrUnsupportedMediaType, err)
+	}
+
+	// JPEG thumbnails for photos, PNG keeps the transparency of PNG and GIF images
+	var thumbnail bytes.Buffer
+	if processed.ContentType == "image/jpeg" {
+		err = jpeg.Encode(&thumbnail, Thumbnail(img, thumbnailSize), &jpeg.Options{Quality: 85})
+		processed.ThumbnailContentType, processed.ThumbnailExtension = "image/jpeg", ".jpg"
+	} else {
+		err = png.Encode(&thumbnail, Thumbnail(img, thumbnailSize))
+		processed.ThumbnailContentType, processed.ThumbnailExtension = "image/png", ".png"
+	}
+	if err != nil {
+		return processed, fmt.Errorf("failed to encode thumbnail: %w", err)
+	}
+	processed.Thumbnail = thumbnail.Bytes()
+
+	return processed, nil
+}
+
+// ThumbnailDimensions returns the size of a width x height image scaled down to fit in a
+// maxSize square, keeping its aspect ratio. Smaller images keep their size.
+func ThumbnailDimensions(width, height, maxSize int) (int, int) {
+	if maxSize <= 0 || (width <= maxSize && height <= maxSize) {
+		return width, height
+	}
+
+	if width >= height {
+		return maxSize, max(1, height*maxSize/width)
+	}
+	return max(1, width*maxSize/height), maxSize
+}
+
+// Thumbnail scales img down to fit in a maxSize square. Each thumbnail pixel is the
+// average of the source pixels it covers (box filter), which gives smooth downscaling
+// without dependencies outside the standard library. Every source pixel is read, so the
+// decoded image types are read directly rather than through image.Image.At.
+func Thumbnail(img image.Image, maxSize int) *image.NRGBA {
+	bounds := img.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+	thumbWidth, thumbHeight := ThumbnailDimensions(width, height, maxSize)
+
+	pixel := pixelReader(img)
+	thumb := image.NewNRGBA(image.Rect(0, 0, thumbWidth, thumbHeight))
+	for y := 0; y < thumbHeight; y++ {
+		y0 := bounds.Min.Y + y*height/thumbHeight
+		y1 := max(y0+1, bounds.Min.Y+(y+1)*height/thumbHeight)
+
+		for x := 0; x < thumbWidth; x++ {
+			x0 := bounds.Min.X + x*width/thumbWidth
+			x1 := max(x0+1, bounds.Min.X+(x+1)*width/thumbWidth)
+
+			// Sum premultiplied components, so transparent pixels do not darken edges
+			var r, g, b, a, n uint64
+			for sy := y0; sy < y1; sy++ {
+				for sx := x0; sx < x1; sx++ {
+					pr, pg, pb, pa := pixel(sx, sy)
+					r, g, b, a = r+uint64(pr), g+uint64(pg), b+uint64(pb), a+uint64(pa)
+					n++
+				}
+			}
+
+			thumb.Set(x, y, color.RGBA64{
+				R: uint16(r / n),
+				G: uint16(g / n),
+				B: uint16(b / n),
+				A: uint16(a / n),
+			})
+		}
+	}
+
+	return thumb
+}
+
+// pixelReader returns a function reading the premultiplied 16-bit components of a pixel,
+// like color.Color.RGBA. The image types produced by the JPEG, PNG and GIF decoders are
+// read through their concrete accessors, without boxing a color.Color per pixel.
+func pixelReader(img image.Image) func(x, y int) (r, g, b, a uint32) {
+	switch m := img.(type) {
+	case *image.YCbCr:
+		return func(x, y int) (uint32, uint32, uint32, uint32) { return m.YCbCrAt(x, y).RGBA() }
+	case *image.Gray:
+		return func(x, y int) (uint32, uint32, uint32, uint32) { return m.GrayAt(x, y).RGBA() }
+	case *image.RGBA:
+		return func(x, y int) (uint32, uint32, uint32, uint32) { return m.RGBAAt(x, y).RGBA() }
+	case *image.NRGBA:
+		return func(x, y int) (uint32, uint32, uint32, uint32) { return m.NRGBAAt(x, y).RGBA() }
+	case *image.Paletted:
+		// Convert the palette once instead of every pixel
+		palette := make([][4]uint32, len(m.Palette))
+		for i, c := range m.Palette {
+			palette[i][0], palette[i][1], palette[i][2], palette[i][3] = c.RGBA()
+		}
+		return func(x, y int) (uint32, uint32, uint32, uint32) {
+			index := int(m.Pix[m.PixOffset(x, y)])
+			if index >= len(palette) {
+				return 0, 0, 0, 0
+			}
+			c := palette[index]
+			return c[0], c[1], c[2], c[3]
+		}
+	default:
+		return func(x, y int) (uint32, uint32, uint32, uint32) { return img.At(x, y).RGBA() }
+	}
+}
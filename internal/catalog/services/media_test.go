@@ -0,0 +1,102 @@
+package services
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/png"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func encodePNG(t *testing.T, width, height int) []byte {
+	img := image.NewNRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			img.Set(x, y, color.NRGBA{R: 200, G: 100, B: 50, A: 255})
+		}
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, png.Encode(&buf, img))
+	return buf.Bytes()
+}
+
+func TestThumbnailDimensions(t *testing.T) {
+	width, height := ThumbnailDimensions(1000, 500, 200)
+	assert.Equal(t, 200, width)
+	assert.Equal(t, 100, height)
+
+	width, height = ThumbnailDimensions(300, 1200, 200)
+	assert.Equal(t, 50, width)
+	assert.Equal(t, 200, height)
+
+	width, height = ThumbnailDimensions(120, 80, 200)
+	assert.Equal(t, 120, width, "small images are not upscaled")
+	assert.Equal(t, 80, height)
+
+	width, height = ThumbnailDimensions(5000, 1, 100)
+	assert.Equal(t, 100, width)
+	assert.Equal(t, 1, height, "dimensions never round to zero")
+}
+
+func TestThumbnailAveragesPixels(t *testing.T) {
+	img := image.NewNRGBA(image.Rect(0, 0, 4, 2))
+	for x := 0; x < 4; x++ {
+		img.Set(x, 0, color.NRGBA{R: 255, A: 255})
+		img.Set(x, 1, color.NRGBA{A: 255})
+	}
+
+	thumb := Thumbnail(img, 2)
+	assert.Equal(t, image.Rect(0, 0, 2, 1), thumb.Bounds())
+	assert.InDelta(t, 127, int(thumb.NRGBAAt(0, 0).R), 1)
+	assert.Equal(t, uint8(255), thumb.NRGBAAt(0, 0).A)
+}
+
+func TestPixelReaderMatchesAt(t *testing.T) {
+	rect := image.Rect(0, 0, 3, 2)
+	ycbcr := image.NewYCbCr(rect, image.YCbCrSubsampleRatio420)
+	for i := range ycbcr.Y {
+		ycbcr.Y[i] = uint8(40 * i)
+	}
+	ycbcr.Cb[0], ycbcr.Cr[0] = 90, 200
+	nrgba := image.NewNRGBA(rect)
+	nrgba.Set(1, 1, color.NRGBA{R: 200, G: 100, B: 50, A: 128})
+	paletted := image.NewPaletted(rect, color.Palette{color.Transparent, color.NRGBA{R: 10, G: 20, B: 30, A: 255}})
+	paletted.SetColorIndex(2, 0, 1)
+
+	for _, img := range []image.Image{ycbcr, nrgba, paletted, image.NewGray(rect)} {
+		pixel := pixelReader(img)
+		for y := rect.Min.Y; y < rect.Max.Y; y++ {
+			for x := rect.Min.X; x < rect.Max.X; x++ {
+				r, g, b, a := img.At(x, y).RGBA()
+				pr, pg, pb, pa := pixel(x, y)
+				assert.Equal(t, [4]uint32{r, g, b, a}, [4]uint32{pr, pg, pb, pa}, "%T at %d,%d", img, x, y)
+			}
+		}
+	}
+}
+
+func TestProcessImage(t *testing.T) {
+	processed, err := ProcessImage(encodePNG(t, 600, 300), 100)
+	require.NoError(t, err)
+	assert.Equal(t, "image/png", processed.ContentType)
+	assert.Equal(t, ".png", processed.Extension)
+	assert.Equal(t, 600, processed.Width)
+	assert.Equal(t, 300, processed.Height)
+
+	thumbnail, err := png.Decode(bytes.NewReader(processed.Thumbnail))
+	require.NoError(t, err)
+	assert.Equal(t, image.Rect(0, 0, 100, 50), thumbnail.Bounds())
+}
+
+func TestProcessImageRejectsNonImages(t *testing.T) {
+	_, err := ProcessImage([]byte("<html><body>not an image</body></html>"), 100)
+	assert.ErrorIs(t, err, ErrUnsupportedMediaType)
+
+	// PNG signature with a corrupt body
+	_, err = ProcessImage(append([]byte("\x89PNG\r\n\x1a\n"), make([]byte, 32)...), 100)
+	assert.ErrorIs(t, err, ErrUnsupportedMediaType)
+}
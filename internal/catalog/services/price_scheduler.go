@@ -0,0 +1,107 @@
+package services
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"gaetanjaminon/GoTuto/internal/catalog/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// RecordPriceChange makes price the current price of a product from at, closing the
+// previous price in the history. It must run in the transaction updating the product.
+func RecordPriceChange(tx *gorm.DB, productID uint, price float64, currency string, at time.Time, reason string) (models.ProductPrice, error) {
+	err := tx.Model(&models.ProductPrice{}).
+		Where("product_id = ? AND status = ? AND effective_to IS NULL", productID, models.PriceChangeApplied).
+		Update("effective_to", at).Error
+	if err != nil {
+		return models.ProductPrice{}, err
+	}
+
+	entry := models.ProductPrice{
+		ProductID:     productID,
+		Price:         price,
+		Currency:      currency,
+		EffectiveFrom: at,
+		Status:        models.PriceChangeApplied,
+		Reason:        reason,
+		AppliedAt:     &at,
+	}
+	return entry, tx.Create(&entry).Error
+}
+
+// ApplyDuePriceChanges applies the scheduled price changes due at now, oldest first, and
+// returns how many were applied. Changes of deleted products, or of products whose
+// currency changed since the change was scheduled, are cancelled. Rows are
+// locked with SKIP LOCKED so several service instances can run the scheduler.
+func ApplyDuePriceChanges(db *gorm.DB, now time.Time) (int, error) {
+	applied := 0
+	err := db.Transaction(func(tx *gorm.DB) error {
+		var due []models.ProductPrice
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("status = ? AND effective_from <= ?", models.PriceChangeScheduled, now).
+			Order("effective_from ASC, id ASC").
+			Find(&due).Error
+		if err != nil {
+			return err
+		}
+
+		for _, change := range due {
+			var product models.Product
+			if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&product, change.ProductID).Error; err != nil {
+				if err := tx.Model(&change).Updates(map[string]interface{}{"status": models.PriceChangeCancelled, "reason": "Product no longer exists"}).Error; err != nil {
+					return err
+				}
+				continue
+			}
+
+			if change.Currency != product.Currency {
+				if err := tx.Model(&change).Updates(map[string]interface{}{"status": models.PriceChangeCancelled, "reason": "Product currency changed"}).Error; err != nil {
+					return err
+				}
+				continue
+			}
+
+			if err := tx.Model(&product).Update("price", change.Price).Error; err != nil {
+				return err
+			}
+
+			// The scheduled row becomes the current price
+			err := tx.Model(&models.ProductPrice{}).
+				Where("product_id = ? AND status = ? AND effective_to IS NULL", product.ID, models.PriceChangeApplied).
+				Update("effective_to", change.EffectiveFrom).Error
+			if err != nil {
+				return err
+			}
+			if err := tx.Model(&change).Updates(map[string]interface{}{"status": models.PriceChangeApplied, "applied_at": now}).Error; err != nil {
+				return err
+			}
+			applied++
+		}
+		return nil
+	})
+	return applied, err
+}
+
+// RunPriceScheduler applies due price changes every interval until ctx is done
+func RunPriceScheduler(ctx context.Context, db *gorm.DB, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if applied, err := ApplyDuePriceChanges(db.WithContext(ctx), time.Now()); err != nil {
+			log.Printf("Price scheduler: failed to apply price changes: %v", err)
+		} else if applied > 0 {
+			log.Printf("Price scheduler: applied %d price change(s)", applied)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,59 @@
+package services
+
+import (
+	"fmt"
+
+	"gaetanjaminon/GoTuto/internal/catalog/models"
+
+	"gorm.io/gorm"
+)
+
+// maxSKUAttempts bounds the sequence numbers skipped because their SKU was already
+// entered manually
+const maxSKUAttempts = 20
+
+// NextSKUSequence allocates the next number of a SKU sequence. The sequence row stays
+// locked until the transaction ends, so concurrent allocations never get the same
+// number, and a rolled back product creation gives its number back.
+func NextSKUSequence(tx *gorm.DB, scope string) (int64, error) {
+	var next int64
+	err := tx.Raw(`INSERT INTO sku_sequences (scope, last_value, updated_at) VALUES (?, 1, NOW())
+		ON CONFLICT (scope) DO UPDATE SET last_value = sku_sequences.last_value + 1, updated_at = NOW()
+		RETURNING last_value`, scope).Scan(&next).Error
+	return next, err
+}
+
+// GenerateSKU returns a new SKU following the pattern, from the sequence of its prefix
+// and category. Numbers whose SKU is already used by a product or variant are skipped.
+func GenerateSKU(tx *gorm.DB, pattern string, parts models.SKUParts) (string, error) {
+	scope := models.SKUSequenceScope(pattern, parts)
+
+	for attempt := 0; attempt < maxSKUAttempts; attempt++ {
+		sequence, err := NextSKUSequence(tx, scope)
+		if err != nil {
+			return "", err
+		}
+		parts.Sequence = sequence
+
+		sku, err := models.FormatSKU(pattern, parts)
+		if err != nil {
+			return "", err
+		}
+
+		var count int64
+		if err := tx.Unscoped().Model(&models.Product{}).Where("sku = ?", sku).Count(&count).Error; err != nil {
+			return "", err
+		}
+		if count > 0 {
+			continue
+		}
+		if err := tx.Unscoped().Model(&models.ProductVariant{}).Where("sku = ?", sku).Count(&count).Error; err != nil {
+			return "", err
+		}
+		if count == 0 {
+			return sku, nil
+		}
+	}
+
+	return "", fmt.Errorf("no free SKU in sequence %s after %d attempts", scope, maxSKUAttempts)
+}
@@ -0,0 +1,42 @@
+package storage
+
+import (
+	"context"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLocalStorageSaveOpenDelete(t *testing.T) {
+	ctx := context.Background()
+	store, err := NewLocalStorage(t.TempDir(), "/media/")
+	require.NoError(t, err)
+
+	require.NoError(t, store.Save(ctx, "products/1/image.jpg", strings.NewReader("content")))
+
+	file, err := store.Open(ctx, "products/1/image.jpg")
+	require.NoError(t, err)
+	content, err := io.ReadAll(file)
+	file.Close()
+	require.NoError(t, err)
+	assert.Equal(t, "content", string(content))
+	assert.Equal(t, "/media/products/1/image.jpg", store.URL("products/1/image.jpg"))
+
+	require.NoError(t, store.Delete(ctx, "products/1/image.jpg"))
+	_, err = store.Open(ctx, "products/1/image.jpg")
+	assert.ErrorIs(t, err, ErrNotFound)
+	assert.NoError(t, store.Delete(ctx, "products/1/image.jpg"), "deleting a missing file is not an error")
+}
+
+func TestLocalStorageRejectsInvalidKeys(t *testing.T) {
+	ctx := context.Background()
+	store, err := NewLocalStorage(t.TempDir(), "/media")
+	require.NoError(t, err)
+
+	for _, key := range []string{"", "/etc/passwd", "../outside.jpg", "products/../../outside.jpg", "products/./a.jpg", "products/.hidden", `products\a.jpg`} {
+		assert.ErrorIs(t, store.Save(ctx, key, strings.NewReader("x")), ErrInvalidKey, key)
+	}
+}
@@ -0,0 +1,28 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"io"
+)
+
+// ErrNotFound is returned when no object is stored under a key
+var ErrNotFound = errors.New("storage: object not found")
+
+// ErrInvalidKey is returned for keys that are empty, absolute or escape the storage root
+var ErrInvalidKey = errors.New("storage: invalid key")
+
+// Storage stores media files under slash-separated keys such as "products/12/abc.jpg"
+type Storage interface {
+	// Save stores the content of r under key, replacing any existing object
+	Save(ctx context.Context, key string, r io.Reader) error
+
+	// Open returns the content stored under key, or ErrNotFound
+	Open(ctx context.Context, key string) (io.ReadCloser, error)
+
+	// Delete removes the object stored under key; deleting a missing object is not an error
+	Delete(ctx context.Context, key string) error
+
+	// URL returns the public URL of the object stored under key
+	URL(key string) string
+}
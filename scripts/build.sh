@@ -19,5 +19,8 @@ go build -o bin/billing-migrator ./cmd/billing-migrator
 echo "Building catalog migrator..."
 go build -o bin/catalog-migrator ./cmd/catalog-migrator
 
+echo "Building billing importer..."
+go build -o bin/billing-importer ./cmd/billing-importer
+
 echo "✅ Binaries built successfully:"
 ls -la bin/
\ No newline at end of file